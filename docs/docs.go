@@ -26,7 +26,12 @@ const docTemplate = `{
     "paths": {
         "/add-bot": {
             "post": {
-                "description": "Adds a new Telegram channel for the specified user. The channel is saved with is_verified = false. User must exist in the system.",
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Adds a new Telegram channel for the user. The channel is saved with is_verified = false and checked in the background. From the Mini App the user is taken from initData and ` + "`" + `user_id` + "`" + ` may be omitted. Our bot process may instead sign the request and name the user in ` + "`" + `user_id` + "`" + `: ` + "`" + `X-Signature-Timestamp` + "`" + ` carries the Unix time in seconds, ` + "`" + `X-Signature-Nonce` + "`" + ` a unique request ID of up to 64 letters, digits, ` + "`" + `-` + "`" + ` or ` + "`" + `_` + "`" + `, and ` + "`" + `X-Signature` + "`" + ` the hex HMAC-SHA256 of ` + "`" + `\u003ctimestamp\u003e.\u003cnonce\u003e.\u003cbody\u003e` + "`" + `, keyed with ` + "`" + `SERVICE_AUTH_SECRET` + "`" + `; signatures older than ` + "`" + `SERVICE_AUTH_MAX_SKEW` + "`" + ` and nonces accepted before are rejected. User must exist in the system. Channels are also added automatically, for the user who promoted the bot, when the bot is made an administrator of a channel.",
                 "consumes": [
                     "application/json"
                 ],
@@ -39,7 +44,25 @@ const docTemplate = `{
                 "summary": "Add a new Channel",
                 "parameters": [
                     {
-                        "description": "The user ID, channel title and username to add.",
+                        "type": "string",
+                        "description": "Unix time the request was signed at, for signed requests.",
+                        "name": "X-Signature-Timestamp",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique request ID, for signed requests.",
+                        "name": "X-Signature-Nonce",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hex HMAC-SHA256 of the timestamp, nonce and body, for signed requests.",
+                        "name": "X-Signature",
+                        "in": "header"
+                    },
+                    {
+                        "description": "The channel title and username to add, and the user for signed requests.",
                         "name": "payload",
                         "in": "body",
                         "required": true,
@@ -61,6 +84,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized - The request is neither authenticated with initData nor validly signed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or user_id names another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error - Database error.",
                         "schema": {
@@ -116,44 +151,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/check-channel": {
-            "post": {
+        "/channels/{id}/promo-codes": {
+            "get": {
                 "security": [
                     {
                         "TgAuth": []
                     }
                 ],
-                "description": "Checks if the user is the owner of the specified channel. If yes, sets is_verified = true. If no, deletes the channel.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the live promo codes of one of the user's channels with how many times each was redeemed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tribute"
+                    "Promo Codes"
                 ],
-                "summary": "Check Channel Ownership",
+                "summary": "List Promo Codes",
                 "parameters": [
                     {
-                        "description": "The channel ID to check.",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CheckChannelRequest"
-                        }
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success - Channel ownership check result.",
+                        "description": "Success - The channel's promo codes.",
                         "schema": {
-                            "$ref": "#/definitions/dto.CheckChannelResponse"
+                            "$ref": "#/definitions/dto.PromoCodesResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - The request body is invalid or channel not found.",
+                        "description": "Bad Request - The channel ID is malformed.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -165,74 +195,32 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "description": "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error - Failed to verify channel ownership.",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/check-verified-passport": {
-            "post": {
-                "description": "**PUBLIC ENDPOINT.** This endpoint is intended to be called by Telegram in response to an admin clicking a button in the verification chat. It should not be called directly by the frontend. It processes verification approvals and rejections.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Webhooks"
-                ],
-                "summary": "Telegram Verification Webhook",
-                "parameters": [
-                    {
-                        "description": "The callback query update sent by Telegram.",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.TelegramUpdate"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success - The callback was processed.",
-                        "schema": {
-                            "$ref": "#/definitions/dto.StatusResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request - The payload from Telegram is malformed.",
+                    "404": {
+                        "description": "Not Found - The channel does not exist.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error - Failed to process the callback data.",
+                        "description": "Internal Server Error - An unexpected error occurred.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/create-subscribe": {
+            },
             "post": {
                 "security": [
                     {
                         "TgAuth": []
                     }
                 ],
-                "description": "Allows an authenticated user (the subscriber) to pay for and subscribe to another user (the creator). This action creates a payment record and updates the creator's earnings.",
+                "description": "Adds a discount code to one of the user's channels. The discount is either a percentage (1-99) or a fixed amount off prices in the same currency, and never makes a subscription free. Codes can be limited to one tier, to a number of redemptions in total and per subscriber, and to a validity window. Subscribers enter the code as ` + "`" + `promo_code` + "`" + ` in ` + "`" + `/create-subscribe` + "`" + `; payments that fail don't use up a redemption.",
                 "consumes": [
                     "application/json"
                 ],
@@ -240,29 +228,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tribute"
+                    "Promo Codes"
                 ],
-                "summary": "Subscribe to an Author",
+                "summary": "Create a Promo Code",
                 "parameters": [
                     {
-                        "description": "The ID of the user to subscribe to and the price.",
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The promo code to create.",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateSubscribeRequest"
+                            "$ref": "#/definitions/dto.PromoCodeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created - The subscription was successful.",
+                        "description": "Created - The new promo code.",
                         "schema": {
-                            "$ref": "#/definitions/dto.MessageResponse"
+                            "$ref": "#/definitions/dto.PromoCodeDTO"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - The request body is invalid.",
+                        "description": "Bad Request - The request is invalid, e.g. a malformed code or discount.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -274,13 +269,25 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "description": "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The channel or tier does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - The channel already has a code with this text.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error - e.g., the creator has no subscription tier, or the price is incorrect.",
+                        "description": "Internal Server Error - An unexpected error occurred.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -288,32 +295,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/create-user": {
+        "/channels/{id}/referral-link": {
             "post": {
                 "security": [
                     {
                         "TgAuth": []
                     }
                 ],
-                "description": "Creates a new user if one doesn't exist, otherwise returns the existing user. This endpoint is idempotent and returns dashboard data.",
+                "description": "Returns the user's referral link to another creator's channel, creating it the first time. The link opens the channel's offer in the Mini App; subscribers who pay after opening it are credited to the user, who earns the channel's referral share of their payments. Clicks, conversions and earnings are shown in the ` + "`" + `referrals` + "`" + ` section of the dashboard.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tribute"
+                    "Referrals"
+                ],
+                "summary": "Get a Referral Link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Create User",
                 "responses": {
                     "200": {
-                        "description": "Success - User already existed.",
+                        "description": "Success - The user's referral link to the channel.",
                         "schema": {
-                            "$ref": "#/definitions/dto.DashboardResponse"
+                            "$ref": "#/definitions/dto.StartLinkDTO"
                         }
                     },
-                    "201": {
-                        "description": "Created - A new user was created.",
+                    "400": {
+                        "description": "Bad Request - The channel is the user's own, not verified, or has no referral program.",
                         "schema": {
-                            "$ref": "#/definitions/dto.DashboardResponse"
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "401": {
@@ -328,6 +344,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found - The channel does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error - An unexpected error occurred.",
                         "schema": {
@@ -337,26 +359,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/dashboard": {
-            "get": {
+        "/channels/{id}/referral-program": {
+            "put": {
                 "security": [
                     {
                         "TgAuth": []
                     }
                 ],
-                "description": "Retrieves all data for the main dashboard screen. The user is identified via the ` + "`" + `initData` + "`" + ` in the Authorization header. If the user does not exist in the database, a 404 error is returned.",
+                "description": "Sets the share of the creator's earnings from one of their verified channels that referrers get for the subscribers they bring, in basis points of what the creator receives after the platform commission. Referrers take their link with ` + "`" + `/channels/{id}/referral-link` + "`" + `; a subscriber is credited to the referrer whose link they last opened before their first payment to the channel, and the referrer then gets the share of every payment the subscriber makes to it. A share of 0 ends the program; subscribers referred earlier keep the share they were referred at.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tribute"
+                    "Referrals"
+                ],
+                "summary": "Set Up a Referral Program",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The share for referrers.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReferralProgramRequest"
+                        }
+                    }
                 ],
-                "summary": "Get Dashboard Data",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved dashboard data.",
+                        "description": "Success - The channel with its new referral share.",
                         "schema": {
-                            "$ref": "#/definitions/dto.DashboardResponse"
+                            "$ref": "#/definitions/dto.ChannelDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The share is out of range or the channel is not verified.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "401": {
@@ -366,13 +415,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "description": "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Not Found - The user does not exist in the database.",
+                        "description": "Not Found - The channel does not exist.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -386,56 +435,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/channels/{id}/start-links": {
             "get": {
-                "description": "Get the health status of the API",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/onboard": {
-            "put": {
                 "security": [
                     {
                         "TgAuth": []
                     }
                 ],
-                "description": "Creates a user record if one doesn't exist, or updates an existing user to mark them as onboarded. This is the first endpoint a new user should call. It is idempotent.",
+                "description": "Returns the live start links of one of the user's channels with how many times and by how many users each was opened.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tribute"
+                    "Start Links"
+                ],
+                "summary": "List Start Links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Onboard a User",
                 "responses": {
                     "200": {
-                        "description": "Success - The user already existed and has been marked as onboarded.",
+                        "description": "Success - The channel's start links.",
                         "schema": {
-                            "$ref": "#/definitions/dto.OnboardResponse"
+                            "$ref": "#/definitions/dto.StartLinksResponse"
                         }
                     },
-                    "201": {
-                        "description": "Created - A new user was created and marked as onboarded.",
+                    "400": {
+                        "description": "Bad Request - The channel ID is malformed.",
                         "schema": {
-                            "$ref": "#/definitions/dto.OnboardResponse"
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "401": {
@@ -445,7 +479,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "description": "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The channel does not exist.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -457,16 +497,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/publish-subscription": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "TgAuth": []
                     }
                 ],
-                "description": "Allows an author to create or update their public subscription details (title, description, price). This is an idempotent operation. The user must have at least one channel added via ` + "`" + `/add-bot` + "`" + ` to use this.",
+                "description": "Creates a short link (` + "`" + `https://t.me/\u003cbot\u003e?startapp=\u003ccode\u003e` + "`" + `) that opens the Mini App on the offer of one of the user's verified channels, optionally with one of its prices preselected. Share it anywhere; every launch of the Mini App through it is counted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -474,29 +512,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tribute"
+                    "Start Links"
                 ],
-                "summary": "Publish or Update a Subscription Tier",
+                "summary": "Create a Start Link",
                 "parameters": [
                     {
-                        "description": "The details of the subscription tier to publish.",
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The link to create.",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.PublishSubscriptionRequest"
+                            "$ref": "#/definitions/dto.StartLinkRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Success - The subscription was published or updated successfully.",
+                    "201": {
+                        "description": "Created - The new start link.",
                         "schema": {
-                            "$ref": "#/definitions/dto.PublishSubscriptionResponse"
+                            "$ref": "#/definitions/dto.StartLinkDTO"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - The request body is invalid.",
+                        "description": "Bad Request - The request is invalid or the channel is not verified.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -508,36 +553,16 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "description": "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error - e.g., the user has no channels.",
+                    "404": {
+                        "description": "Not Found - The channel does not exist or the price is not one of its prices.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/reset-database": {
-            "get": {
-                "description": "Drops all tables and recreates them with empty structure. WARNING: This will delete all data!",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Development"
-                ],
-                "summary": "Reset Database",
-                "responses": {
-                    "200": {
-                        "description": "Success - Database was reset successfully.",
-                        "schema": {
-                            "$ref": "#/definitions/dto.MessageResponse"
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error - An unexpected error occurred.",
@@ -548,44 +573,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/set-up-payouts": {
-            "post": {
+        "/channels/{id}/tiers": {
+            "get": {
                 "security": [
                     {
                         "TgAuth": []
                     }
                 ],
-                "description": "Saves user's bank card number for payouts. The user must be verified to use this endpoint. Only the card number is stored in the database.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the subscription tiers of a channel with the prices each one is sold at, both in their sort order. The channel owner always sees their tiers; other users only see the tiers of verified channels.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tribute"
+                    "Catalog"
                 ],
-                "summary": "Set Up Payout Method",
+                "summary": "List Channel Tiers",
                 "parameters": [
                     {
-                        "description": "The user's card number.",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.SetUpPayoutsRequest"
-                        }
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success - The card number was saved successfully.",
+                        "description": "Success - The channel's tiers.",
                         "schema": {
-                            "$ref": "#/definitions/dto.MessageResponse"
+                            "$ref": "#/definitions/dto.TiersResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - The request body is invalid.",
+                        "description": "Bad Request - The channel ID is malformed or the channel is not verified.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -597,28 +617,149 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - The provided initData is invalid or expired, or the user is not verified.",
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The channel does not exist.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error - Database error.",
+                        "description": "Internal Server Error - An unexpected error occurred.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/upload-verified-passport": {
+            },
             "post": {
                 "security": [
                     {
                         "TgAuth": []
                     }
                 ],
-                "description": "Uploads a user's photo and passport scan for manual verification. Both images must be provided as base64 encoded strings. The documents are sent to a private admin chat for review.",
+                "description": "Adds a subscription tier (e.g. \"Basic\" or \"VIP\") to one of the user's verified channels. Subscribers can buy the tier once at least one price is added to it via ` + "`" + `POST /tiers/{id}/prices` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Create a Tier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The tier to create.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TierRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - The new tier.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request is invalid or the channel is not verified.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The channel does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{username}/offer": {
+            "get": {
+                "description": "Returns what the subscribe page of a channel shows: its title, whether its ownership is verified, and the tiers that can be bought with their titles, descriptions, button texts and price options (billing interval, price and free trial days). Tiers are only listed for verified channels. No authentication is needed, so the page can be shown before the Mini App has initData; subscribing still goes through ` + "`" + `/create-subscribe` + "`" + ` or ` + "`" + `/start-trial` + "`" + ` with the returned ` + "`" + `channel_id` + "`" + `.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get a Channel's Offer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel username, with or without the @",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The channel's offer.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChannelOfferResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No channel with this username has been added.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/check-channel": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Checks in Telegram that the user is the creator or an administrator of the channel and that the bot is an administrator allowed to invite users via link and to ban users. If everything is in place the channel becomes ` + "`" + `verified` + "`" + `. Otherwise it stays ` + "`" + `pending` + "`" + ` with the list of ` + "`" + `missing_permissions` + "`" + ` and is checked again in the background until it is verified or the retries are given up; the owner is told in the bot either way. The owner can only be checked once the bot is an administrator. Calling this again starts the retries over.",
                 "consumes": [
                     "application/json"
                 ],
@@ -628,27 +769,27 @@ const docTemplate = `{
                 "tags": [
                     "Tribute"
                 ],
-                "summary": "Upload Documents for Verification",
+                "summary": "Check Channel Ownership",
                 "parameters": [
                     {
-                        "description": "JSON object containing base64 encoded photo and passport.",
+                        "description": "The channel ID to check.",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UploadVerifiedPassportRequest"
+                            "$ref": "#/definitions/dto.CheckChannelRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success - The verification request was sent successfully.",
+                        "description": "Success - Channel ownership check result.",
                         "schema": {
-                            "$ref": "#/definitions/dto.MessageResponse"
+                            "$ref": "#/definitions/dto.CheckChannelResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - The request body is invalid or missing required fields.",
+                        "description": "Bad Request - The request body is invalid or channel not found.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -666,324 +807,3124 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error - Failed to send documents to the verification service.",
+                        "description": "Internal Server Error - Failed to verify channel ownership.",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AddBotRequest": {
-            "type": "object",
-            "required": [
-                "channel_title",
-                "channel_username",
-                "user_id"
-            ],
-            "properties": {
-                "channel_title": {
+        },
+        "/check-verified-passport": {
+            "post": {
+                "description": "Callback of the KYC provider that decides a user's verification request: the one named by ` + "`" + `requestId` + "`" + `, or else the user's open request. Without any request only the user's status is set. Each integration partner signs its requests with its own secret from ` + "`" + `PARTNER_SECRETS` + "`" + `: ` + "`" + `X-Partner-Id` + "`" + ` names the partner, ` + "`" + `X-Signature-Timestamp` + "`" + ` carries the Unix time in seconds and ` + "`" + `X-Signature` + "`" + ` the hex HMAC-SHA256 of ` + "`" + `\u003ctimestamp\u003e.\u003cbody\u003e` + "`" + `. Signatures older than ` + "`" + `PARTNER_SIGNATURE_MAX_SKEW` + "`" + ` or accepted before are rejected. Every attempt is audited and rejected ones are reported to the admin chat.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Check Verified Passport",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The integration partner that signed the request.",
+                        "name": "X-Partner-Id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unix time the request was signed at.",
+                        "name": "X-Signature-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hex HMAC-SHA256 of the timestamp and body.",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "User ID and verification status.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CheckVerifiedPassportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - User verification status updated.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request body.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The request isn't validly signed by a partner, or replays an earlier one.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - User or verification request not found.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - The verification request has already been decided.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to update user verification status.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/create-subscribe": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Charges the subscriber for one billing period of a price of the channel's subscription tiers (` + "`" + `price_id` + "`" + `, or the first price of the first tier if omitted) through the configured payment provider. The amount is always taken from the catalog, less the discount of ` + "`" + `promo_code` + "`" + ` if one is given. With Telegram invoices the payment stays ` + "`" + `pending` + "`" + `: open ` + "`" + `invoice_link` + "`" + ` in the Mini App (` + "`" + `Telegram.WebApp.openInvoice` + "`" + `) to pay. Access to the creator's channel is granted, and a one-time invite link sent via the bot, only once the provider confirms the payment; the payment ` + "`" + `status` + "`" + ` shows whether that already happened.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Subscribe to a Channel",
+                "parameters": [
+                    {
+                        "description": "The ID of the channel to subscribe to.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateSubscribeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - The payment was created; see its status.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateSubscribeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request body is invalid, the channel is not verified, the user tried to subscribe to their own channel, or the promo code can't be used.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The channel does not exist, has no subscription tier, or the price is not one of its prices.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/create-user": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Creates a new user if one doesn't exist, otherwise returns the existing user. This endpoint is idempotent and returns dashboard data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Create User",
+                "responses": {
+                    "200": {
+                        "description": "Success - User already existed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DashboardResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created - A new user was created.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DashboardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Retrieves all data for the main dashboard screen. The user is identified via the ` + "`" + `initData` + "`" + ` in the Authorization header. If the user does not exist in the database, a 404 error is returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Get Dashboard Data",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved dashboard data.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DashboardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The user does not exist in the database.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Get the health status of the API",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/my-subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Returns the channels the user has access to as a subscriber, including free trials, followed by their past subscriptions. Each entry has the channel, tier and price, when the next period is due and, while access lasts, the invite link into the channel.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "My Subscriptions"
+                ],
+                "summary": "List My Subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "Success - The user's current and past subscriptions.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MySubscriptionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/my-subscriptions/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Stops one of the user's subscriptions from renewing. Access lasts until the end of the period already paid for (or of the free trial), after which the user is removed from the channel. Cancelling again has no effect; lifetime access can't be cancelled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "My Subscriptions"
+                ],
+                "summary": "Cancel a Subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription (membership) ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The cancelled subscription.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MembershipDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The ID is malformed, or the subscription has expired or is lifetime access.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the subscription belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The subscription does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/onboard": {
+            "put": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Creates a user record if one doesn't exist, or updates an existing user to mark them as onboarded. This is the first endpoint a new user should call. It is idempotent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Onboard a User",
+                "responses": {
+                    "200": {
+                        "description": "Success - The user already existed and has been marked as onboarded.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OnboardResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created - A new user was created and marked as onboarded.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OnboardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/received": {
+            "get": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Returns the succeeded and refunded payments made to the user for their channels, newest first, with how much of each was refunded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Refunds"
+                ],
+                "summary": "List Received Payments",
+                "responses": {
+                    "200": {
+                        "description": "Success - The payments made to the user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook/{provider}": {
+            "post": {
+                "description": "Receives asynchronous charge events (succeeded, failed, refunded) from a payment provider. A refund the provider reports on its own, e.g. a chargeback, is recorded against the payment, taken out of the creator's balance and ends the subscriber's access. Each provider authenticates its own requests; the simulator expects the hex HMAC-SHA256 of the body, keyed with ` + "`" + `PAYMENT_SIMULATOR_WEBHOOK_SECRET` + "`" + `, in ` + "`" + `X-Simulator-Signature` + "`" + `. Events that fail to apply are answered with 500 so the provider retries them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Payment Provider Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment provider name, e.g. simulator",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The event was applied.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The webhook could not be authenticated or parsed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The provider is unknown or doesn't use webhooks.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - The event could not be applied.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/refunds": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Returns part or all of a payment made to the user back to the subscriber through the payment provider, takes it back out of the user's balance (the platform commission on the refunded part is returned too) and tells the subscriber via the bot. With ` + "`" + `revoke_access` + "`" + ` the subscriber is also removed from the channel. A payment can be refunded in several parts up to its amount; Telegram Stars payments can only be refunded in full. The ` + "`" + `Idempotency-Key` + "`" + ` header makes the request safe to retry: repeating it returns the same refund, and a refund the provider rejected is tried again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Refunds"
+                ],
+                "summary": "Refund a Payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of this refund request, e.g. a UUID",
+                        "name": "Idempotency-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "How much to refund.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefundPaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - The refund and the updated payment.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefundResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request is invalid, the amount exceeds what is left to refund, or the payment can't be refunded.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the payment was made to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The payment does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - The idempotency key was used for a different amount, or its refund is still in progress.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - The payment provider rejected the refund.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts": {
+            "get": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Returns the user's payouts, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payouts"
+                ],
+                "summary": "Get Payout History",
+                "responses": {
+                    "200": {
+                        "description": "Success - Returns the payout history.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PayoutsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Withdraws part of the creator's balance to the card set up via ` + "`" + `/set-up-payouts` + "`" + `. The amount is reserved immediately and sent to the payout gateway in the background; follow its progress with ` + "`" + `GET /payouts` + "`" + `. If the payout fails the amount is returned to the balance.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payouts"
+                ],
+                "summary": "Request a Payout",
+                "parameters": [
+                    {
+                        "description": "The amount to withdraw.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RequestPayoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - The payout was requested.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PayoutDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The amount is invalid, below the minimum or exceeds the balance, or no payout method is set up.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The user is not verified, or the initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Changes the amount, trial days and position of a price. The interval can't be changed; delete the price and add a new one instead. Payments that are already pending keep their amount.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Update a Tier Price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Price ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The new price details.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTierPriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The updated price.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TierPriceDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request is invalid, e.g. a malformed price.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the price belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The price does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Stops offering a tier at a price. Subscribers who already paid it keep their access until the end of their period.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Delete a Tier Price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Price ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The price was removed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The price ID is malformed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the price belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The price does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Changes the redemption limits and validity window of a promo code. The code and its discount can't be changed; delete it and create a new one instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promo Codes"
+                ],
+                "summary": "Update a Promo Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The new limits and validity window.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePromoCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The updated promo code.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromoCodeDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request is invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the code belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The promo code does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Stops a promo code from being used. Payments that already used it keep their discount.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promo Codes"
+                ],
+                "summary": "Delete a Promo Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The promo code was removed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The promo code ID is malformed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the code belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The promo code does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/publish-subscription": {
+            "put": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Allows an author to create or update the public subscription details (title, description, monthly price) of the first tier of one of their channels. Publishing again for the same ` + "`" + `channel_id` + "`" + ` updates that tier; further tiers and billing intervals are managed via ` + "`" + `/channels/{id}/tiers` + "`" + `. This is an idempotent operation. The channel must have been added via ` + "`" + `/add-bot` + "`" + ` and verified via ` + "`" + `/check-channel` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Publish or Update a Subscription Tier",
+                "parameters": [
+                    {
+                        "description": "The details of the subscription tier to publish.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PublishSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The subscription was published or updated successfully.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PublishSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request body is invalid, the price or currency is malformed, or the channel is not verified.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The channel does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reset-database": {
+            "get": {
+                "description": "Drops all tables and recreates them with empty structure. WARNING: This will delete all data!",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Development"
+                ],
+                "summary": "Reset Database",
+                "responses": {
+                    "200": {
+                        "description": "Success - Database was reset successfully.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/set-up-payouts": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Saves the bank card the user is paid out to, replacing any previous one. The user must be verified to use this endpoint. The card number is checked with the Luhn algorithm and stored only encrypted; responses only ever show the last four digits.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Set Up Payout Method",
+                "parameters": [
+                    {
+                        "description": "The user's card number and expiry.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetUpPayoutsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The card was saved successfully.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request body is invalid, the card number fails validation or the card has expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the user is not verified.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Database error.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/start-links/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Stops a start link from opening the offer. Its code is never given to another link. Referrers can delete their referral links the same way; subscribers a link already brought stay referred, and asking for the channel's referral link again makes a new one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Start Links"
+                ],
+                "summary": "Delete a Start Link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The start link was removed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The start link ID is malformed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the link belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The start link does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/start-offer": {
+            "get": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Resolves the ` + "`" + `start_param` + "`" + ` of the initData the Mini App was launched with, i.e. the code of a creator's start link, to the channel offer it points to, and records the launch as a click on the link. Repeated requests of the same launch are counted once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Start Links"
+                ],
+                "summary": "Open the Start Link Offer",
+                "responses": {
+                    "200": {
+                        "description": "Success - The offer the link opens.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StartOfferResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The Mini App wasn't launched through a start link, or the link was deleted.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/start-trial": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Gives the subscriber free access to a channel for the trial days of one of its tier prices (` + "`" + `price_id` + "`" + `, or the first price of the first tier if omitted) and sends a one-time invite link via the bot. Each user gets one trial per channel. Before the trial ends the bot reminds the subscriber and invoices them for the price; paying converts the trial into a paid subscription that starts when the trial ends, otherwise the subscriber is removed from the channel.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Start a Free Trial",
+                "parameters": [
+                    {
+                        "description": "The channel to try.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StartTrialRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - The trial started.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MembershipDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request body is invalid, the channel is not verified, or the price has no trial.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The channel does not exist, has no subscription tier, or the price is not one of its prices.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - The user already had a trial in this channel or is already subscribed to it.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/telegram/webhook": {
+            "post": {
+                "description": "Receives updates from Telegram. Requests must carry the secret token configured via ` + "`" + `setWebhook` + "`" + `. Callback queries from the verification buttons in the admin chat, pre-checkout queries and successful invoice payments are processed and answered here. Processing errors are logged and still acknowledged with 200 so Telegram doesn't redeliver the update.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Telegram Bot Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The secret token configured for the webhook.",
+                        "name": "X-Telegram-Bot-Api-Secret-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "The update sent by Telegram.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/telegram.Update"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The update was accepted.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The update payload is malformed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The secret token is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tiers/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Changes the title, description, button text and position of one of the user's tiers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Update a Tier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The new tier details.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TierRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The updated tier.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request is invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the tier belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The tier does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Removes a tier and its prices from the channel's catalog. Subscribers who already paid for it keep their access until the end of their period.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Delete a Tier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The tier was removed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The tier ID is malformed.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the tier belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The tier does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tiers/{id}/prices": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Offers a tier for a billing interval: ` + "`" + `month` + "`" + `, ` + "`" + `quarter` + "`" + `, ` + "`" + `year` + "`" + ` or ` + "`" + `lifetime` + "`" + `. A tier has at most one price per interval. Lifetime access never expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Add a Tier Price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The price to add.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTierPriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - The new price.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TierPriceDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request is invalid, e.g. an unknown interval or a malformed price.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired, or the tier belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - The tier does not exist.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - The tier already has a price for this interval.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload-verified-passport": {
+            "post": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Uploads a user's photo and passport scan for verification. Both images must be provided as base64 encoded strings. The documents are stored with a new verification request and sent to a private admin chat for review; if that fails they are sent again later. The request's progress is shown by ` + "`" + `GET /verification` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Upload Documents for Verification",
+                "parameters": [
+                    {
+                        "description": "JSON object containing base64 encoded photo and passport.",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UploadVerifiedPassportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success - The verification request was sent successfully.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - The request body is invalid, missing required fields or a document isn't valid base64.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - The user is already verified or has a request in review.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to store the documents.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verification": {
+            "get": {
+                "security": [
+                    {
+                        "TgAuth": []
+                    }
+                ],
+                "description": "Returns whether the user is verified and their latest verification request: its status (` + "`" + `submitted` + "`" + `, ` + "`" + `delivering` + "`" + `, ` + "`" + `in_review` + "`" + `, ` + "`" + `approved` + "`" + `, ` + "`" + `rejected` + "`" + ` or ` + "`" + `resubmit` + "`" + `), the reason given when it was rejected or new documents were asked for, and its status history. Who reviewed the request isn't shown.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tribute"
+                ],
+                "summary": "Get Verification Status",
+                "responses": {
+                    "200": {
+                        "description": "Success - The user's verification status.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerificationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - The Authorization header is missing or invalid.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - The provided initData is invalid or expired.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - User not found.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - An unexpected error occurred.",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AddBotRequest": {
+            "type": "object",
+            "required": [
+                "channel_title",
+                "channel_username"
+            ],
+            "properties": {
+                "channel_title": {
+                    "type": "string"
+                },
+                "channel_username": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is only needed in requests signed by our services; from the Mini App the user comes from initData",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AddBotResponse": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "$ref": "#/definitions/dto.ChannelDTO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ChannelDTO": {
+            "type": "object",
+            "properties": {
+                "channel_title": {
+                    "type": "string"
+                },
+                "channel_username": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_verified": {
+                    "type": "boolean"
+                },
+                "missing_permissions": {
+                    "description": "MissingPermissions is what kept the last check from verifying a pending channel",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MissingPermissionDTO"
+                    }
+                },
+                "next_check_at": {
+                    "description": "NextCheckAt is when a pending channel is checked again; empty once retries are given up",
+                    "type": "string"
+                },
+                "referral_share_bps": {
+                    "description": "Share of the creator's earnings paid to referrers (1000 = 10%); 0 if the channel has no referral program",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "status": {
+                    "description": "pending until the owner and the bot are confirmed to have the rights needed, then verified",
+                    "type": "string",
+                    "example": "pending"
+                }
+            }
+        },
+        "dto.ChannelOfferResponse": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "description": "Pass to /create-subscribe and /start-trial",
+                    "type": "string"
+                },
+                "channel_title": {
+                    "type": "string"
+                },
+                "channel_username": {
+                    "type": "string"
+                },
+                "is_verified": {
+                    "type": "boolean"
+                },
+                "referral_share_bps": {
+                    "description": "ReferralShareBPS is what referrers earn of the creator's earnings from subscribers they bring (1000 = 10%); 0 if the channel has no referral program",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "tiers": {
+                    "description": "Tiers that can be bought, in display order; empty unless the channel is verified",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SubDTO"
+                    }
+                }
+            }
+        },
+        "dto.CheckChannelRequest": {
+            "type": "object",
+            "required": [
+                "channel_id"
+            ],
+            "properties": {
+                "channel_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CheckChannelResponse": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "description": "The channel with its verification status and what is missing",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.ChannelDTO"
+                        }
+                    ]
+                },
+                "is_owner": {
+                    "description": "True once the channel is verified",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CheckVerifiedPassportRequest": {
+            "type": "object",
+            "required": [
+                "userId"
+            ],
+            "properties": {
+                "isVerificated": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "description": "Reason is shown to the user when the request is rejected",
+                    "type": "string"
+                },
+                "requestId": {
+                    "description": "RequestID is the verification request the decision is about; without it the user's open request is decided",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateSubscribeRequest": {
+            "type": "object",
+            "required": [
+                "channel_id"
+            ],
+            "properties": {
+                "channel_id": {
+                    "description": "Channel whose tier to subscribe to",
+                    "type": "string"
+                },
+                "price_id": {
+                    "description": "Tier price to pay; defaults to the channel's first tier price",
+                    "type": "string"
+                },
+                "promo_code": {
+                    "description": "Case-insensitive promo code of the channel",
+                    "type": "string",
+                    "example": "SPRING20"
+                },
+                "send_invoice": {
+                    "description": "Also send the invoice to the subscriber's chat with the bot",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CreateSubscribeResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
+                },
+                "discount": {
+                    "description": "Taken off by the promo code, if one was applied",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyDTO"
+                        }
+                    ]
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "invoice_link": {
+                    "description": "Empty if the payment was settled right away",
+                    "type": "string",
+                    "example": "https://t.me/$AbCdEf"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                }
+            }
+        },
+        "dto.CreateTierPriceRequest": {
+            "type": "object",
+            "required": [
+                "interval",
+                "price"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "ISO 4217 code, defaults to the platform currency",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "interval": {
+                    "type": "string",
+                    "enum": [
+                        "month",
+                        "quarter",
+                        "year",
+                        "lifetime"
+                    ],
+                    "example": "month"
+                },
+                "price": {
+                    "description": "Decimal amount in major units",
+                    "type": "string",
+                    "example": "199.00"
+                },
+                "sort_order": {
+                    "description": "Prices are listed in ascending order",
+                    "type": "integer"
+                },
+                "trial_days": {
+                    "description": "Free days before the first charge",
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "dto.DashboardResponse": {
+            "type": "object",
+            "properties": {
+                "card_number": {
+                    "description": "Masked",
+                    "type": "string",
+                    "example": "**** **** **** 4242"
+                },
+                "channels-and-groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ChannelDTO"
+                    }
+                },
+                "earn": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
+                },
+                "is-sub-published": {
+                    "type": "boolean"
+                },
+                "is-verified": {
+                    "type": "boolean"
+                },
+                "payments-history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentDTO"
+                    }
+                },
+                "payout_card": {
+                    "$ref": "#/definitions/dto.PayoutCardDTO"
+                },
+                "referrals": {
+                    "description": "The user's referral links to other creators' channels",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReferralDTO"
+                    }
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SubDTO"
+                    }
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MembershipDTO": {
+            "type": "object",
+            "properties": {
+                "billing_interval": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "channel_id": {
+                    "type": "string"
+                },
+                "channel_title": {
+                    "type": "string"
+                },
+                "channel_username": {
+                    "type": "string"
+                },
+                "current_period_end": {
+                    "description": "Empty for lifetime access",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invite_link": {
+                    "description": "Only while the membership grants access",
+                    "type": "string"
+                },
+                "next_renewal_at": {
+                    "description": "Empty if the membership won't renew",
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
+                },
+                "price_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "trialing"
+                },
+                "tier_id": {
+                    "type": "string"
+                },
+                "tier_title": {
+                    "type": "string"
+                },
+                "trial_ends_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MissingPermissionDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "owner_is_admin, bot_is_admin, bot_can_invite_users or bot_can_restrict_members",
+                    "type": "string",
+                    "example": "bot_can_invite_users"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Allow the bot to invite users via link"
+                },
+                "subject": {
+                    "description": "Who lacks it: owner or bot",
+                    "type": "string",
+                    "example": "bot"
+                }
+            }
+        },
+        "dto.MoneyDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "199.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                }
+            }
+        },
+        "dto.MySubscriptionsResponse": {
+            "type": "object",
+            "properties": {
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MembershipDTO"
+                    }
+                }
+            }
+        },
+        "dto.OnboardResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponse"
+                }
+            }
+        },
+        "dto.PaymentDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
+                },
+                "created-date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "description": "Taken off by a promo code",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyDTO"
+                        }
+                    ]
+                },
+                "failure-reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "refunded": {
+                    "description": "Returned to the payer so far",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyDTO"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "example": "succeeded"
+                }
+            }
+        },
+        "dto.PaymentsResponse": {
+            "type": "object",
+            "properties": {
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentDTO"
+                    }
+                }
+            }
+        },
+        "dto.PayoutCardDTO": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string",
+                    "example": "visa"
+                },
+                "exp_month": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "exp_year": {
+                    "type": "integer",
+                    "example": 2027
+                },
+                "last4": {
+                    "type": "string",
+                    "example": "4242"
+                }
+            }
+        },
+        "dto.PayoutDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "processing"
+                }
+            }
+        },
+        "dto.PayoutsResponse": {
+            "type": "object",
+            "properties": {
+                "payouts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PayoutDTO"
+                    }
+                }
+            }
+        },
+        "dto.PromoCodeDTO": {
+            "type": "object",
+            "properties": {
+                "amount_off": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
+                },
+                "channel_id": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "SPRING20"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string",
+                    "example": "percent"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "percent_off": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "redemptions": {
+                    "description": "Payments that used the code and went through",
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PromoCodeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "discount_type"
+            ],
+            "properties": {
+                "amount_off": {
+                    "description": "Decimal amount in major units, for fixed discounts",
+                    "type": "string",
+                    "example": "50.00"
+                },
+                "code": {
+                    "description": "3-32 letters, digits, dashes or underscores; case-insensitive",
+                    "type": "string",
+                    "example": "SPRING20"
+                },
+                "currency": {
+                    "description": "ISO 4217 code of amount_off, defaults to the platform currency",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "discount_type": {
+                    "type": "string",
+                    "enum": [
+                        "percent",
+                        "fixed"
+                    ],
+                    "example": "percent"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-04-01T00:00:00Z"
+                },
+                "max_redemptions": {
+                    "description": "0 for unlimited",
+                    "type": "integer",
+                    "example": 100
+                },
+                "per_user_limit": {
+                    "description": "0 for unlimited",
+                    "type": "integer",
+                    "example": 1
+                },
+                "percent_off": {
+                    "description": "1-99, for percent discounts",
+                    "type": "integer",
+                    "example": 20
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
+                },
+                "tier_id": {
+                    "description": "Limits the code to one tier of the channel",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PromoCodesResponse": {
+            "type": "object",
+            "properties": {
+                "promo_codes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PromoCodeDTO"
+                    }
+                }
+            }
+        },
+        "dto.PublishSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "channel_id",
+                "price"
+            ],
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "button-text": {
+                    "type": "string"
+                },
+                "channel_id": {
+                    "description": "Verified channel the tier grants access to",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "ISO 4217 code, defaults to the platform currency",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Decimal amount in major units",
+                    "type": "string",
+                    "example": "199.00"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PublishSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "subscription": {
+                    "$ref": "#/definitions/dto.SubDTO"
+                }
+            }
+        },
+        "dto.ReferralDTO": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "type": "string"
+                },
+                "channel_title": {
+                    "type": "string"
+                },
+                "channel_username": {
+                    "type": "string"
+                },
+                "clicks": {
+                    "description": "Launches of the Mini App through the link",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "q3Vx_9aB"
+                },
+                "conversions": {
+                    "description": "Subscribers who paid after opening it",
+                    "type": "integer"
+                },
+                "earned": {
+                    "description": "Credited to the referrer's balance per currency, less refunds",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MoneyDTO"
+                    }
+                },
+                "referral_share_bps": {
+                    "description": "The channel's current share for new referrals",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://t.me/tribute_bot?startapp=q3Vx_9aB"
+                },
+                "visitors": {
+                    "description": "Different users who opened it",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ReferralProgramRequest": {
+            "type": "object",
+            "required": [
+                "share_bps"
+            ],
+            "properties": {
+                "share_bps": {
+                    "description": "Share of the creator's earnings (after the platform commission) paid to referrers, in basis points; 0 ends the program",
+                    "type": "integer",
+                    "example": 1000
+                }
+            }
+        },
+        "dto.RefundDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "revoke_access": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "creator, admin or chargeback",
+                    "type": "string",
+                    "example": "creator"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "succeeded"
+                }
+            }
+        },
+        "dto.RefundPaymentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Decimal amount in the payment's currency; omit to refund everything not refunded yet",
+                    "type": "string",
+                    "example": "99.50"
+                },
+                "reason": {
+                    "description": "Shown to the subscriber",
+                    "type": "string"
+                },
+                "revoke_access": {
+                    "description": "Also end the subscriber's access to the channel",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.RefundResponse": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/dto.PaymentDTO"
+                },
+                "refund": {
+                    "$ref": "#/definitions/dto.RefundDTO"
+                }
+            }
+        },
+        "dto.RequestPayoutRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Decimal amount in major units",
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "currency": {
+                    "description": "ISO 4217 code, defaults to the platform currency",
+                    "type": "string",
+                    "example": "RUB"
+                }
+            }
+        },
+        "dto.SetUpPayoutsRequest": {
+            "type": "object",
+            "required": [
+                "card-expiry",
+                "card-number"
+            ],
+            "properties": {
+                "card-expiry": {
+                    "description": "MM/YY or MM/YYYY",
+                    "type": "string",
+                    "example": "12/27"
+                },
+                "card-number": {
+                    "type": "string",
+                    "example": "4242 4242 4242 4242"
+                }
+            }
+        },
+        "dto.StartLinkDTO": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "type": "string"
+                },
+                "clicks": {
+                    "description": "Launches of the Mini App through the link",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "q3Vx_9aB"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "price_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://t.me/tribute_bot?startapp=q3Vx_9aB"
+                },
+                "visitors": {
+                    "description": "Different users who opened it",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.StartLinkRequest": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "description": "Tells the creator's links apart",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Instagram bio"
+                },
+                "price_id": {
+                    "description": "Price to preselect on the offer",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StartLinksResponse": {
+            "type": "object",
+            "properties": {
+                "start_links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.StartLinkDTO"
+                    }
+                }
+            }
+        },
+        "dto.StartOfferResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "offer": {
+                    "$ref": "#/definitions/dto.ChannelOfferResponse"
+                },
+                "price_id": {
+                    "description": "Price the link preselects",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StartTrialRequest": {
+            "type": "object",
+            "required": [
+                "channel_id"
+            ],
+            "properties": {
+                "channel_id": {
+                    "description": "Channel to try",
                     "type": "string"
                 },
-                "channel_username": {
+                "price_id": {
+                    "description": "Tier price with trial days; defaults to the channel's first tier price",
                     "type": "string"
-                },
-                "user_id": {
-                    "type": "integer"
                 }
             }
         },
-        "dto.AddBotResponse": {
+        "dto.StatusResponse": {
             "type": "object",
             "properties": {
-                "channel": {
-                    "$ref": "#/definitions/dto.ChannelDTO"
-                },
-                "message": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "dto.CallbackQuery": {
+        "dto.SubDTO": {
             "type": "object",
             "properties": {
-                "data": {
+                "button_text": {
                     "type": "string"
                 },
-                "from": {
-                    "$ref": "#/definitions/dto.User"
+                "channel_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "message": {
-                    "$ref": "#/definitions/dto.Message"
+                "price": {
+                    "description": "First price of the tier; omitted if it has none",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.MoneyDTO"
+                        }
+                    ]
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TierPriceDTO"
+                    }
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "dto.ChannelDTO": {
+        "dto.TierPriceDTO": {
             "type": "object",
             "properties": {
-                "channel_title": {
+                "id": {
                     "type": "string"
                 },
-                "channel_username": {
-                    "type": "string"
+                "interval": {
+                    "type": "string",
+                    "example": "month"
                 },
-                "id": {
-                    "type": "string"
+                "price": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
                 },
-                "is_verified": {
-                    "type": "boolean"
+                "sort_order": {
+                    "type": "integer"
+                },
+                "trial_days": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.Chat": {
+        "dto.TierRequest": {
             "type": "object",
+            "required": [
+                "title"
+            ],
             "properties": {
-                "id": {
+                "button_text": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "description": "Tiers are listed in ascending order",
                     "type": "integer"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "VIP"
                 }
             }
         },
-        "dto.CheckChannelRequest": {
+        "dto.TiersResponse": {
             "type": "object",
-            "required": [
-                "channel_id"
-            ],
             "properties": {
-                "channel_id": {
-                    "type": "string"
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SubDTO"
+                    }
                 }
             }
         },
-        "dto.CheckChannelResponse": {
+        "dto.UpdatePromoCodeRequest": {
             "type": "object",
             "properties": {
-                "is_owner": {
-                    "type": "boolean"
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-04-01T00:00:00Z"
+                },
+                "max_redemptions": {
+                    "description": "0 for unlimited",
+                    "type": "integer",
+                    "example": 100
+                },
+                "per_user_limit": {
+                    "description": "0 for unlimited",
+                    "type": "integer",
+                    "example": 1
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-03-01T00:00:00Z"
                 }
             }
         },
-        "dto.CreateSubscribeRequest": {
+        "dto.UpdateTierPriceRequest": {
             "type": "object",
+            "required": [
+                "price"
+            ],
             "properties": {
+                "currency": {
+                    "description": "ISO 4217 code, defaults to the platform currency",
+                    "type": "string",
+                    "example": "RUB"
+                },
                 "price": {
-                    "type": "number"
+                    "description": "Decimal amount in major units",
+                    "type": "string",
+                    "example": "199.00"
                 },
-                "user_id": {
+                "sort_order": {
+                    "description": "Prices are listed in ascending order",
                     "type": "integer"
+                },
+                "trial_days": {
+                    "description": "Free days before the first charge",
+                    "type": "integer",
+                    "example": 7
                 }
             }
         },
-        "dto.DashboardResponse": {
+        "dto.UploadVerifiedPassportRequest": {
             "type": "object",
             "properties": {
-                "card_number": {
+                "access_token": {
                     "type": "string"
                 },
-                "channels-and-groups": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.ChannelDTO"
-                    }
+                "user-passport": {
+                    "description": "Assuming base64 encoded string",
+                    "type": "string"
                 },
-                "earn": {
-                    "type": "number"
+                "user-photo": {
+                    "description": "Assuming base64 encoded string",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserResponse": {
+            "type": "object",
+            "properties": {
+                "card_number": {
+                    "description": "Masked",
+                    "type": "string",
+                    "example": "**** **** **** 4242"
                 },
-                "is-sub-published": {
+                "earned": {
+                    "$ref": "#/definitions/dto.MoneyDTO"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_onboarded": {
                     "type": "boolean"
                 },
-                "is-verified": {
+                "is_sub_published": {
                     "type": "boolean"
                 },
-                "payments-history": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.PaymentDTO"
-                    }
+                "is_verified": {
+                    "type": "boolean"
                 },
-                "subscriptions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.SubDTO"
-                    }
+                "payout_card": {
+                    "$ref": "#/definitions/dto.PayoutCardDTO"
                 }
             }
         },
-        "dto.ErrorResponse": {
+        "dto.VerificationEventDTO": {
             "type": "object",
             "properties": {
-                "error": {
+                "created_at": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.Message": {
-            "type": "object",
-            "properties": {
-                "chat": {
-                    "$ref": "#/definitions/dto.Chat"
                 },
-                "message_id": {
-                    "type": "integer"
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "in_review"
                 }
             }
         },
-        "dto.MessageResponse": {
+        "dto.VerificationRequestDTO": {
             "type": "object",
             "properties": {
-                "message": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VerificationEventDTO"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "rejected"
+                },
+                "submitted_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.OnboardResponse": {
+        "dto.VerificationResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "is_verified": {
+                    "type": "boolean"
                 },
-                "user": {
-                    "$ref": "#/definitions/dto.UserResponse"
+                "request": {
+                    "description": "Request is omitted if the user never submitted documents",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.VerificationRequestDTO"
+                        }
+                    ]
                 }
             }
         },
-        "dto.PaymentDTO": {
+        "telegram.CallbackQuery": {
             "type": "object",
             "properties": {
-                "created-date": {
+                "data": {
                     "type": "string"
                 },
-                "description": {
+                "from": {
+                    "$ref": "#/definitions/telegram.User"
+                },
+                "id": {
                     "type": "string"
+                },
+                "message": {
+                    "$ref": "#/definitions/telegram.Message"
                 }
             }
         },
-        "dto.PublishSubscriptionRequest": {
+        "telegram.Chat": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "button-text": {
+                "title": {
                     "type": "string"
                 },
-                "description": {
+                "type": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
-                },
-                "title": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "dto.PublishSubscriptionResponse": {
+        "telegram.ChatMember": {
             "type": "object",
             "properties": {
-                "message": {
+                "can_invite_users": {
+                    "type": "boolean"
+                },
+                "can_restrict_members": {
+                    "type": "boolean"
+                },
+                "status": {
                     "type": "string"
                 },
-                "subscription": {
-                    "$ref": "#/definitions/dto.SubDTO"
+                "user": {
+                    "type": "object",
+                    "properties": {
+                        "id": {
+                            "type": "integer"
+                        },
+                        "username": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
         },
-        "dto.SetUpPayoutsRequest": {
+        "telegram.ChatMemberUpdated": {
             "type": "object",
-            "required": [
-                "card-number"
-            ],
             "properties": {
-                "card-number": {
-                    "type": "string"
+                "chat": {
+                    "$ref": "#/definitions/telegram.Chat"
+                },
+                "date": {
+                    "type": "integer"
+                },
+                "from": {
+                    "$ref": "#/definitions/telegram.User"
+                },
+                "new_chat_member": {
+                    "$ref": "#/definitions/telegram.ChatMember"
+                },
+                "old_chat_member": {
+                    "$ref": "#/definitions/telegram.ChatMember"
                 }
             }
         },
-        "dto.StatusResponse": {
+        "telegram.Message": {
             "type": "object",
             "properties": {
-                "status": {
+                "chat": {
+                    "$ref": "#/definitions/telegram.Chat"
+                },
+                "from": {
+                    "$ref": "#/definitions/telegram.User"
+                },
+                "message_id": {
+                    "type": "integer"
+                },
+                "successful_payment": {
+                    "description": "SuccessfulPayment is set on the service message Telegram sends after an invoice is paid",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/telegram.SuccessfulPayment"
+                        }
+                    ]
+                },
+                "text": {
                     "type": "string"
                 }
             }
         },
-        "dto.SubDTO": {
+        "telegram.PreCheckoutQuery": {
             "type": "object",
             "properties": {
-                "description": {
+                "currency": {
                     "type": "string"
                 },
+                "from": {
+                    "$ref": "#/definitions/telegram.User"
+                },
                 "id": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
-                },
-                "title": {
+                "invoice_payload": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.TelegramUpdate": {
-            "type": "object",
-            "properties": {
-                "callback_query": {
-                    "$ref": "#/definitions/dto.CallbackQuery"
                 },
-                "update_id": {
+                "total_amount": {
                     "type": "integer"
                 }
             }
         },
-        "dto.UploadVerifiedPassportRequest": {
+        "telegram.SuccessfulPayment": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "currency": {
                     "type": "string"
                 },
-                "user-passport": {
-                    "description": "Assuming base64 encoded string",
+                "invoice_payload": {
                     "type": "string"
                 },
-                "user-photo": {
-                    "description": "Assuming base64 encoded string",
+                "provider_payment_charge_id": {
                     "type": "string"
+                },
+                "telegram_payment_charge_id": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.User": {
+        "telegram.Update": {
             "type": "object",
             "properties": {
-                "id": {
+                "callback_query": {
+                    "$ref": "#/definitions/telegram.CallbackQuery"
+                },
+                "message": {
+                    "$ref": "#/definitions/telegram.Message"
+                },
+                "my_chat_member": {
+                    "description": "MyChatMember is sent when the bot is added to a chat, promoted, demoted or removed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/telegram.ChatMemberUpdated"
+                        }
+                    ]
+                },
+                "pre_checkout_query": {
+                    "$ref": "#/definitions/telegram.PreCheckoutQuery"
+                },
+                "update_id": {
                     "type": "integer"
                 }
             }
         },
-        "dto.UserResponse": {
+        "telegram.User": {
             "type": "object",
             "properties": {
-                "card_number": {
+                "first_name": {
                     "type": "string"
                 },
-                "earned": {
-                    "type": "number"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "is_onboarded": {
-                    "type": "boolean"
-                },
-                "is_sub_published": {
+                "is_bot": {
                     "type": "boolean"
                 },
-                "is_verified": {
-                    "type": "boolean"
+                "username": {
+                    "type": "string"
                 }
             }
         }
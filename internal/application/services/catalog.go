@@ -0,0 +1,433 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+// maxTrialDays caps how long a free trial a creator can offer.
+const maxTrialDays = 90
+
+var (
+	// ErrInvalidPrice is returned when a price is zero or negative.
+	ErrInvalidPrice = errors.New("price must be greater than zero")
+	// ErrTierNotFound is returned when a tier ID doesn't refer to a live subscription tier.
+	ErrTierNotFound = errors.New("subscription tier not found")
+	// ErrNotTierOwner is returned when a user edits a tier of someone else's channel.
+	ErrNotTierOwner = errors.New("subscription tier does not belong to this user")
+	// ErrPriceNotFound is returned when a price ID doesn't refer to a live price of the tier or channel.
+	ErrPriceNotFound = errors.New("price not found")
+	// ErrInvalidInterval is returned for billing intervals other than month, quarter, year and lifetime.
+	ErrInvalidInterval = errors.New("billing interval must be one of month, quarter, year or lifetime")
+	// ErrIntervalTaken is returned when a tier already has a price for the billing interval.
+	ErrIntervalTaken = errors.New("tier already has a price for this billing interval")
+	// ErrInvalidTrialDays is returned when trial days are negative or above maxTrialDays.
+	ErrInvalidTrialDays = fmt.Errorf("trial days must be between 0 and %d", maxTrialDays)
+)
+
+// TierDetails are the creator-editable fields of a subscription tier.
+type TierDetails struct {
+	Title       string
+	Description string
+	ButtonText  string
+	SortOrder   int
+}
+
+// PriceDetails are the creator-editable fields of a tier price. The interval is
+// only used when the price is created.
+type PriceDetails struct {
+	Interval  entities.BillingInterval
+	Price     money.Money
+	TrialDays int
+	SortOrder int
+}
+
+func (d PriceDetails) validate() error {
+	if !d.Interval.IsValid() {
+		return ErrInvalidInterval
+	}
+	if !d.Price.IsPositive() {
+		return ErrInvalidPrice
+	}
+	if d.TrialDays < 0 || d.TrialDays > maxTrialDays {
+		return ErrInvalidTrialDays
+	}
+	return nil
+}
+
+// PublishSubscription creates or updates the first subscription tier of one of the
+// user's channels and sets its monthly price. Only verified channels can be monetized;
+// further tiers and intervals are managed through the tier and price catalog.
+func (s *TributeService) PublishSubscription(userID int64, channelID uuid.UUID, title, description, buttonText string, price money.Money) (*entities.Subscription, error) {
+	if !price.IsPositive() {
+		return nil, ErrInvalidPrice
+	}
+
+	channel, err := s.findOwnedChannel(userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if !channel.IsVerified {
+		return nil, ErrChannelNotVerified
+	}
+
+	tiers, err := s.subs.FindByChannelID(channel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscription *entities.Subscription
+	if len(tiers) > 0 {
+		// Update the channel's first tier
+		subscription = tiers[0]
+		subscription.Title = title
+		subscription.Description = description
+		subscription.ButtonText = buttonText
+		if err := s.subs.Update(subscription); err != nil {
+			return nil, err
+		}
+	} else {
+		subscription, err = s.createTier(channel, TierDetails{Title: title, Description: description, ButtonText: buttonText})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prices, err := s.prices.FindBySubscriptionID(subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+	monthly := findPriceByInterval(prices, entities.IntervalMonth)
+	if monthly != nil {
+		monthly.Price = price
+		if err := s.prices.Update(monthly); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := s.createPrice(subscription, PriceDetails{Interval: entities.IntervalMonth, Price: price}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.loadTierPrices([]*entities.Subscription{subscription}); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// GetChannelTiers returns the live tiers of a channel with their prices. Other users
+// can only see the tiers of verified channels.
+func (s *TributeService) GetChannelTiers(userID int64, channelID uuid.UUID) ([]*entities.Subscription, error) {
+	channel, err := s.channels.FindByID(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, ErrChannelNotFound
+	}
+	if channel.UserID != userID && !channel.IsVerified {
+		return nil, ErrChannelNotVerified
+	}
+
+	tiers, err := s.subs.FindByChannelID(channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadTierPrices(tiers); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// ChannelOffer is what a channel sells to new subscribers.
+type ChannelOffer struct {
+	Channel *entities.Channel
+	// Tiers are the tiers that can be bought, with their prices; empty unless the channel is verified
+	Tiers []*entities.Subscription
+}
+
+// GetChannelOffer returns the public subscribe page of a channel by its username.
+func (s *TributeService) GetChannelOffer(username string) (*ChannelOffer, error) {
+	channel, err := s.channels.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, ErrChannelNotFound
+	}
+	return s.channelOffer(channel)
+}
+
+// channelOffer lists the tiers of a channel that can be bought.
+func (s *TributeService) channelOffer(channel *entities.Channel) (*ChannelOffer, error) {
+	offer := &ChannelOffer{Channel: channel, Tiers: []*entities.Subscription{}}
+	if !channel.IsVerified {
+		return offer, nil
+	}
+
+	tiers, err := s.subs.FindByChannelID(channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadTierPrices(tiers); err != nil {
+		return nil, err
+	}
+	for _, tier := range tiers {
+		if len(tier.Prices) > 0 {
+			offer.Tiers = append(offer.Tiers, tier)
+		}
+	}
+	return offer, nil
+}
+
+// CreateTier adds a subscription tier to one of the user's verified channels. The tier
+// can't be bought until a price is added to it.
+func (s *TributeService) CreateTier(userID int64, channelID uuid.UUID, details TierDetails) (*entities.Subscription, error) {
+	channel, err := s.findOwnedChannel(userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if !channel.IsVerified {
+		return nil, ErrChannelNotVerified
+	}
+
+	tier, err := s.createTier(channel, details)
+	if err != nil {
+		return nil, err
+	}
+	tier.Prices = []*entities.TierPrice{}
+	return tier, nil
+}
+
+// UpdateTier changes the title, description, button text and position of a tier.
+func (s *TributeService) UpdateTier(userID int64, tierID uuid.UUID, details TierDetails) (*entities.Subscription, error) {
+	tier, err := s.findOwnedTier(userID, tierID)
+	if err != nil {
+		return nil, err
+	}
+
+	tier.Title = details.Title
+	tier.Description = details.Description
+	tier.ButtonText = details.ButtonText
+	tier.SortOrder = details.SortOrder
+	if err := s.subs.Update(tier); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadTierPrices([]*entities.Subscription{tier}); err != nil {
+		return nil, err
+	}
+	return tier, nil
+}
+
+// ArchiveTier removes a tier from the channel's catalog. Subscribers who already paid
+// for it keep their access until the end of their period.
+func (s *TributeService) ArchiveTier(userID int64, tierID uuid.UUID) error {
+	tier, err := s.findOwnedTier(userID, tierID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tier.ArchivedAt = &now
+	return s.subs.Update(tier)
+}
+
+// AddTierPrice offers a tier at a new price. A tier has at most one price per billing interval.
+func (s *TributeService) AddTierPrice(userID int64, tierID uuid.UUID, details PriceDetails) (*entities.TierPrice, error) {
+	if err := details.validate(); err != nil {
+		return nil, err
+	}
+
+	tier, err := s.findOwnedTier(userID, tierID)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := s.prices.FindBySubscriptionID(tier.ID)
+	if err != nil {
+		return nil, err
+	}
+	if findPriceByInterval(prices, details.Interval) != nil {
+		return nil, ErrIntervalTaken
+	}
+
+	return s.createPrice(tier, details)
+}
+
+// UpdateTierPrice changes the amount, trial and position of a price. Payments that are
+// already pending keep the amount they were created with.
+func (s *TributeService) UpdateTierPrice(userID int64, priceID uuid.UUID, details PriceDetails) (*entities.TierPrice, error) {
+	price, err := s.findOwnedPrice(userID, priceID)
+	if err != nil {
+		return nil, err
+	}
+
+	details.Interval = price.Interval
+	if err := details.validate(); err != nil {
+		return nil, err
+	}
+
+	price.Price = details.Price
+	price.TrialDays = details.TrialDays
+	price.SortOrder = details.SortOrder
+	if err := s.prices.Update(price); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// ArchiveTierPrice stops offering a tier at a price. Subscribers who already paid it keep
+// their access until the end of their period.
+func (s *TributeService) ArchiveTierPrice(userID int64, priceID uuid.UUID) error {
+	price, err := s.findOwnedPrice(userID, priceID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	price.ArchivedAt = &now
+	return s.prices.Update(price)
+}
+
+func (s *TributeService) createTier(channel *entities.Channel, details TierDetails) (*entities.Subscription, error) {
+	tier := &entities.Subscription{
+		ChannelID:       channel.ID,
+		UserID:          channel.UserID,
+		ChannelUsername: channel.ChannelUsername,
+		Title:           details.Title,
+		Description:     details.Description,
+		ButtonText:      details.ButtonText,
+		SortOrder:       details.SortOrder,
+		CreatedDate:     time.Now(),
+	}
+	if err := s.subs.Create(tier); err != nil {
+		return nil, err
+	}
+	return tier, nil
+}
+
+func (s *TributeService) createPrice(tier *entities.Subscription, details PriceDetails) (*entities.TierPrice, error) {
+	price := &entities.TierPrice{
+		SubscriptionID: tier.ID,
+		Interval:       details.Interval,
+		Price:          details.Price,
+		TrialDays:      details.TrialDays,
+		SortOrder:      details.SortOrder,
+		CreatedDate:    time.Now(),
+	}
+	if err := s.prices.Create(price); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// findOwnedTier returns a live tier if it belongs to one of the user's channels.
+func (s *TributeService) findOwnedTier(userID int64, tierID uuid.UUID) (*entities.Subscription, error) {
+	tier, err := s.subs.FindByID(tierID)
+	if err != nil {
+		return nil, err
+	}
+	if tier == nil || tier.ArchivedAt != nil {
+		return nil, ErrTierNotFound
+	}
+	if tier.UserID != userID {
+		return nil, ErrNotTierOwner
+	}
+	return tier, nil
+}
+
+// findOwnedPrice returns a live price if its tier belongs to one of the user's channels.
+func (s *TributeService) findOwnedPrice(userID int64, priceID uuid.UUID) (*entities.TierPrice, error) {
+	price, err := s.prices.FindByID(priceID)
+	if err != nil {
+		return nil, err
+	}
+	if price == nil || price.ArchivedAt != nil {
+		return nil, ErrPriceNotFound
+	}
+	if _, err := s.findOwnedTier(userID, price.SubscriptionID); err != nil {
+		if errors.Is(err, ErrTierNotFound) {
+			return nil, ErrPriceNotFound
+		}
+		return nil, err
+	}
+	return price, nil
+}
+
+// findCheckoutPrice returns the tier price a subscriber pays for access to a verified
+// channel, with its tier and the channel. Without a price ID the channel's default
+// offer is used: the first price of its first tier that has one.
+func (s *TributeService) findCheckoutPrice(channelID uuid.UUID, priceID *uuid.UUID) (*entities.Subscription, *entities.TierPrice, *entities.Channel, error) {
+	channel, err := s.channels.FindByID(channelID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if channel == nil {
+		return nil, nil, nil, ErrChannelNotFound
+	}
+	if !channel.IsVerified {
+		return nil, nil, nil, ErrChannelNotVerified
+	}
+
+	if priceID == nil {
+		tiers, err := s.subs.FindByChannelID(channel.ID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if err := s.loadTierPrices(tiers); err != nil {
+			return nil, nil, nil, err
+		}
+		for _, tier := range tiers {
+			if price := tier.DefaultPrice(); price != nil {
+				return tier, price, channel, nil
+			}
+		}
+		return nil, nil, nil, ErrNoSubscriptionTier
+	}
+
+	price, err := s.prices.FindByID(*priceID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if price == nil || price.ArchivedAt != nil {
+		return nil, nil, nil, ErrPriceNotFound
+	}
+	tier, err := s.subs.FindByID(price.SubscriptionID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if tier == nil || tier.ArchivedAt != nil || tier.ChannelID != channel.ID {
+		return nil, nil, nil, ErrPriceNotFound
+	}
+	return tier, price, channel, nil
+}
+
+// loadTierPrices fills in the live prices of each tier.
+func (s *TributeService) loadTierPrices(tiers []*entities.Subscription) error {
+	for _, tier := range tiers {
+		prices, err := s.prices.FindBySubscriptionID(tier.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load prices of tier %s: %w", tier.ID, err)
+		}
+		if prices == nil {
+			prices = []*entities.TierPrice{}
+		}
+		tier.Prices = prices
+	}
+	return nil
+}
+
+func findPriceByInterval(prices []*entities.TierPrice, interval entities.BillingInterval) *entities.TierPrice {
+	for _, price := range prices {
+		if price.Interval == interval {
+			return price
+		}
+	}
+	return nil
+}
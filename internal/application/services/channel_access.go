@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+)
+
+// grantChannelAccess issues a one-time invite link into the channel, stores it
+// on the membership and sends it to the subscriber.
+func (s *TributeService) grantChannelAccess(membership *entities.Membership, channel *entities.Channel) error {
+	expireDate := time.Now().Add(s.billing.InviteLinkTTL)
+	if membership.CurrentPeriodEnd != nil && membership.CurrentPeriodEnd.Before(expireDate) {
+		expireDate = *membership.CurrentPeriodEnd
+	}
+
+	// Link names are limited to 32 characters and are only shown to channel admins.
+	name := fmt.Sprintf("sub %d", membership.SubscriberID)
+	link, err := s.telegramBot.CreateChatInviteLink(channel.ChatID(), name, expireDate, 1)
+	if err != nil {
+		return fmt.Errorf("failed to create invite link: %w", err)
+	}
+
+	membership.InviteLink = link.InviteLink
+	if err := s.memberships.Update(membership); err != nil {
+		return fmt.Errorf("failed to save invite link: %w", err)
+	}
+
+	intro := "Оплата прошла успешно!"
+	if membership.Status == entities.MembershipTrialing {
+		intro = fmt.Sprintf("Пробный период начался и продлится до %s.", membership.CurrentPeriodEnd.Format("02.01.2006 15:04"))
+	}
+	message := fmt.Sprintf("%s Ваша ссылка для вступления в канал %s:\n%s\n\nСсылка одноразовая и действует до %s.",
+		intro, channel.ChannelTitle, link.InviteLink, expireDate.Format("02.01.2006 15:04"))
+	if err := s.telegramBot.SendMessage(membership.SubscriberID, message); err != nil {
+		return fmt.Errorf("failed to send invite link: %w", err)
+	}
+	return nil
+}
+
+// notifyAccessEnded tells the subscriber that their access to the channel ended.
+func (s *TributeService) notifyAccessEnded(membership *entities.Membership, channel *entities.Channel) {
+	message := fmt.Sprintf("Срок вашей подписки на канал %s истёк, доступ к каналу закрыт.", channel.ChannelTitle)
+	if membership.IsTrialPeriod() {
+		message = fmt.Sprintf("Пробный период в канале %s закончился, доступ к каналу закрыт.", channel.ChannelTitle)
+	}
+	if err := s.telegramBot.SendMessage(membership.SubscriberID, message); err != nil {
+		fmt.Printf("Failed to notify user %d about expired access: %v\n", membership.SubscriberID, err)
+	}
+}
+
+// removeFromChannel removes the subscriber from the channel and invalidates the invite
+// link they were given.
+func (s *TributeService) removeFromChannel(membership *entities.Membership, channel *entities.Channel) error {
+	if membership.InviteLink != "" {
+		// The link may already be used up or expired, which is fine.
+		if err := s.telegramBot.RevokeChatInviteLink(channel.ChatID(), membership.InviteLink); err != nil {
+			fmt.Printf("Failed to revoke invite link for membership %s: %v\n", membership.ID, err)
+		}
+	}
+
+	if err := s.telegramBot.KickChatMember(channel.ChatID(), membership.SubscriberID); err != nil {
+		return fmt.Errorf("failed to remove user %d from %s: %w", membership.SubscriberID, channel.ChatID(), err)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/infrastructure/telegram"
+
+	"github.com/google/uuid"
+)
+
+// CheckChannel checks in Telegram that the user owns or administers one of their channels
+// and that the bot is an administrator with the rights to invite and remove subscribers.
+// If everything is in place the channel is verified; otherwise it stays pending with the
+// missing permissions recorded, and is checked again on a schedule that starts over with
+// every request.
+func (s *TributeService) CheckChannel(userID int64, channelID uuid.UUID) (*entities.Channel, error) {
+	channel, err := s.findOwnedChannel(userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	missing, err := s.checkChannelRights(channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check channel membership: %w", err)
+	}
+	channel.VerificationAttempts = 0
+	if err := s.applyChannelCheck(channel, missing, time.Now()); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// RetryChannelChecks checks the pending channels that are due again and notifies their
+// owners when a channel gets verified or its retries are given up. Returns how many
+// channels were verified.
+func (s *TributeService) RetryChannelChecks(now time.Time) (int, error) {
+	due, err := s.channels.FindDueForVerification(now)
+	if err != nil {
+		return 0, err
+	}
+
+	verified := 0
+	for _, channel := range due {
+		missing, err := s.checkChannelRights(channel)
+		if err != nil {
+			// Telegram couldn't answer; the channel stays due and is tried on the next run
+			fmt.Printf("Failed to check channel %s: %v\n", channel.ID, err)
+			continue
+		}
+		if err := s.applyChannelCheck(channel, missing, now); err != nil {
+			return verified, fmt.Errorf("failed to save check of channel %s: %w", channel.ID, err)
+		}
+		if channel.IsVerified {
+			verified++
+		}
+	}
+	return verified, nil
+}
+
+// checkChannelRights returns what is missing in Telegram for the channel to be verified.
+// Errors are only returned when Telegram couldn't be asked; a refusal to answer about a
+// channel means the bot isn't one of its administrators.
+func (s *TributeService) checkChannelRights(channel *entities.Channel) ([]entities.ChannelPermission, error) {
+	botID, err := s.telegramBot.BotUserID()
+	if err != nil {
+		return nil, err
+	}
+
+	bot, err := s.telegramBot.GetChatMember(channel.ChatID(), botID)
+	if refused, err := telegramRefused(err); err != nil {
+		return nil, err
+	} else if refused || bot.Status != "administrator" {
+		// Without being an administrator the bot can't see who administers the channel
+		return []entities.ChannelPermission{
+			entities.PermissionBotAdmin, entities.PermissionBotInviteUsers, entities.PermissionBotRestrictMembers,
+		}, nil
+	}
+
+	var missing []entities.ChannelPermission
+	owner, err := s.telegramBot.GetChatMember(channel.ChatID(), channel.UserID)
+	if refused, err := telegramRefused(err); err != nil {
+		return nil, err
+	} else if refused || (owner.Status != "creator" && owner.Status != "administrator") {
+		missing = append(missing, entities.PermissionOwnerAdmin)
+	}
+	if !bot.CanInviteUsers {
+		missing = append(missing, entities.PermissionBotInviteUsers)
+	}
+	if !bot.CanRestrictMembers {
+		missing = append(missing, entities.PermissionBotRestrictMembers)
+	}
+	return missing, nil
+}
+
+// telegramRefused tells a Bot API refusal (e.g. chat not found) apart from a failure to get
+// an answer, which is returned as an error.
+func telegramRefused(err error) (bool, error) {
+	if err == nil {
+		return false, nil
+	}
+	var apiErr *telegram.APIError
+	if errors.As(err, &apiErr) && !apiErr.Temporary() {
+		return true, nil
+	}
+	return false, err
+}
+
+// applyChannelCheck records the outcome of a check and tells the owner when the channel got
+// verified or when no more checks will be made.
+func (s *TributeService) applyChannelCheck(channel *entities.Channel, missing []entities.ChannelPermission, now time.Time) error {
+	wasVerified := channel.IsVerified
+	channel.VerificationCheckedAt = &now
+	channel.MissingPermissions = missing
+	if len(missing) == 0 {
+		channel.IsVerified = true
+		channel.VerificationAttempts = 0
+		channel.NextVerificationAt = nil
+	} else {
+		channel.IsVerified = false
+		channel.VerificationAttempts++
+		channel.NextVerificationAt = nil
+		if channel.VerificationAttempts < s.channelChecks.MaxAttempts {
+			next := now.Add(s.channelChecks.RetryInterval)
+			channel.NextVerificationAt = &next
+		}
+	}
+	if err := s.channels.Update(channel); err != nil {
+		return fmt.Errorf("failed to update channel verification: %w", err)
+	}
+
+	var message string
+	switch {
+	case channel.IsVerified && !wasVerified:
+		message = fmt.Sprintf("Good! You added bot to channel: %s", channel.DisplayName())
+	case !channel.IsVerified && channel.NextVerificationAt == nil:
+		reasons := make([]string, len(missing))
+		for i, permission := range missing {
+			reasons[i] = "• " + permission.Description()
+		}
+		message = fmt.Sprintf("We couldn't verify %s:\n%s\nFix this in Telegram and check the channel again in the app.",
+			channel.DisplayName(), strings.Join(reasons, "\n"))
+	default:
+		return nil
+	}
+	if err := s.telegramBot.SendMessage(channel.UserID, message); err != nil {
+		fmt.Printf("Failed to send channel check result to user %d: %v\n", channel.UserID, err)
+	}
+	return nil
+}
+
+// BotMembershipChange is a change of the bot's own status in a Telegram chat, made by ChangedBy.
+type BotMembershipChange struct {
+	ChatID       int64
+	ChatType     string
+	ChatTitle    string
+	ChatUsername string
+	ChangedBy    int64
+	// Status is the bot's new status: "administrator", "member", "left", "kicked"...
+	Status string
+}
+
+// HandleBotMembership keeps channels in step with the bot's status in them, as reported by
+// Telegram. When the bot is made an administrator of a channel it isn't registered for yet,
+// the channel is added for whoever promoted it and checked right away; a known channel is
+// checked again. When the bot loses its administrator rights the channel is unverified and
+// its owner notified.
+func (s *TributeService) HandleBotMembership(change BotMembershipChange) error {
+	if change.ChatType != "channel" && change.ChatType != "supergroup" {
+		return nil
+	}
+	isAdmin := change.Status == "administrator"
+
+	channel, err := s.findMembershipChannel(change)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		if !isAdmin {
+			return nil
+		}
+		if _, err := s.CreateUser(change.ChangedBy); err != nil {
+			return err
+		}
+		channel = &entities.Channel{UserID: change.ChangedBy}
+	}
+	chatID := change.ChatID
+	channel.TelegramChatID = &chatID
+	channel.ChannelTitle = change.ChatTitle
+	channel.ChannelUsername = change.ChatUsername
+
+	now := time.Now()
+	if !isAdmin {
+		return s.unverifyChannel(channel, now)
+	}
+
+	if channel.ID == uuid.Nil {
+		channel.NextVerificationAt = &now
+		if err := s.channels.Create(channel); err != nil {
+			return fmt.Errorf("failed to register channel %d: %w", change.ChatID, err)
+		}
+	}
+	missing, err := s.checkChannelRights(channel)
+	if err != nil {
+		// The retry worker picks the channel up again
+		channel.NextVerificationAt = &now
+		if updateErr := s.channels.Update(channel); updateErr != nil {
+			return fmt.Errorf("failed to update channel %s: %w", channel.ID, updateErr)
+		}
+		return fmt.Errorf("failed to check channel %s: %w", channel.ID, err)
+	}
+	channel.VerificationAttempts = 0
+	if err := s.applyChannelCheck(channel, missing, now); err != nil {
+		return err
+	}
+	if !channel.IsVerified && channel.NextVerificationAt != nil {
+		s.notifyMissingPermissions(channel)
+	}
+	return nil
+}
+
+// findMembershipChannel returns the channel a membership change is about. Channels added
+// before their chat ID was known are matched by username; an unverified one claimed by
+// another user goes to whoever actually made the bot an administrator.
+func (s *TributeService) findMembershipChannel(change BotMembershipChange) (*entities.Channel, error) {
+	channel, err := s.channels.FindByTelegramChatID(change.ChatID)
+	if err != nil || channel != nil {
+		return channel, err
+	}
+	if change.ChatUsername == "" {
+		return nil, nil
+	}
+
+	channel, err = s.channels.FindByUsername(change.ChatUsername)
+	if err != nil || channel == nil || channel.TelegramChatID != nil {
+		return nil, err
+	}
+	if channel.UserID != change.ChangedBy && !channel.IsVerified && change.Status == "administrator" {
+		if _, err := s.CreateUser(change.ChangedBy); err != nil {
+			return nil, err
+		}
+		channel.UserID = change.ChangedBy
+	}
+	return channel, nil
+}
+
+// unverifyChannel records that the bot is no longer an administrator of the channel. Checks
+// aren't retried until the bot is promoted again, which Telegram reports by itself.
+func (s *TributeService) unverifyChannel(channel *entities.Channel, now time.Time) error {
+	wasVerified := channel.IsVerified
+	channel.IsVerified = false
+	channel.MissingPermissions = []entities.ChannelPermission{
+		entities.PermissionBotAdmin, entities.PermissionBotInviteUsers, entities.PermissionBotRestrictMembers,
+	}
+	channel.VerificationCheckedAt = &now
+	channel.NextVerificationAt = nil
+	if err := s.channels.Update(channel); err != nil {
+		return fmt.Errorf("failed to unverify channel %s: %w", channel.ID, err)
+	}
+
+	if wasVerified {
+		message := fmt.Sprintf("The bot is no longer an administrator of %s, so new subscribers can't join it. Make the bot an administrator again to restore the channel.",
+			channel.DisplayName())
+		if err := s.telegramBot.SendMessage(channel.UserID, message); err != nil {
+			fmt.Printf("Failed to send channel unverified notice to user %d: %v\n", channel.UserID, err)
+		}
+	}
+	return nil
+}
+
+// notifyMissingPermissions tells the owner what still keeps a channel from being verified.
+func (s *TributeService) notifyMissingPermissions(channel *entities.Channel) {
+	reasons := make([]string, len(channel.MissingPermissions))
+	for i, permission := range channel.MissingPermissions {
+		reasons[i] = "• " + permission.Description()
+	}
+	message := fmt.Sprintf("The bot was added to %s, but the channel can't be verified yet:\n%s\nWe'll check again automatically.",
+		channel.DisplayName(), strings.Join(reasons, "\n"))
+	if err := s.telegramBot.SendMessage(channel.UserID, message); err != nil {
+		fmt.Printf("Failed to send channel check result to user %d: %v\n", channel.UserID, err)
+	}
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/payments"
+
+	"github.com/google/uuid"
+)
+
+// ErrSelfSubscription is returned when a creator tries to subscribe to their own tier.
+var ErrSelfSubscription = errors.New("cannot subscribe to yourself")
+
+// ErrInvoiceNotPayable is returned when an invoice refers to a payment that can no longer be paid.
+var ErrInvoiceNotPayable = errors.New("invoice is no longer payable")
+
+// SubscriptionCheckout is a charge for one billing period of a creator's tier.
+type SubscriptionCheckout struct {
+	Payment *entities.Payment
+	// CheckoutURL is where the subscriber pays; empty if the provider settled the charge right away
+	CheckoutURL string
+}
+
+// StartSubscriptionCheckout creates a pending payment for one of the channel's tier prices
+// and charges it through the active payment provider. Without a price ID the channel's
+// default offer is charged, less the discount of promoCode if one is given. The amount
+// always comes from the catalog; access
+// is only granted once the provider reports the payment as successful, which may happen
+// immediately or later through an update or webhook. If notifyPayer is set the provider
+// also sends the payment request to the subscriber directly.
+func (s *TributeService) StartSubscriptionCheckout(subscriberID int64, channelID uuid.UUID, priceID *uuid.UUID, promoCode string, notifyPayer bool) (*SubscriptionCheckout, error) {
+	tier, price, channel, err := s.findCheckoutPrice(channelID, priceID)
+	if err != nil {
+		return nil, err
+	}
+	if tier.UserID == subscriberID {
+		return nil, ErrSelfSubscription
+	}
+
+	provider := s.providers.Active()
+	payment, err := s.createCheckoutPayment(subscriberID, tier, price, promoCode, provider.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	// Private channels have no username and are named by their title
+	name := "@" + channel.ChannelUsername
+	if channel.ChannelUsername == "" {
+		name = channel.ChannelTitle
+	}
+	title := tier.Title
+	if title == "" {
+		title = name
+	}
+	description := tier.Description
+	if description == "" {
+		description = fmt.Sprintf("Доступ к %s %s", name, accessPeriodText(price.Interval))
+	}
+
+	charge, err := provider.CreateCharge(payments.ChargeRequest{
+		Reference:   payment.ID.String(),
+		PayerID:     subscriberID,
+		Amount:      payment.Amount,
+		Title:       title,
+		Description: description,
+		NotifyPayer: notifyPayer,
+	})
+	if err != nil {
+		if failErr := s.MarkPaymentFailed(payment, "charge could not be created"); failErr != nil {
+			fmt.Printf("Failed to mark payment %s as failed: %v\n", payment.ID, failErr)
+		}
+		return nil, fmt.Errorf("failed to create charge: %w", err)
+	}
+
+	switch charge.Status {
+	case payments.ChargeSucceeded:
+		if err := s.completePayment(payment, charge.Amount, charge.ID); err != nil {
+			return nil, err
+		}
+	case payments.ChargeFailed:
+		if err := s.MarkPaymentFailed(payment, charge.FailureReason); err != nil {
+			return nil, err
+		}
+	default:
+		if charge.ID != "" {
+			if err := s.setProviderChargeID(payment, charge.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &SubscriptionCheckout{Payment: payment, CheckoutURL: charge.CheckoutURL}, nil
+}
+
+// createCheckoutPayment creates the pending payment for a checkout, redeeming the promo
+// code if one is given.
+func (s *TributeService) createCheckoutPayment(subscriberID int64, tier *entities.Subscription, price *entities.TierPrice, promoCode, provider string) (*entities.Payment, error) {
+	if promoCode == "" {
+		return s.createPendingPayment(subscriberID, tier, price, nil, money.Money{}, provider)
+	}
+
+	s.promoMu.Lock()
+	defer s.promoMu.Unlock()
+
+	promo, discount, err := s.redeemPromoCode(subscriberID, tier, price, promoCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.createPendingPayment(subscriberID, tier, price, promo, discount, provider)
+}
+
+// invoicePayment loads the payment a Telegram invoice was issued for and checks that it
+// was issued to payerID.
+func (s *TributeService) invoicePayment(payload string, payerID int64) (*entities.Payment, error) {
+	paymentID, err := uuid.Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invoice payload %q", payload)
+	}
+	payment, err := s.payments.FindByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, fmt.Errorf("payment %s not found", paymentID)
+	}
+	if payment.Provider != payments.TelegramProviderName {
+		return nil, fmt.Errorf("payment %s is handled by %s", payment.ID, payment.Provider)
+	}
+	if payment.PayerID != payerID {
+		return nil, fmt.Errorf("payment %s was issued to user %d, not %d", payment.ID, payment.PayerID, payerID)
+	}
+	return payment, nil
+}
+
+// ValidateCheckout decides whether Telegram may charge the user for an invoice.
+func (s *TributeService) ValidateCheckout(payerID int64, payload string, paid money.Money) error {
+	payment, err := s.invoicePayment(payload, payerID)
+	if err != nil {
+		return err
+	}
+	if payment.Status != entities.PaymentPending {
+		return fmt.Errorf("%w: payment %s is %s", ErrInvoiceNotPayable, payment.ID, payment.Status)
+	}
+	if !paid.Equal(payment.Amount) {
+		return fmt.Errorf("%w: payment %s is for %s, got %s", ErrPriceMismatch, payment.ID, payment.Amount, paid)
+	}
+	return nil
+}
+
+// CompleteInvoicePayment confirms the payment a Telegram invoice was issued for and
+// grants the subscriber access to the channel.
+func (s *TributeService) CompleteInvoicePayment(payerID int64, payload string, paid money.Money, chargeID string) error {
+	payment, err := s.invoicePayment(payload, payerID)
+	if err != nil {
+		return err
+	}
+	return s.completePayment(payment, paid, chargeID)
+}
+
+// HandlePaymentEvent applies a change reported asynchronously by a payment provider.
+func (s *TributeService) HandlePaymentEvent(providerName string, event *payments.Event) error {
+	paymentID, err := uuid.Parse(event.Reference)
+	if err != nil {
+		return fmt.Errorf("invalid payment reference %q", event.Reference)
+	}
+	payment, err := s.payments.FindByID(paymentID)
+	if err != nil {
+		return err
+	}
+	if payment == nil {
+		return fmt.Errorf("payment %s not found", paymentID)
+	}
+	if payment.Provider != providerName {
+		return fmt.Errorf("payment %s is handled by %s, not %s", payment.ID, payment.Provider, providerName)
+	}
+
+	switch event.Type {
+	case payments.EventChargeSucceeded:
+		return s.completePayment(payment, event.Amount, event.ChargeID)
+	case payments.EventChargeFailed:
+		// Providers may repeat events; a payment that already failed stays failed
+		if payment.Status == entities.PaymentFailed {
+			return nil
+		}
+		return s.MarkPaymentFailed(payment, event.FailureReason)
+	case payments.EventChargeRefunded:
+		return s.recordChargeback(payment.ID, event)
+	default:
+		return fmt.Errorf("unsupported payment event %s for payment %s", event.Type, payment.ID)
+	}
+}
+
+// completePayment confirms a payment and grants the payer access to the channel it
+// pays for. Providers may report the same payment more than once; repeated reports
+// are ignored. The membership is activated and the creator credited before the payment
+// is marked succeeded, so a report that failed halfway can be repeated until all are done.
+func (s *TributeService) completePayment(payment *entities.Payment, paid money.Money, chargeID string) error {
+	if payment.Status == entities.PaymentSucceeded && payment.ProviderChargeID == chargeID {
+		return nil
+	}
+	if !canTransitionPayment(payment.Status, entities.PaymentSucceeded) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidPaymentTransition, payment.Status, entities.PaymentSucceeded)
+	}
+	if !paid.Equal(payment.Amount) {
+		return fmt.Errorf("%w: payment %s is for %s, got %s", ErrPriceMismatch, payment.ID, payment.Amount, paid)
+	}
+
+	if payment.SubscriptionID == nil {
+		return fmt.Errorf("payment %s has no subscription tier", payment.ID)
+	}
+	tier, err := s.subs.FindByID(*payment.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	if tier == nil {
+		return fmt.Errorf("subscription tier %s not found", *payment.SubscriptionID)
+	}
+	if payment.PriceID == nil {
+		return fmt.Errorf("payment %s has no price", payment.ID)
+	}
+	price, err := s.prices.FindByID(*payment.PriceID)
+	if err != nil {
+		return err
+	}
+	if price == nil {
+		return fmt.Errorf("price %s not found", *payment.PriceID)
+	}
+	channel, err := s.channels.FindByID(tier.ChannelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return fmt.Errorf("channel %s not found", tier.ChannelID)
+	}
+
+	// Grant access for the paid period, unless an earlier report of the payment already did
+	membership, err := s.memberships.FindCurrent(payment.PayerID, tier.ID)
+	if err != nil {
+		return err
+	}
+	if membership == nil || membership.PaymentID == nil || *membership.PaymentID != payment.ID {
+		membership, err = s.Subscribe(payment, tier, price)
+		if err != nil {
+			return fmt.Errorf("failed to activate membership: %w", err)
+		}
+	}
+
+	if err := s.MarkPaymentSucceeded(payment, chargeID); err != nil {
+		return err
+	}
+
+	// The payment is already recorded, so a Telegram failure must not fail the request
+	if err := s.grantChannelAccess(membership, channel); err != nil {
+		fmt.Printf("Failed to grant channel access to user %d: %v\n", payment.PayerID, err)
+	}
+	return nil
+}
+
+// accessPeriodText describes in Russian how long one payment at the interval grants access.
+func accessPeriodText(interval entities.BillingInterval) string {
+	switch interval {
+	case entities.IntervalQuarter:
+		return "на 3 месяца"
+	case entities.IntervalYear:
+		return "на 1 год"
+	case entities.IntervalLifetime:
+		return "навсегда"
+	default:
+		return "на 1 месяц"
+	}
+}
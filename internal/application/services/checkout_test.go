@@ -0,0 +1,270 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/payments"
+	"tribute-back/internal/infrastructure/telegram"
+)
+
+func preCheckoutUpdate(payment *entities.Payment) *telegram.Update {
+	return &telegram.Update{PreCheckoutQuery: &telegram.PreCheckoutQuery{
+		ID:             "query-" + payment.ID.String(),
+		From:           telegram.User{ID: payment.PayerID},
+		Currency:       payment.Amount.Currency,
+		TotalAmount:    payment.Amount.Amount,
+		InvoicePayload: payment.ID.String(),
+	}}
+}
+
+func successfulPaymentUpdate(payment *entities.Payment) *telegram.Update {
+	return &telegram.Update{Message: &telegram.Message{
+		MessageID: 1,
+		From:      &telegram.User{ID: payment.PayerID},
+		Chat:      telegram.Chat{ID: payment.PayerID, Type: "private"},
+		SuccessfulPayment: &telegram.SuccessfulPayment{
+			Currency:                payment.Amount.Currency,
+			TotalAmount:             payment.Amount.Amount,
+			InvoicePayload:          payment.ID.String(),
+			TelegramPaymentChargeID: "tg_charge_" + payment.ID.String(),
+		},
+	}}
+}
+
+// assertActivated checks that the payment succeeded and funded the subscriber's only membership.
+func assertActivated(t *testing.T, env *testEnv, payment *entities.Payment) *entities.Membership {
+	t.Helper()
+	if stored := env.payment(t, payment.ID); stored.Status != entities.PaymentSucceeded {
+		t.Fatalf("payment is %s, want succeeded", stored.Status)
+	}
+	memberships := env.subscriberMemberships(t)
+	if len(memberships) != 1 {
+		t.Fatalf("subscriber has %d memberships, want 1", len(memberships))
+	}
+	membership := memberships[0]
+	if membership.Status != entities.MembershipActive {
+		t.Errorf("membership is %s, want active", membership.Status)
+	}
+	if membership.PaymentID == nil || *membership.PaymentID != payment.ID {
+		t.Errorf("membership was paid by %v, want %s", membership.PaymentID, payment.ID)
+	}
+	return membership
+}
+
+func TestInvoicePaymentActivatesMembership(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+
+	checkout := env.checkout(t)
+	payment := checkout.Payment
+	if payment.Status != entities.PaymentPending || checkout.CheckoutURL == "" {
+		t.Fatalf("checkout is %s with link %q, want a pending payment with an invoice link", payment.Status, checkout.CheckoutURL)
+	}
+	invoices := env.telegram.called("createInvoiceLink")
+	if len(invoices) != 1 || invoices[0].Params["payload"] != payment.ID.String() {
+		t.Fatalf("invoice links = %+v, want one for payment %s", invoices, payment.ID)
+	}
+
+	if err := env.dispatcher.Dispatch(preCheckoutUpdate(payment)); err != nil {
+		t.Fatalf("pre-checkout: %v", err)
+	}
+	answers := env.telegram.called("answerPreCheckoutQuery")
+	if len(answers) != 1 || answers[0].Params["ok"] != true {
+		t.Fatalf("pre-checkout answers = %+v, want one approval", answers)
+	}
+	if len(env.subscriberMemberships(t)) != 0 {
+		t.Fatal("pre-checkout granted access before the payment")
+	}
+
+	if err := env.dispatcher.Dispatch(successfulPaymentUpdate(payment)); err != nil {
+		t.Fatalf("successful payment: %v", err)
+	}
+	membership := assertActivated(t, env, payment)
+
+	links := env.telegram.called("createChatInviteLink")
+	if len(links) != 1 {
+		t.Fatalf("created %d invite links, want 1", len(links))
+	}
+	link := links[0].Params
+	if link["chat_id"] != fmt.Sprint(testChannelChatID) || link["member_limit"] != float64(1) {
+		t.Errorf("invite link params = %v, want a one-time link into the channel", link)
+	}
+	if expireDate := int64(link["expire_date"].(float64)); expireDate > time.Now().Add(24*time.Hour).Unix() {
+		t.Errorf("invite link expires at %d, after the invite link TTL", expireDate)
+	}
+	messages := env.telegram.messagesTo(testSubscriberID)
+	if len(messages) != 1 || membership.InviteLink == "" || !strings.Contains(messages[0], membership.InviteLink) {
+		t.Errorf("messages to subscriber = %q, want the invite link %q", messages, membership.InviteLink)
+	}
+
+	// The invoice can't be paid again
+	if err := env.dispatcher.Dispatch(preCheckoutUpdate(payment)); err == nil {
+		t.Error("pre-checkout of a paid invoice was accepted")
+	}
+	if answers := env.telegram.called("answerPreCheckoutQuery"); len(answers) != 2 || answers[1].Params["ok"] != false {
+		t.Errorf("pre-checkout answers = %+v, want the second rejected", answers)
+	}
+
+	// A redelivered successful_payment changes nothing
+	if err := env.dispatcher.Dispatch(successfulPaymentUpdate(payment)); err != nil {
+		t.Fatalf("redelivered successful payment: %v", err)
+	}
+	again := assertActivated(t, env, payment)
+	if !again.CurrentPeriodEnd.Equal(*membership.CurrentPeriodEnd) {
+		t.Errorf("redelivery moved the period end from %s to %s", membership.CurrentPeriodEnd, again.CurrentPeriodEnd)
+	}
+	if links := env.telegram.called("createChatInviteLink"); len(links) != 1 {
+		t.Errorf("created %d invite links after redelivery, want 1", len(links))
+	}
+}
+
+func TestPreCheckoutRejectsWrongAmount(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	payment := env.checkout(t).Payment
+
+	update := preCheckoutUpdate(payment)
+	update.PreCheckoutQuery.TotalAmount--
+	if err := env.dispatcher.Dispatch(update); err == nil {
+		t.Fatal("pre-checkout for a lower amount was accepted")
+	}
+	if answers := env.telegram.called("answerPreCheckoutQuery"); len(answers) != 1 || answers[0].Params["ok"] != false {
+		t.Errorf("pre-checkout answers = %+v, want a rejection", answers)
+	}
+}
+
+func TestSuccessfulPaymentRetriedAfterPartialFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		// fail breaks one write of the first attempt
+		fail func(env *testEnv)
+		// activated is whether the first attempt got as far as the membership
+		activated bool
+	}{
+		{name: "membership write fails", fail: func(env *testEnv) { env.memberships.failWrites = 1 }},
+		{name: "ledger write fails", fail: func(env *testEnv) { env.ledger.failPosts = 1 }, activated: true},
+		{name: "payment update fails", fail: func(env *testEnv) { env.payments.failUpdates = 1 }, activated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, testOptions{})
+			payment := env.checkout(t).Payment
+
+			tt.fail(env)
+			if err := env.dispatcher.Dispatch(successfulPaymentUpdate(payment)); err == nil {
+				t.Fatal("first attempt succeeded despite the failure")
+			}
+			if alerts := env.telegram.messagesTo(testAdminChatID); len(alerts) != 1 {
+				t.Errorf("admin alerts = %q, want one about the unprocessed payment", alerts)
+			}
+			if stored := env.payment(t, payment.ID); stored.Status != entities.PaymentPending {
+				t.Fatalf("payment is %s after the failed attempt, want pending", stored.Status)
+			}
+			var firstPeriodEnd *time.Time
+			if memberships := env.subscriberMemberships(t); tt.activated {
+				if len(memberships) != 1 {
+					t.Fatalf("subscriber has %d memberships after the failed attempt, want 1", len(memberships))
+				}
+				firstPeriodEnd = memberships[0].CurrentPeriodEnd
+			} else if len(memberships) != 0 {
+				t.Fatalf("subscriber has %d memberships after the failed attempt, want 0", len(memberships))
+			}
+
+			if err := env.dispatcher.Dispatch(successfulPaymentUpdate(payment)); err != nil {
+				t.Fatalf("retry: %v", err)
+			}
+			membership := assertActivated(t, env, payment)
+			if firstPeriodEnd != nil && !membership.CurrentPeriodEnd.Equal(*firstPeriodEnd) {
+				t.Errorf("retry extended the period from %s to %s", firstPeriodEnd, membership.CurrentPeriodEnd)
+			}
+			if links := env.telegram.called("createChatInviteLink"); len(links) != 1 {
+				t.Errorf("created %d invite links, want 1", len(links))
+			}
+			balance, err := env.service.ledger.CreatorBalance(testCreatorID, "RUB")
+			if err != nil {
+				t.Fatalf("CreatorBalance: %v", err)
+			}
+			if want := money.New(17910, "RUB"); !balance.Equal(want) {
+				t.Errorf("creator balance = %s, want %s", balance, want)
+			}
+		})
+	}
+}
+
+func TestSimulatorChargeOutcomes(t *testing.T) {
+	t.Run("succeeds", func(t *testing.T) {
+		env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName})
+		checkout := env.checkout(t)
+		if checkout.CheckoutURL != "" {
+			t.Errorf("checkout link = %q, want none for a settled charge", checkout.CheckoutURL)
+		}
+		assertActivated(t, env, checkout.Payment)
+	})
+
+	t.Run("declines", func(t *testing.T) {
+		env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName, price: money.New(19902, "RUB")})
+		payment := env.payment(t, env.checkout(t).Payment.ID)
+		if payment.Status != entities.PaymentFailed || payment.FailureReason != "card_declined" {
+			t.Fatalf("payment is %s (%q), want failed with card_declined", payment.Status, payment.FailureReason)
+		}
+		if memberships := env.subscriberMemberships(t); len(memberships) != 0 {
+			t.Errorf("declined payment created %d memberships", len(memberships))
+		}
+		if links := env.telegram.called("createChatInviteLink"); len(links) != 0 {
+			t.Errorf("declined payment created %d invite links", len(links))
+		}
+	})
+
+	t.Run("delays until confirmed", func(t *testing.T) {
+		env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName, price: money.New(19903, "RUB")})
+		payment := env.checkout(t).Payment
+		if stored := env.payment(t, payment.ID); stored.Status != entities.PaymentPending {
+			t.Fatalf("delayed payment is %s, want pending", stored.Status)
+		}
+		if memberships := env.subscriberMemberships(t); len(memberships) != 0 {
+			t.Fatalf("pending payment created %d memberships", len(memberships))
+		}
+
+		if _, err := env.simulator.Capture(env.payment(t, payment.ID).ProviderChargeID); err != nil {
+			t.Fatalf("Capture: %v", err)
+		}
+		assertActivated(t, env, payment)
+	})
+
+	t.Run("delays and confirms itself", func(t *testing.T) {
+		env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName, price: money.New(19903, "RUB"), simulatorDelay: 10 * time.Millisecond})
+		payment := env.checkout(t).Payment
+
+		deadline := time.Now().Add(2 * time.Second)
+		for env.payment(t, payment.ID).Status == entities.PaymentPending {
+			if time.Now().After(deadline) {
+				t.Fatal("delayed payment was never confirmed")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		assertActivated(t, env, payment)
+	})
+
+	t.Run("delays and fails", func(t *testing.T) {
+		env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName, price: money.New(19903, "RUB")})
+		payment := env.checkout(t).Payment
+
+		err := env.service.HandlePaymentEvent(payments.SimulatorProviderName, &payments.Event{
+			Type:          payments.EventChargeFailed,
+			ChargeID:      env.payment(t, payment.ID).ProviderChargeID,
+			Reference:     payment.ID.String(),
+			FailureReason: "insufficient_funds",
+		})
+		if err != nil {
+			t.Fatalf("HandlePaymentEvent: %v", err)
+		}
+		if stored := env.payment(t, payment.ID); stored.Status != entities.PaymentFailed {
+			t.Fatalf("payment is %s, want failed", stored.Status)
+		}
+		if memberships := env.subscriberMemberships(t); len(memberships) != 0 {
+			t.Errorf("failed payment created %d memberships", len(memberships))
+		}
+	})
+}
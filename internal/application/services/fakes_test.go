@@ -0,0 +1,693 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"tribute-back/internal/config"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/domain/repositories"
+	"tribute-back/internal/infrastructure/payments"
+	"tribute-back/internal/infrastructure/payouts"
+	"tribute-back/internal/infrastructure/telegram"
+	"tribute-back/internal/infrastructure/vault"
+
+	"github.com/google/uuid"
+)
+
+// IDs of the Telegram chats and users the tests are set in.
+const (
+	testAdminChatID   = int64(-100)
+	testChannelChatID = int64(-1001234567890)
+	testCreatorID     = int64(100)
+	testSubscriberID  = int64(200)
+)
+
+// errStorage is returned by repositories that were told to fail.
+var errStorage = errors.New("storage unavailable")
+
+// botCall is one Bot API method call received by fakeTelegram.
+type botCall struct {
+	Method string
+	Params map[string]interface{}
+}
+
+// fakeTelegram is a Bot API server that records the methods called on it and answers
+// them like Telegram would. Methods can be made to fail a number of times.
+type fakeTelegram struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	calls    []botCall
+	failures map[string]int
+	links    int
+}
+
+func newFakeTelegram(t *testing.T) *fakeTelegram {
+	f := &fakeTelegram{failures: make(map[string]int)}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeTelegram) serve(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	var params map[string]interface{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		json.NewDecoder(r.Body).Decode(&params)
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, botCall{Method: method, Params: params})
+	failing := f.failures[method] > 0
+	if failing {
+		f.failures[method]--
+	}
+	var result interface{} = true
+	switch method {
+	case "createChatInviteLink":
+		f.links++
+		result = map[string]interface{}{"invite_link": fmt.Sprintf("https://t.me/+invite%d", f.links), "name": params["name"]}
+	case "createInvoiceLink":
+		f.links++
+		result = fmt.Sprintf("https://t.me/$invoice%d", f.links)
+	case "getMe":
+		result = map[string]interface{}{"id": 1, "is_bot": true}
+	}
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if failing {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error_code": 400, "description": "Bad Request: " + method + " failed"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": result})
+}
+
+// fail makes the next times calls of the method fail.
+func (f *fakeTelegram) fail(method string, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[method] = times
+}
+
+// called returns the calls of a method, oldest first.
+func (f *fakeTelegram) called(method string) []botCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []botCall
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// methods returns the names of the methods called, oldest first.
+func (f *fakeTelegram) methods() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, len(f.calls))
+	for i, call := range f.calls {
+		names[i] = call.Method
+	}
+	return names
+}
+
+// messagesTo returns the texts sent to a chat, oldest first.
+func (f *fakeTelegram) messagesTo(chatID int64) []string {
+	var texts []string
+	for _, call := range f.called("sendMessage") {
+		if id, _ := call.Params["chat_id"].(float64); int64(id) == chatID {
+			texts = append(texts, call.Params["text"].(string))
+		}
+	}
+	return texts
+}
+
+// newTestBot creates a BotService that talks to the fake server.
+func newTestBot(t *testing.T, fake *fakeTelegram) *telegram.BotService {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123456789:test-token")
+	t.Setenv("TELEGRAM_ADMIN_CHAT_ID", fmt.Sprint(testAdminChatID))
+	t.Setenv("TELEGRAM_API_URL", fake.server.URL)
+	t.Setenv("TELEGRAM_PAYMENT_PROVIDER_TOKEN", "provider-token")
+	t.Setenv("TELEGRAM_BOT_USERNAME", "tribute_test_bot")
+	bot, err := telegram.NewBotService()
+	if err != nil {
+		t.Fatalf("NewBotService: %v", err)
+	}
+	return bot
+}
+
+// testEnv is a TributeService backed by in-memory repositories and the fake Bot API, with
+// a verified channel whose creator sells a monthly tier.
+type testEnv struct {
+	service     *TributeService
+	dispatcher  *UpdateDispatcher
+	telegram    *fakeTelegram
+	simulator   *payments.Simulator
+	users       *memUsers
+	payments    *memPayments
+	memberships *memMemberships
+	refunds     *memRefunds
+	ledger      *memLedger
+	payouts     *memPayouts
+	channel     *entities.Channel
+	tier        *entities.Subscription
+	price       *entities.TierPrice
+}
+
+// testOptions configures newTestEnv.
+type testOptions struct {
+	// provider is the active payment provider; telegram by default
+	provider string
+	// price is what the tier costs a month; 199.00 RUB by default
+	price          money.Money
+	simulatorDelay time.Duration
+	payouts        payouts.MockConfig
+	payoutAttempts int
+}
+
+func newTestEnv(t *testing.T, opts testOptions) *testEnv {
+	t.Helper()
+	if opts.provider == "" {
+		opts.provider = payments.TelegramProviderName
+	}
+	if opts.price.Currency == "" {
+		opts.price = money.New(19900, "RUB")
+	}
+	if opts.payouts.FailureMode == "" {
+		opts.payouts.FailureMode = payouts.MockNoFailure
+	}
+	if opts.payoutAttempts == 0 {
+		opts.payoutAttempts = 3
+	}
+
+	fake := newFakeTelegram(t)
+	bot := newTestBot(t, fake)
+	env := &testEnv{
+		telegram:    fake,
+		simulator:   payments.NewSimulator("simulator-secret", opts.simulatorDelay),
+		users:       &memUsers{users: make(map[int64]*entities.User)},
+		memberships: &memMemberships{memberships: make(map[uuid.UUID]*entities.Membership)},
+		refunds:     &memRefunds{refunds: make(map[uuid.UUID]*entities.Refund)},
+		payouts:     &memPayouts{payouts: make(map[uuid.UUID]*entities.Payout)},
+	}
+	channels := &memChannels{channels: make(map[uuid.UUID]*entities.Channel)}
+	subs := &memSubs{subs: make(map[uuid.UUID]*entities.Subscription)}
+	prices := &memPrices{prices: make(map[uuid.UUID]*entities.TierPrice)}
+	env.payments = &memPayments{payments: make(map[uuid.UUID]*entities.Payment), subs: subs}
+
+	env.ledger = newMemLedger()
+	ledger, err := NewLedgerService(env.ledger, 1000)
+	if err != nil {
+		t.Fatalf("NewLedgerService: %v", err)
+	}
+	registry, err := payments.NewRegistry(opts.provider, payments.NewTelegramProvider(bot), env.simulator)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	cardVault, err := vault.New(&memVault{records: make(map[string]*entities.VaultRecord)},
+		map[string]string{"test": base64.StdEncoding.EncodeToString(make([]byte, 32))}, "test")
+	if err != nil {
+		t.Fatalf("vault.New: %v", err)
+	}
+
+	env.service = NewTributeService(env.users, channels, subs, prices, env.payments, env.refunds, nil, &memStartLinks{}, &memReferrals{},
+		env.memberships, env.payouts, nil, nil, ledger, bot, telegram.NewMemoryAlertThrottle(time.Minute), registry,
+		payouts.NewMockGateway(opts.payouts), PayoutPolicy{MinAmounts: map[string]money.Money{}, MaxAttempts: opts.payoutAttempts},
+		cardVault, nil, nil, config.BillingConfig{Currency: "RUB", InviteLinkTTL: 24 * time.Hour, TrialReminderBefore: 24 * time.Hour,
+			RenewalInvoiceBefore: 72 * time.Hour, ReferralAttributionWindow: time.Hour},
+		config.ChannelCheckConfig{})
+	env.dispatcher = NewUpdateDispatcher(env.service, bot)
+	env.simulator.SetNotifier(func(event *payments.Event) {
+		if err := env.service.HandlePaymentEvent(payments.SimulatorProviderName, event); err != nil {
+			t.Errorf("HandlePaymentEvent: %v", err)
+		}
+	})
+
+	now := time.Now()
+	chatID := testChannelChatID
+	env.users.Create(&entities.User{ID: testCreatorID, IsVerified: true})
+	env.users.Create(&entities.User{ID: testSubscriberID})
+	env.channel = &entities.Channel{ID: uuid.New(), UserID: testCreatorID, ChannelTitle: "Test Channel", ChannelUsername: "testchannel",
+		TelegramChatID: &chatID, IsVerified: true}
+	channels.channels[env.channel.ID] = env.channel
+	env.tier = &entities.Subscription{ID: uuid.New(), ChannelID: env.channel.ID, UserID: testCreatorID, Title: "Supporter", CreatedDate: now}
+	subs.subs[env.tier.ID] = env.tier
+	env.price = &entities.TierPrice{ID: uuid.New(), SubscriptionID: env.tier.ID, Interval: entities.IntervalMonth, Price: opts.price, CreatedDate: now}
+	prices.prices[env.price.ID] = env.price
+	return env
+}
+
+// checkout starts a checkout of the tier's price for the subscriber.
+func (e *testEnv) checkout(t *testing.T) *SubscriptionCheckout {
+	t.Helper()
+	checkout, err := e.service.StartSubscriptionCheckout(testSubscriberID, e.channel.ID, &e.price.ID, "", false)
+	if err != nil {
+		t.Fatalf("StartSubscriptionCheckout: %v", err)
+	}
+	return checkout
+}
+
+// payment returns the stored state of a payment.
+func (e *testEnv) payment(t *testing.T, id uuid.UUID) *entities.Payment {
+	t.Helper()
+	payment, err := e.payments.FindByID(id)
+	if err != nil || payment == nil {
+		t.Fatalf("payment %s not found: %v", id, err)
+	}
+	return payment
+}
+
+// subscriberMemberships returns the subscriber's stored memberships.
+func (e *testEnv) subscriberMemberships(t *testing.T) []*entities.Membership {
+	t.Helper()
+	memberships, err := e.memberships.FindBySubscriberID(testSubscriberID)
+	if err != nil {
+		t.Fatalf("FindBySubscriberID: %v", err)
+	}
+	return memberships
+}
+
+// The in-memory repositories keep copies of what they are given, like a database would,
+// so that changes a service makes to an entity are only seen once they are saved.
+// Methods the tests don't need are left to the embedded interface and panic if called.
+
+type memUsers struct {
+	repositories.UserRepository
+	mu    sync.Mutex
+	users map[int64]*entities.User
+}
+
+func (r *memUsers) FindByID(id int64) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.users[id]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memUsers) Create(user *entities.User) error {
+	return r.Update(user)
+}
+
+func (r *memUsers) Update(user *entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+type memChannels struct {
+	repositories.ChannelRepository
+	channels map[uuid.UUID]*entities.Channel
+}
+
+func (r *memChannels) FindByID(id uuid.UUID) (*entities.Channel, error) {
+	if channel, ok := r.channels[id]; ok {
+		copied := *channel
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+type memSubs struct {
+	repositories.SubscriptionRepository
+	subs map[uuid.UUID]*entities.Subscription
+}
+
+func (r *memSubs) FindByID(id uuid.UUID) (*entities.Subscription, error) {
+	if sub, ok := r.subs[id]; ok {
+		copied := *sub
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+type memPrices struct {
+	repositories.TierPriceRepository
+	prices map[uuid.UUID]*entities.TierPrice
+}
+
+func (r *memPrices) FindByID(id uuid.UUID) (*entities.TierPrice, error) {
+	if price, ok := r.prices[id]; ok {
+		copied := *price
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+type memPayments struct {
+	repositories.PaymentRepository
+	mu       sync.Mutex
+	payments map[uuid.UUID]*entities.Payment
+	subs     *memSubs
+	// failUpdates makes the next updates fail
+	failUpdates int
+}
+
+func (r *memPayments) FindByID(id uuid.UUID) (*entities.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if payment, ok := r.payments[id]; ok {
+		copied := *payment
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memPayments) FindByPayerID(payerID int64) ([]*entities.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*entities.Payment
+	for _, payment := range r.payments {
+		if payment.PayerID == payerID {
+			copied := *payment
+			found = append(found, &copied)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].CreatedDate.After(found[j].CreatedDate) })
+	return found, nil
+}
+
+func (r *memPayments) HasPaidForChannel(payerID int64, channelID uuid.UUID, exceptID uuid.UUID) (bool, error) {
+	payments, _ := r.FindByPayerID(payerID)
+	for _, payment := range payments {
+		paid := payment.Status == entities.PaymentSucceeded || payment.Status == entities.PaymentRefunded
+		if !paid || payment.ID == exceptID || payment.SubscriptionID == nil {
+			continue
+		}
+		if sub, _ := r.subs.FindByID(*payment.SubscriptionID); sub != nil && sub.ChannelID == channelID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memPayments) Create(payment *entities.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if payment.ID == uuid.Nil {
+		payment.ID = uuid.New()
+	}
+	copied := *payment
+	r.payments[payment.ID] = &copied
+	return nil
+}
+
+func (r *memPayments) Update(payment *entities.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failUpdates > 0 {
+		r.failUpdates--
+		return errStorage
+	}
+	copied := *payment
+	r.payments[payment.ID] = &copied
+	return nil
+}
+
+type memRefunds struct {
+	mu      sync.Mutex
+	refunds map[uuid.UUID]*entities.Refund
+}
+
+func (r *memRefunds) FindByPaymentID(paymentID uuid.UUID) ([]*entities.Refund, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*entities.Refund
+	for _, refund := range r.refunds {
+		if refund.PaymentID == paymentID {
+			copied := *refund
+			found = append(found, &copied)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].CreatedAt.Before(found[j].CreatedAt) })
+	return found, nil
+}
+
+func (r *memRefunds) FindByIdempotencyKey(paymentID uuid.UUID, key string) (*entities.Refund, error) {
+	refunds, _ := r.FindByPaymentID(paymentID)
+	for _, refund := range refunds {
+		if refund.IdempotencyKey == key {
+			return refund, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRefunds) Create(refund *entities.Refund) error {
+	refund.ID = uuid.New()
+	return r.Update(refund)
+}
+
+func (r *memRefunds) Update(refund *entities.Refund) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *refund
+	r.refunds[refund.ID] = &copied
+	return nil
+}
+
+type memMemberships struct {
+	repositories.MembershipRepository
+	mu          sync.Mutex
+	memberships map[uuid.UUID]*entities.Membership
+	// failWrites makes the next creates and updates fail
+	failWrites int
+}
+
+func (r *memMemberships) find(match func(m *entities.Membership) bool) []*entities.Membership {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*entities.Membership
+	for _, membership := range r.memberships {
+		if match(membership) {
+			copied := *membership
+			found = append(found, &copied)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].StartedAt.After(found[j].StartedAt) })
+	return found
+}
+
+func (r *memMemberships) FindByID(id uuid.UUID) (*entities.Membership, error) {
+	found := r.find(func(m *entities.Membership) bool { return m.ID == id })
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+
+func (r *memMemberships) FindBySubscriberID(subscriberID int64) ([]*entities.Membership, error) {
+	return r.find(func(m *entities.Membership) bool { return m.SubscriberID == subscriberID }), nil
+}
+
+func (r *memMemberships) FindCurrent(subscriberID int64, subscriptionID uuid.UUID) (*entities.Membership, error) {
+	found := r.find(func(m *entities.Membership) bool {
+		return m.SubscriberID == subscriberID && m.SubscriptionID == subscriptionID && m.Status != entities.MembershipExpired
+	})
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+
+func (r *memMemberships) FindDueForExpiry(now time.Time) ([]*entities.Membership, error) {
+	return r.find(func(m *entities.Membership) bool {
+		return m.Status != entities.MembershipExpired && m.CurrentPeriodEnd != nil && !m.CurrentPeriodEnd.After(now)
+	}), nil
+}
+
+func (r *memMemberships) FindDueForRenewal(before time.Time) ([]*entities.Membership, error) {
+	return r.find(func(m *entities.Membership) bool {
+		return m.Status == entities.MembershipActive && m.RenewalInvoicedAt == nil && m.CurrentPeriodEnd != nil && !m.CurrentPeriodEnd.After(before)
+	}), nil
+}
+
+func (r *memMemberships) Create(membership *entities.Membership) error {
+	membership.ID = uuid.New()
+	return r.Update(membership)
+}
+
+func (r *memMemberships) Update(membership *entities.Membership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failWrites > 0 {
+		r.failWrites--
+		return errStorage
+	}
+	copied := *membership
+	r.memberships[membership.ID] = &copied
+	return nil
+}
+
+type memLedger struct {
+	mu       sync.Mutex
+	accounts []*entities.LedgerAccount
+	entries  []*entities.JournalEntry
+	// failPosts makes the next posts fail
+	failPosts int
+}
+
+func newMemLedger() *memLedger {
+	return &memLedger{}
+}
+
+func sameOwner(a, b *int64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func (r *memLedger) FindOrCreateAccount(accountType entities.LedgerAccountType, ownerID *int64, currency string) (*entities.LedgerAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, account := range r.accounts {
+		if account.Type == accountType && account.Currency == currency && sameOwner(account.OwnerID, ownerID) {
+			return account, nil
+		}
+	}
+	account := &entities.LedgerAccount{ID: uuid.New(), Type: accountType, OwnerID: ownerID, Currency: currency, CreatedAt: time.Now()}
+	r.accounts = append(r.accounts, account)
+	return account, nil
+}
+
+func (r *memLedger) PostEntry(entry *entities.JournalEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failPosts > 0 {
+		r.failPosts--
+		return errStorage
+	}
+	entry.ID = uuid.New()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *memLedger) FindEntryByReference(kind, reference string) (*entities.JournalEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range r.entries {
+		if entry.Kind == kind && entry.Reference == reference {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memLedger) Balance(accountType entities.LedgerAccountType, ownerID *int64, currency string) (money.Money, error) {
+	account, _ := r.FindOrCreateAccount(accountType, ownerID, currency)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	balance := money.Zero(currency)
+	for _, entry := range r.entries {
+		for _, posting := range entry.Postings {
+			if posting.AccountID == account.ID {
+				balance.Amount += posting.Amount.Amount
+			}
+		}
+	}
+	return balance, nil
+}
+
+func (r *memLedger) FindUnbalancedEntries() ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unbalanced []uuid.UUID
+	for _, entry := range r.entries {
+		var sum int64
+		for _, posting := range entry.Postings {
+			sum += posting.Amount.Amount
+		}
+		if sum != 0 {
+			unbalanced = append(unbalanced, entry.ID)
+		}
+	}
+	return unbalanced, nil
+}
+
+type memReferrals struct {
+	repositories.ReferralRepository
+}
+
+func (r *memReferrals) FindBySubscriber(channelID uuid.UUID, subscriberID int64) (*entities.Referral, error) {
+	return nil, nil
+}
+
+type memStartLinks struct {
+	repositories.StartLinkRepository
+}
+
+func (r *memStartLinks) FindLastReferralClick(channelID uuid.UUID, userID int64, since time.Time) (*entities.StartLink, error) {
+	return nil, nil
+}
+
+type memPayouts struct {
+	repositories.PayoutRepository
+	mu      sync.Mutex
+	payouts map[uuid.UUID]*entities.Payout
+}
+
+func (r *memPayouts) FindByID(id uuid.UUID) (*entities.Payout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if payout, ok := r.payouts[id]; ok {
+		copied := *payout
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memPayouts) FindByStatus(status entities.PayoutStatus) ([]*entities.Payout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*entities.Payout
+	for _, payout := range r.payouts {
+		if payout.Status == status {
+			copied := *payout
+			found = append(found, &copied)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].CreatedAt.Before(found[j].CreatedAt) })
+	return found, nil
+}
+
+func (r *memPayouts) Create(payout *entities.Payout) error {
+	return r.Update(payout)
+}
+
+func (r *memPayouts) Update(payout *entities.Payout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *payout
+	r.payouts[payout.ID] = &copied
+	return nil
+}
+
+type memVault struct {
+	repositories.VaultRepository
+	records map[string]*entities.VaultRecord
+}
+
+func (r *memVault) FindByToken(token string) (*entities.VaultRecord, error) {
+	return r.records[token], nil
+}
+
+func (r *memVault) Create(record *entities.VaultRecord) error {
+	r.records[record.Token] = record
+	return nil
+}
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/domain/repositories"
+)
+
+// ErrInsufficientFunds is returned when a payout exceeds the creator's balance.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// bpsDenominator is 100% expressed in basis points.
+const bpsDenominator = 10000
+
+// LedgerService records money movements as balanced journal entries. Creator
+// balances are never stored directly; they are the sum of the postings.
+type LedgerService struct {
+	ledger        repositories.LedgerRepository
+	commissionBPS int64
+	// payoutMu keeps concurrent payouts from spending the same balance twice
+	payoutMu sync.Mutex
+}
+
+func NewLedgerService(ledger repositories.LedgerRepository, commissionBPS int) (*LedgerService, error) {
+	if commissionBPS < 0 || commissionBPS > bpsDenominator {
+		return nil, fmt.Errorf("platform commission must be between 0 and %d basis points, got %d", bpsDenominator, commissionBPS)
+	}
+	return &LedgerService{ledger: ledger, commissionBPS: int64(commissionBPS)}, nil
+}
+
+// Commission returns the platform's share of a gross amount, rounded half up to the minor unit.
+func (l *LedgerService) Commission(gross money.Money) money.Money {
+	return money.New((gross.Amount*l.commissionBPS+bpsDenominator/2)/bpsDenominator, gross.Currency)
+}
+
+// ReferralCredit is the part of a subscription payment that goes to the referrer who brought the subscriber.
+type ReferralCredit struct {
+	ReferrerID int64
+	Amount     money.Money
+}
+
+// ReferralCommission returns a referrer's share of what the creator earns from a gross
+// amount, i.e. of the amount minus the platform commission, rounded half up to the minor unit.
+func (l *LedgerService) ReferralCommission(gross money.Money, shareBPS int) money.Money {
+	net := gross.Amount - l.Commission(gross).Amount
+	return money.New((net*int64(shareBPS)+bpsDenominator/2)/bpsDenominator, gross.Currency)
+}
+
+// RecordSubscriptionPayment credits the creator with the gross amount minus the platform
+// commission and, if the subscriber was referred, minus the referrer's credit, which goes
+// to the referrer's balance. Recording the same reference twice is a no-op.
+func (l *LedgerService) RecordSubscriptionPayment(reference string, creatorID int64, gross money.Money, referral *ReferralCredit) (*entities.JournalEntry, error) {
+	if !gross.IsPositive() {
+		return nil, fmt.Errorf("payment amount must be positive, got %s", gross)
+	}
+
+	existing, err := l.ledger.FindEntryByReference(entities.JournalSubscriptionPayment, reference)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	fee := l.Commission(gross)
+	net, err := gross.Sub(fee)
+	if err != nil {
+		return nil, err
+	}
+	specs := []postingSpec{
+		{entities.LedgerPaymentsClearing, nil, -gross.Amount},
+		{entities.LedgerPlatformRevenue, nil, fee.Amount},
+	}
+	description := fmt.Sprintf("Subscription payment of %s to user %d (commission %s)", gross, creatorID, fee)
+	if referral != nil {
+		if !referral.Amount.SameCurrency(gross) || referral.Amount.Amount < 0 || referral.Amount.Amount > net.Amount {
+			return nil, fmt.Errorf("referral credit of %s exceeds the creator's %s", referral.Amount, net)
+		}
+		net = money.New(net.Amount-referral.Amount.Amount, net.Currency)
+		specs = append(specs, postingSpec{entities.LedgerCreatorBalance, &referral.ReferrerID, referral.Amount.Amount})
+		description = fmt.Sprintf("Subscription payment of %s to user %d (commission %s, referral share %s to user %d)",
+			gross, creatorID, fee, referral.Amount, referral.ReferrerID)
+	}
+	specs = append(specs, postingSpec{entities.LedgerCreatorBalance, &creatorID, net.Amount})
+
+	postings, err := l.postings(gross.Currency, specs)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entities.JournalEntry{
+		Kind:        entities.JournalSubscriptionPayment,
+		Reference:   reference,
+		Description: description,
+		CreatedAt:   time.Now(),
+		Postings:    postings,
+	}
+	return entry, l.post(entry)
+}
+
+// RecordPayout debits the creator's balance for money sent out to them. It is recorded
+// when the payout is requested, so the amount can't be withdrawn twice while the payout
+// is in flight. Recording the same reference twice is a no-op.
+func (l *LedgerService) RecordPayout(reference string, creatorID int64, amount money.Money) (*entities.JournalEntry, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("payout amount must be positive, got %s", amount)
+	}
+
+	l.payoutMu.Lock()
+	defer l.payoutMu.Unlock()
+
+	existing, err := l.ledger.FindEntryByReference(entities.JournalPayout, reference)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	balance, err := l.CreatorBalance(creatorID, amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Amount < amount.Amount {
+		return nil, fmt.Errorf("%w: balance is %s, payout is %s", ErrInsufficientFunds, balance, amount)
+	}
+
+	postings, err := l.postings(amount.Currency, []postingSpec{
+		{entities.LedgerCreatorBalance, &creatorID, -amount.Amount},
+		{entities.LedgerPayoutsClearing, nil, amount.Amount},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entities.JournalEntry{
+		Kind:        entities.JournalPayout,
+		Reference:   reference,
+		Description: fmt.Sprintf("Payout of %s to user %d", amount, creatorID),
+		CreatedAt:   time.Now(),
+		Postings:    postings,
+	}
+	return entry, l.post(entry)
+}
+
+// ReversePayout credits the creator back with a payout that didn't go through.
+// Reversing the same payout twice is a no-op.
+func (l *LedgerService) ReversePayout(reference string, creatorID int64, amount money.Money) (*entities.JournalEntry, error) {
+	existing, err := l.ledger.FindEntryByReference(entities.JournalPayoutReversal, reference)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	payout, err := l.ledger.FindEntryByReference(entities.JournalPayout, reference)
+	if err != nil {
+		return nil, err
+	}
+	if payout == nil {
+		return nil, fmt.Errorf("payout %s was never recorded", reference)
+	}
+
+	postings, err := l.postings(amount.Currency, []postingSpec{
+		{entities.LedgerPayoutsClearing, nil, -amount.Amount},
+		{entities.LedgerCreatorBalance, &creatorID, amount.Amount},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entities.JournalEntry{
+		Kind:        entities.JournalPayoutReversal,
+		Reference:   reference,
+		Description: fmt.Sprintf("Reversal of failed payout of %s to user %d", amount, creatorID),
+		CreatedAt:   time.Now(),
+		Postings:    postings,
+	}
+	return entry, l.post(entry)
+}
+
+// SettlePayout records that the gateway paid out a payout: the money leaves the funds
+// received from subscribers and the payout is cleared. Settling the same payout twice is a no-op.
+func (l *LedgerService) SettlePayout(reference string, creatorID int64, amount money.Money) (*entities.JournalEntry, error) {
+	existing, err := l.ledger.FindEntryByReference(entities.JournalPayoutSettlement, reference)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	payout, err := l.ledger.FindEntryByReference(entities.JournalPayout, reference)
+	if err != nil {
+		return nil, err
+	}
+	if payout == nil {
+		return nil, fmt.Errorf("payout %s was never recorded", reference)
+	}
+
+	postings, err := l.postings(amount.Currency, []postingSpec{
+		{entities.LedgerPayoutsClearing, nil, -amount.Amount},
+		{entities.LedgerPaymentsClearing, nil, amount.Amount},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entities.JournalEntry{
+		Kind:        entities.JournalPayoutSettlement,
+		Reference:   reference,
+		Description: fmt.Sprintf("Settlement of payout of %s to user %d", amount, creatorID),
+		CreatedAt:   time.Now(),
+		Postings:    postings,
+	}
+	return entry, l.post(entry)
+}
+
+// RecordRefund takes a refund of a subscription payment back out of the creator's balance,
+// the platform commission and the referrer's share, if any. The commission and the
+// referrer give back their credit in proportion to the share of the payment refunded; the
+// creator covers the rest. refundedBefore is what had been refunded of the payment before
+// this refund; the shares are computed on the running total so that a payment refunded in
+// parts reverses exactly what it credited. Balances may go negative if the money was
+// already paid out. Recording the same reference twice is a no-op.
+func (l *LedgerService) RecordRefund(reference, paymentReference string, creatorID int64, paid, refundedBefore, amount money.Money) (*entities.JournalEntry, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("refund amount must be positive, got %s", amount)
+	}
+	if !paid.SameCurrency(amount) || !paid.SameCurrency(refundedBefore) || refundedBefore.Amount+amount.Amount > paid.Amount {
+		return nil, fmt.Errorf("cannot refund %s of payment %s of %s, %s already refunded", amount, paymentReference, paid, refundedBefore)
+	}
+
+	existing, err := l.ledger.FindEntryByReference(entities.JournalRefund, reference)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	payment, err := l.ledger.FindEntryByReference(entities.JournalSubscriptionPayment, paymentReference)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, fmt.Errorf("payment %s was never recorded", paymentReference)
+	}
+	accounts := make(map[entities.LedgerAccountType]*entities.LedgerAccount)
+	for accountType, ownerID := range map[entities.LedgerAccountType]*int64{
+		entities.LedgerPaymentsClearing: nil,
+		entities.LedgerPlatformRevenue:  nil,
+		entities.LedgerCreatorBalance:   &creatorID,
+	} {
+		account, err := l.ledger.FindOrCreateAccount(accountType, ownerID, paid.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s account: %w", accountType, err)
+		}
+		accounts[accountType] = account
+	}
+
+	// The share of a credit for everything refunded so far, rounded half up
+	share := func(credited, refunded int64) int64 {
+		return (credited*refunded*2 + paid.Amount) / (paid.Amount * 2)
+	}
+	postings := []*entities.Posting{{AccountID: accounts[entities.LedgerPaymentsClearing].ID, Amount: amount}}
+	creatorRefund := amount.Amount
+	var feeRefund, referralRefund int64
+	for _, p := range payment.Postings {
+		if p.AccountID == accounts[entities.LedgerPaymentsClearing].ID || p.AccountID == accounts[entities.LedgerCreatorBalance].ID {
+			continue
+		}
+		back := share(p.Amount.Amount, refundedBefore.Amount+amount.Amount) - share(p.Amount.Amount, refundedBefore.Amount)
+		if back == 0 {
+			continue
+		}
+		if p.AccountID == accounts[entities.LedgerPlatformRevenue].ID {
+			feeRefund += back
+		} else {
+			referralRefund += back
+		}
+		creatorRefund -= back
+		postings = append(postings, &entities.Posting{AccountID: p.AccountID, Amount: money.New(-back, amount.Currency)})
+	}
+	if creatorRefund != 0 {
+		postings = append(postings, &entities.Posting{AccountID: accounts[entities.LedgerCreatorBalance].ID, Amount: money.New(-creatorRefund, amount.Currency)})
+	}
+
+	description := fmt.Sprintf("Refund of %s of payment %s to user %d (commission %s)", amount, paymentReference, creatorID, money.New(feeRefund, amount.Currency))
+	if referralRefund != 0 {
+		description = fmt.Sprintf("Refund of %s of payment %s to user %d (commission %s, referral share %s)",
+			amount, paymentReference, creatorID, money.New(feeRefund, amount.Currency), money.New(referralRefund, amount.Currency))
+	}
+	entry := &entities.JournalEntry{
+		Kind:        entities.JournalRefund,
+		Reference:   reference,
+		Description: description,
+		CreatedAt:   time.Now(),
+		Postings:    postings,
+	}
+	return entry, l.post(entry)
+}
+
+// CreatorBalance returns what the platform currently owes a creator in the given currency.
+func (l *LedgerService) CreatorBalance(creatorID int64, currency string) (money.Money, error) {
+	return l.ledger.Balance(entities.LedgerCreatorBalance, &creatorID, currency)
+}
+
+// VerifyBalanced checks the ledger invariant: the postings of every journal entry sum to zero.
+func (l *LedgerService) VerifyBalanced() error {
+	ids, err := l.ledger.FindUnbalancedEntries()
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	refs := make([]string, len(ids))
+	for i, id := range ids {
+		refs[i] = id.String()
+	}
+	return fmt.Errorf("%d unbalanced journal entries: %s", len(ids), strings.Join(refs, ", "))
+}
+
+type postingSpec struct {
+	accountType entities.LedgerAccountType
+	ownerID     *int64
+	amount      int64
+}
+
+func (l *LedgerService) postings(currency string, specs []postingSpec) ([]*entities.Posting, error) {
+	var postings []*entities.Posting
+	for _, spec := range specs {
+		// Zero postings (e.g. no commission) carry no information
+		if spec.amount == 0 {
+			continue
+		}
+		account, err := l.ledger.FindOrCreateAccount(spec.accountType, spec.ownerID, currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s account: %w", spec.accountType, err)
+		}
+		postings = append(postings, &entities.Posting{
+			AccountID: account.ID,
+			Amount:    money.New(spec.amount, currency),
+		})
+	}
+	return postings, nil
+}
+
+func (l *LedgerService) post(entry *entities.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	return l.ledger.PostEntry(entry)
+}
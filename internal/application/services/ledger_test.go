@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+)
+
+const testReferrerID = 300
+
+func rub(amount int64) money.Money {
+	return money.New(amount, "RUB")
+}
+
+// newTestLedger returns a ledger with a 10% platform commission.
+func newTestLedger(t *testing.T) (*LedgerService, *memLedger) {
+	t.Helper()
+	repo := newMemLedger()
+	ledger, err := NewLedgerService(repo, 1000)
+	if err != nil {
+		t.Fatalf("NewLedgerService: %v", err)
+	}
+	return ledger, repo
+}
+
+// assertPosted checks that the entry balances and was posted once under its kind and reference.
+func assertPosted(t *testing.T, repo *memLedger, entry *entities.JournalEntry) {
+	t.Helper()
+	var sum int64
+	for _, posting := range entry.Postings {
+		sum += posting.Amount.Amount
+	}
+	if sum != 0 {
+		t.Errorf("%s entry %s postings sum to %d, want 0", entry.Kind, entry.Reference, sum)
+	}
+	count := 0
+	for _, posted := range repo.entries {
+		if posted.Kind == entry.Kind && posted.Reference == entry.Reference {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("%s entry %s was posted %d times, want once", entry.Kind, entry.Reference, count)
+	}
+}
+
+func assertLedgerBalance(t *testing.T, repo *memLedger, accountType entities.LedgerAccountType, ownerID int64, want int64) {
+	t.Helper()
+	var owner *int64
+	if ownerID != 0 {
+		owner = &ownerID
+	}
+	balance, err := repo.Balance(accountType, owner, "RUB")
+	if err != nil {
+		t.Fatalf("Balance: %v", err)
+	}
+	if !balance.Equal(rub(want)) {
+		t.Errorf("%s balance of %d = %s, want %s", accountType, ownerID, balance, rub(want))
+	}
+}
+
+func TestNewLedgerServiceRejectsInvalidCommission(t *testing.T) {
+	for _, bps := range []int{-1, 10001} {
+		if _, err := NewLedgerService(newMemLedger(), bps); err == nil {
+			t.Errorf("NewLedgerService accepted a commission of %d basis points", bps)
+		}
+	}
+}
+
+func TestRecordSubscriptionPayment(t *testing.T) {
+	tests := []struct {
+		name     string
+		gross    int64
+		shareBPS int
+		fee      int64
+		referral int64
+		creator  int64
+	}{
+		{name: "no referral", gross: 19900, fee: 1990, creator: 17910},
+		{name: "commission rounds half up", gross: 1005, fee: 101, creator: 904},
+		// The referrer gets 20% of the 179.10 the creator earns
+		{name: "referral split", gross: 19900, shareBPS: 2000, fee: 1990, referral: 3582, creator: 14328},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger, repo := newTestLedger(t)
+			var credit *ReferralCredit
+			if tt.shareBPS != 0 {
+				credit = &ReferralCredit{ReferrerID: testReferrerID, Amount: ledger.ReferralCommission(rub(tt.gross), tt.shareBPS)}
+			}
+
+			entry, err := ledger.RecordSubscriptionPayment("payment-1", testCreatorID, rub(tt.gross), credit)
+			if err != nil {
+				t.Fatalf("RecordSubscriptionPayment: %v", err)
+			}
+			assertPosted(t, repo, entry)
+			assertLedgerBalance(t, repo, entities.LedgerPaymentsClearing, 0, -tt.gross)
+			assertLedgerBalance(t, repo, entities.LedgerPlatformRevenue, 0, tt.fee)
+			assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testReferrerID, tt.referral)
+			assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testCreatorID, tt.creator)
+
+			// Reposting the payment credits nobody again
+			again, err := ledger.RecordSubscriptionPayment("payment-1", testCreatorID, rub(tt.gross), credit)
+			if err != nil {
+				t.Fatalf("repeated RecordSubscriptionPayment: %v", err)
+			}
+			if again.ID != entry.ID {
+				t.Errorf("repeated payment posted entry %s, want the existing %s", again.ID, entry.ID)
+			}
+			assertPosted(t, repo, entry)
+			assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testCreatorID, tt.creator)
+			if err := ledger.VerifyBalanced(); err != nil {
+				t.Errorf("VerifyBalanced: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordSubscriptionPaymentRejectsExcessiveReferral(t *testing.T) {
+	ledger, repo := newTestLedger(t)
+	credit := &ReferralCredit{ReferrerID: testReferrerID, Amount: rub(17911)}
+	if _, err := ledger.RecordSubscriptionPayment("payment-1", testCreatorID, rub(19900), credit); err == nil {
+		t.Fatal("referral credit larger than the creator's share was recorded")
+	}
+	if len(repo.entries) != 0 {
+		t.Errorf("posted %d entries, want none", len(repo.entries))
+	}
+}
+
+func TestRecordPayout(t *testing.T) {
+	ledger, repo := newTestLedger(t)
+	if _, err := ledger.RecordSubscriptionPayment("payment-1", testCreatorID, rub(19900), nil); err != nil {
+		t.Fatalf("RecordSubscriptionPayment: %v", err)
+	}
+
+	if _, err := ledger.RecordPayout("payout-0", testCreatorID, rub(17911)); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("payout over the balance: err = %v, want ErrInsufficientFunds", err)
+	}
+
+	entry, err := ledger.RecordPayout("payout-1", testCreatorID, rub(10000))
+	if err != nil {
+		t.Fatalf("RecordPayout: %v", err)
+	}
+	assertPosted(t, repo, entry)
+	assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testCreatorID, 7910)
+	assertLedgerBalance(t, repo, entities.LedgerPayoutsClearing, 0, 10000)
+
+	// Recording the payout again doesn't withdraw it twice, even though the balance would allow it
+	if _, err := ledger.RecordPayout("payout-1", testCreatorID, rub(5000)); err != nil {
+		t.Fatalf("repeated RecordPayout: %v", err)
+	}
+	assertPosted(t, repo, entry)
+	assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testCreatorID, 7910)
+
+	reversal, err := ledger.ReversePayout("payout-1", testCreatorID, rub(10000))
+	if err != nil {
+		t.Fatalf("ReversePayout: %v", err)
+	}
+	if _, err := ledger.ReversePayout("payout-1", testCreatorID, rub(10000)); err != nil {
+		t.Fatalf("repeated ReversePayout: %v", err)
+	}
+	assertPosted(t, repo, reversal)
+	assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testCreatorID, 17910)
+	assertLedgerBalance(t, repo, entities.LedgerPayoutsClearing, 0, 0)
+
+	if _, err := ledger.ReversePayout("payout-2", testCreatorID, rub(100)); err == nil {
+		t.Error("reversed a payout that was never recorded")
+	}
+	if err := ledger.VerifyBalanced(); err != nil {
+		t.Errorf("VerifyBalanced: %v", err)
+	}
+}
+
+func TestSettlePayout(t *testing.T) {
+	ledger, repo := newTestLedger(t)
+	if _, err := ledger.RecordSubscriptionPayment("payment-1", testCreatorID, rub(19900), nil); err != nil {
+		t.Fatalf("RecordSubscriptionPayment: %v", err)
+	}
+	if _, err := ledger.SettlePayout("payout-1", testCreatorID, rub(10000)); err == nil {
+		t.Fatal("settled a payout that was never recorded")
+	}
+	if _, err := ledger.RecordPayout("payout-1", testCreatorID, rub(10000)); err != nil {
+		t.Fatalf("RecordPayout: %v", err)
+	}
+
+	entry, err := ledger.SettlePayout("payout-1", testCreatorID, rub(10000))
+	if err != nil {
+		t.Fatalf("SettlePayout: %v", err)
+	}
+	if _, err := ledger.SettlePayout("payout-1", testCreatorID, rub(10000)); err != nil {
+		t.Fatalf("repeated SettlePayout: %v", err)
+	}
+	assertPosted(t, repo, entry)
+	assertLedgerBalance(t, repo, entities.LedgerPayoutsClearing, 0, 0)
+	assertLedgerBalance(t, repo, entities.LedgerPaymentsClearing, 0, -19900+10000)
+	assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testCreatorID, 7910)
+}
+
+func TestRecordRefund(t *testing.T) {
+	ledger, repo := newTestLedger(t)
+	credit := &ReferralCredit{ReferrerID: testReferrerID, Amount: ledger.ReferralCommission(rub(19900), 2000)}
+	if _, err := ledger.RecordSubscriptionPayment("payment-1", testCreatorID, rub(19900), credit); err != nil {
+		t.Fatalf("RecordSubscriptionPayment: %v", err)
+	}
+
+	// Half of the payment: the commission and the referrer give back half of their credit
+	entry, err := ledger.RecordRefund("refund-1", "payment-1", testCreatorID, rub(19900), rub(0), rub(9950))
+	if err != nil {
+		t.Fatalf("RecordRefund: %v", err)
+	}
+	assertPosted(t, repo, entry)
+	assertLedgerBalance(t, repo, entities.LedgerPlatformRevenue, 0, 1990-995)
+	assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testReferrerID, 3582-1791)
+	assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testCreatorID, 14328-7164)
+
+	if _, err := ledger.RecordRefund("refund-1", "payment-1", testCreatorID, rub(19900), rub(0), rub(9950)); err != nil {
+		t.Fatalf("repeated RecordRefund: %v", err)
+	}
+	assertPosted(t, repo, entry)
+
+	if _, err := ledger.RecordRefund("refund-2", "payment-1", testCreatorID, rub(19900), rub(9950), rub(9951)); err == nil {
+		t.Fatal("refunded more than was paid")
+	}
+	rest, err := ledger.RecordRefund("refund-2", "payment-1", testCreatorID, rub(19900), rub(9950), rub(9950))
+	if err != nil {
+		t.Fatalf("RecordRefund of the rest: %v", err)
+	}
+	assertPosted(t, repo, rest)
+	assertLedgerBalance(t, repo, entities.LedgerPaymentsClearing, 0, 0)
+	assertLedgerBalance(t, repo, entities.LedgerPlatformRevenue, 0, 0)
+	assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testReferrerID, 0)
+	assertLedgerBalance(t, repo, entities.LedgerCreatorBalance, testCreatorID, 0)
+
+	if _, err := ledger.RecordRefund("refund-3", "payment-2", testCreatorID, rub(19900), rub(0), rub(100)); err == nil {
+		t.Error("refunded a payment that was never recorded")
+	}
+	if err := ledger.VerifyBalanced(); err != nil {
+		t.Errorf("VerifyBalanced: %v", err)
+	}
+}
+
+func TestVerifyBalancedReportsUnbalancedEntries(t *testing.T) {
+	ledger, repo := newTestLedger(t)
+	account, _ := repo.FindOrCreateAccount(entities.LedgerPlatformRevenue, nil, "RUB")
+	repo.PostEntry(&entities.JournalEntry{Kind: "manual", Reference: "broken", Postings: []*entities.Posting{{AccountID: account.ID, Amount: rub(100)}}})
+
+	if err := ledger.VerifyBalanced(); err == nil {
+		t.Error("VerifyBalanced accepted an entry that doesn't balance")
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"tribute-back/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrMembershipNotFound is returned when a membership ID doesn't refer to a membership.
+	ErrMembershipNotFound = errors.New("membership not found")
+	// ErrNotMembershipOwner is returned when a user acts on someone else's membership.
+	ErrNotMembershipOwner = errors.New("membership does not belong to this user")
+	// ErrMembershipExpired is returned when cancelling a membership that no longer grants access.
+	ErrMembershipExpired = errors.New("membership has already expired")
+	// ErrMembershipNotRenewing is returned when cancelling lifetime access, which never renews.
+	ErrMembershipNotRenewing = errors.New("lifetime access does not renew")
+)
+
+// SubscriberMembership is a membership with the channel, tier and price it is for, as
+// shown to the subscriber. Channel, Tier and Price are nil if they no longer exist.
+type SubscriberMembership struct {
+	Membership *entities.Membership
+	Channel    *entities.Channel
+	Tier       *entities.Subscription
+	Price      *entities.TierPrice
+}
+
+// Subscribe grants the payer of a payment access to a tier for one billing period of the
+// given price. If the payer already has a membership for the tier it is renewed instead.
+// The membership records the payment, so the caller can tell whether it was already applied.
+func (s *TributeService) Subscribe(payment *entities.Payment, tier *entities.Subscription, price *entities.TierPrice) (*entities.Membership, error) {
+	now := time.Now()
+
+	current, err := s.memberships.FindCurrent(payment.PayerID, tier.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		current.PaymentID = &payment.ID
+		if err := s.renewMembership(current, price, now); err != nil {
+			return nil, err
+		}
+		return current, nil
+	}
+
+	priceID := price.ID
+	paymentID := payment.ID
+	membership := &entities.Membership{
+		SubscriberID:     payment.PayerID,
+		SubscriptionID:   tier.ID,
+		PriceID:          &priceID,
+		PaymentID:        &paymentID,
+		ChannelID:        tier.ChannelID,
+		Status:           entities.MembershipActive,
+		StartedAt:        now,
+		CurrentPeriodEnd: price.Interval.PeriodEnd(now),
+	}
+	if err := s.memberships.Create(membership); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+func (s *TributeService) renewMembership(membership *entities.Membership, price *entities.TierPrice, now time.Time) error {
+	if membership.Status == entities.MembershipExpired {
+		return errors.New("membership has expired")
+	}
+
+	switch {
+	case membership.CurrentPeriodEnd == nil:
+		// Lifetime access can't be extended any further
+	case price.Interval == entities.IntervalLifetime:
+		membership.CurrentPeriodEnd = nil
+	default:
+		// Renewing early stacks the new period on top of the time that is left.
+		periodStart := *membership.CurrentPeriodEnd
+		if now.After(periodStart) {
+			periodStart = now
+		}
+		membership.CurrentPeriodEnd = price.Interval.PeriodEnd(periodStart)
+	}
+	if price.ID != uuid.Nil {
+		priceID := price.ID
+		membership.PriceID = &priceID
+	}
+	membership.Status = entities.MembershipActive
+	membership.CancelledAt = nil
+	// The new period gets its own invoice before it ends
+	membership.RenewalInvoicedAt = nil
+
+	return s.memberships.Update(membership)
+}
+
+// CancelMembership stops a membership from renewing. The subscriber keeps
+// access until the end of the current period.
+func (s *TributeService) CancelMembership(subscriberID int64, membershipID uuid.UUID) (*entities.Membership, error) {
+	membership, err := s.memberships.FindByID(membershipID)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil {
+		return nil, ErrMembershipNotFound
+	}
+	if membership.SubscriberID != subscriberID {
+		return nil, ErrNotMembershipOwner
+	}
+
+	switch {
+	case membership.Status == entities.MembershipExpired:
+		return nil, ErrMembershipExpired
+	case membership.Status == entities.MembershipCancelled:
+		return membership, nil
+	case membership.CurrentPeriodEnd == nil:
+		return nil, ErrMembershipNotRenewing
+	}
+
+	now := time.Now()
+	membership.Status = entities.MembershipCancelled
+	membership.CancelledAt = &now
+	if err := s.memberships.Update(membership); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// GetMySubscriptions returns the subscriber's memberships, current ones first and then past
+// ones, each newest first.
+func (s *TributeService) GetMySubscriptions(subscriberID int64) ([]*SubscriberMembership, error) {
+	memberships, err := s.memberships.FindBySubscriberID(subscriberID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sort.SliceStable(memberships, func(i, j int) bool {
+		return memberships[i].HasAccess(now) && !memberships[j].HasAccess(now)
+	})
+
+	channels := make(map[uuid.UUID]*entities.Channel)
+	tiers := make(map[uuid.UUID]*entities.Subscription)
+	prices := make(map[uuid.UUID]*entities.TierPrice)
+	result := make([]*SubscriberMembership, len(memberships))
+	for i, membership := range memberships {
+		if result[i], err = s.describeMembership(membership, channels, tiers, prices); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// CancelMySubscription stops one of the subscriber's memberships from renewing and returns it
+// as shown in GetMySubscriptions.
+func (s *TributeService) CancelMySubscription(subscriberID int64, membershipID uuid.UUID) (*SubscriberMembership, error) {
+	membership, err := s.CancelMembership(subscriberID, membershipID)
+	if err != nil {
+		return nil, err
+	}
+	return s.describeMembership(membership, map[uuid.UUID]*entities.Channel{}, map[uuid.UUID]*entities.Subscription{}, map[uuid.UUID]*entities.TierPrice{})
+}
+
+// describeMembership loads what a membership is for, reusing and filling the given caches
+// so that memberships of the same channel are only looked up once.
+func (s *TributeService) describeMembership(membership *entities.Membership, channels map[uuid.UUID]*entities.Channel, tiers map[uuid.UUID]*entities.Subscription, prices map[uuid.UUID]*entities.TierPrice) (*SubscriberMembership, error) {
+	var err error
+	channel, ok := channels[membership.ChannelID]
+	if !ok {
+		if channel, err = s.channels.FindByID(membership.ChannelID); err != nil {
+			return nil, err
+		}
+		channels[membership.ChannelID] = channel
+	}
+	tier, ok := tiers[membership.SubscriptionID]
+	if !ok {
+		if tier, err = s.subs.FindByID(membership.SubscriptionID); err != nil {
+			return nil, err
+		}
+		tiers[membership.SubscriptionID] = tier
+	}
+	var price *entities.TierPrice
+	if membership.PriceID != nil {
+		if price, ok = prices[*membership.PriceID]; !ok {
+			if price, err = s.prices.FindByID(*membership.PriceID); err != nil {
+				return nil, err
+			}
+			prices[*membership.PriceID] = price
+		}
+	}
+	return &SubscriberMembership{Membership: membership, Channel: channel, Tier: tier, Price: price}, nil
+}
+
+// ExpireMemberships removes the subscribers of every membership whose period ended at or
+// before now from the channels, moves the memberships to expired and returns how many
+// memberships were expired. A membership is only expired once its subscriber was removed,
+// so removals that fail are retried on the next run.
+func (s *TributeService) ExpireMemberships(now time.Time) (int, error) {
+	due, err := s.memberships.FindDueForExpiry(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, membership := range due {
+		channel, err := s.channels.FindByID(membership.ChannelID)
+		if err != nil {
+			fmt.Printf("Failed to load channel %s to revoke access of membership %s: %v\n", membership.ChannelID, membership.ID, err)
+			continue
+		}
+		// A deleted channel has nobody left to remove
+		if channel != nil {
+			if err := s.removeFromChannel(membership, channel); err != nil {
+				fmt.Printf("Failed to revoke channel access for membership %s, retrying on the next run: %v\n", membership.ID, err)
+				continue
+			}
+		}
+
+		membership.Status = entities.MembershipExpired
+		if err := s.memberships.Update(membership); err != nil {
+			return expired, fmt.Errorf("failed to expire membership %s: %w", membership.ID, err)
+		}
+		expired++
+
+		if channel != nil {
+			s.notifyAccessEnded(membership, channel)
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"tribute-back/internal/domain/entities"
+)
+
+// subscribe pays for the tier through Telegram and returns the activated membership.
+func (e *testEnv) subscribe(t *testing.T) *entities.Membership {
+	t.Helper()
+	payment := e.checkout(t).Payment
+	if err := e.dispatcher.Dispatch(successfulPaymentUpdate(payment)); err != nil {
+		t.Fatalf("successful payment: %v", err)
+	}
+	return assertActivated(t, e, payment)
+}
+
+func TestExpireMembershipsRemovesSubscriber(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	membership := env.subscribe(t)
+
+	expired, err := env.service.ExpireMemberships(membership.CurrentPeriodEnd.Add(-time.Minute))
+	if err != nil || expired != 0 {
+		t.Fatalf("ExpireMemberships before the period ended = %d, %v, want nothing expired", expired, err)
+	}
+
+	expired, err = env.service.ExpireMemberships(membership.CurrentPeriodEnd.Add(time.Minute))
+	if err != nil || expired != 1 {
+		t.Fatalf("ExpireMemberships = %d, %v, want 1 expired", expired, err)
+	}
+	if stored := env.subscriberMemberships(t)[0]; stored.Status != entities.MembershipExpired {
+		t.Errorf("membership is %s, want expired", stored.Status)
+	}
+
+	chatID := fmt.Sprint(testChannelChatID)
+	revoked := env.telegram.called("revokeChatInviteLink")
+	if len(revoked) != 1 || revoked[0].Params["chat_id"] != chatID || revoked[0].Params["invite_link"] != membership.InviteLink {
+		t.Errorf("revoked invite links = %+v, want %s", revoked, membership.InviteLink)
+	}
+	bans := env.telegram.called("banChatMember")
+	if len(bans) != 1 || bans[0].Params["chat_id"] != chatID || bans[0].Params["user_id"] != float64(testSubscriberID) {
+		t.Errorf("bans = %+v, want the subscriber banned from the channel", bans)
+	}
+	unbans := env.telegram.called("unbanChatMember")
+	if len(unbans) != 1 || unbans[0].Params["user_id"] != float64(testSubscriberID) || unbans[0].Params["only_if_banned"] != true {
+		t.Errorf("unbans = %+v, want the subscriber unbanned so they can come back", unbans)
+	}
+	methods := strings.Join(env.telegram.methods(), " ")
+	if !strings.Contains(methods, "banChatMember unbanChatMember sendMessage") {
+		t.Errorf("methods called = %s, want the ban lifted and the subscriber told afterwards", methods)
+	}
+	messages := env.telegram.messagesTo(testSubscriberID)
+	if last := messages[len(messages)-1]; !strings.Contains(last, "истёк") {
+		t.Errorf("last message to subscriber = %q, want the expiry notice", last)
+	}
+
+	// Expired memberships are not expired again
+	if expired, err := env.service.ExpireMemberships(membership.CurrentPeriodEnd.Add(time.Hour)); err != nil || expired != 0 {
+		t.Errorf("second ExpireMemberships = %d, %v, want nothing expired", expired, err)
+	}
+}
+
+func TestExpireMembershipsRetriesFailedRemoval(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	membership := env.subscribe(t)
+	after := membership.CurrentPeriodEnd.Add(time.Minute)
+
+	env.telegram.fail("banChatMember", 1)
+	expired, err := env.service.ExpireMemberships(after)
+	if err != nil || expired != 0 {
+		t.Fatalf("ExpireMemberships with a failing ban = %d, %v, want nothing expired", expired, err)
+	}
+	if stored := env.subscriberMemberships(t)[0]; stored.Status != entities.MembershipActive {
+		t.Fatalf("membership is %s while the subscriber is still in the channel, want active", stored.Status)
+	}
+	if unbans := env.telegram.called("unbanChatMember"); len(unbans) != 0 {
+		t.Errorf("unbanned after a failed ban: %+v", unbans)
+	}
+	if messages := env.telegram.messagesTo(testSubscriberID); len(messages) != 1 {
+		t.Errorf("messages to subscriber = %q, want only the invite", messages)
+	}
+
+	expired, err = env.service.ExpireMemberships(after)
+	if err != nil || expired != 1 {
+		t.Fatalf("retried ExpireMemberships = %d, %v, want 1 expired", expired, err)
+	}
+	if stored := env.subscriberMemberships(t)[0]; stored.Status != entities.MembershipExpired {
+		t.Errorf("membership is %s, want expired", stored.Status)
+	}
+	if bans := env.telegram.called("banChatMember"); len(bans) != 2 {
+		t.Errorf("banned %d times, want 2", len(bans))
+	}
+}
+
+func TestProcessRenewalsExtendsPaidMembership(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	membership := env.subscribe(t)
+	periodEnd := *membership.CurrentPeriodEnd
+
+	invoiced, err := env.service.ProcessRenewals(periodEnd.Add(-96 * time.Hour))
+	if err != nil || invoiced != 0 {
+		t.Fatalf("ProcessRenewals before the renewal window = %d, %v, want nothing invoiced", invoiced, err)
+	}
+	invoiced, err = env.service.ProcessRenewals(periodEnd.Add(-48 * time.Hour))
+	if err != nil || invoiced != 1 {
+		t.Fatalf("ProcessRenewals = %d, %v, want 1 invoiced", invoiced, err)
+	}
+	if invoiced, err := env.service.ProcessRenewals(periodEnd.Add(-24 * time.Hour)); err != nil || invoiced != 0 {
+		t.Errorf("second ProcessRenewals = %d, %v, want the membership invoiced once", invoiced, err)
+	}
+
+	invoices := env.telegram.called("createInvoiceLink")
+	if len(invoices) != 2 {
+		t.Fatalf("created %d invoice links, want one for the renewal", len(invoices)-1)
+	}
+	messages := env.telegram.messagesTo(testSubscriberID)
+	if last := messages[len(messages)-1]; !strings.Contains(last, "https://t.me/$invoice") {
+		t.Errorf("last message to subscriber = %q, want the renewal invoice link", last)
+	}
+
+	payments, err := env.payments.FindByPayerID(testSubscriberID)
+	if err != nil {
+		t.Fatalf("FindByPayerID: %v", err)
+	}
+	var renewal *entities.Payment
+	for _, payment := range payments {
+		if payment.Status == entities.PaymentPending {
+			renewal = payment
+		}
+	}
+	if renewal == nil {
+		t.Fatal("no pending renewal payment")
+	}
+	if err := env.dispatcher.Dispatch(successfulPaymentUpdate(renewal)); err != nil {
+		t.Fatalf("renewal payment: %v", err)
+	}
+
+	renewed := assertActivated(t, env, renewal)
+	if want := periodEnd.AddDate(0, 1, 0); !renewed.CurrentPeriodEnd.Equal(want) {
+		t.Errorf("renewed period ends %s, want %s", renewed.CurrentPeriodEnd, want)
+	}
+	if renewed.RenewalInvoicedAt != nil {
+		t.Error("renewed membership is still marked as invoiced")
+	}
+}
+
+func TestProcessRenewalsSkipsCancelledMembership(t *testing.T) {
+	env := newTestEnv(t, testOptions{})
+	membership := env.subscribe(t)
+
+	if _, err := env.service.CancelMembership(testSubscriberID, membership.ID); err != nil {
+		t.Fatalf("CancelMembership: %v", err)
+	}
+	invoiced, err := env.service.ProcessRenewals(membership.CurrentPeriodEnd.Add(-time.Hour))
+	if err != nil || invoiced != 0 {
+		t.Errorf("ProcessRenewals = %d, %v, want a cancelled membership left to expire", invoiced, err)
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/infrastructure/auth"
+)
+
+// ErrPartnerUnauthorized is returned for callbacks that weren't signed by a configured
+// partner, were signed too long ago or replay an earlier callback.
+var ErrPartnerUnauthorized = errors.New("request is not authenticated as a partner callback")
+
+// PartnerRequest is a callback claiming to come from an integration partner, with the
+// signature headers it was sent with.
+type PartnerRequest struct {
+	Partner   string
+	Endpoint  string
+	RemoteIP  string
+	Timestamp string
+	Signature string
+	Body      []byte
+}
+
+// AuthenticatePartnerRequest checks that a callback was signed by the partner it names,
+// within the allowed clock skew, and that its signature wasn't accepted before. Every
+// attempt is audited; rejected ones are reported to the admin chat.
+func (s *TributeService) AuthenticatePartnerRequest(req PartnerRequest) error {
+	s.partnerMu.Lock()
+	defer s.partnerMu.Unlock()
+
+	now := time.Now()
+	// Hex decoding ignores letter case, so replays are looked up by the canonical form
+	signature := auth.CanonicalSignature(req.Signature)
+	var reason string
+	if err := s.partners.Verify(req.Partner, req.Timestamp, req.Signature, req.Body, now); err != nil {
+		reason = err.Error()
+	} else {
+		previous, err := s.partnerRequests.FindAcceptedBySignature(signature)
+		if err != nil {
+			return err
+		}
+		if previous != nil {
+			reason = fmt.Sprintf("signature was already accepted at %s", previous.CreatedAt.Format(time.RFC3339))
+		}
+	}
+
+	record := &entities.PartnerRequest{
+		Partner:   req.Partner,
+		Endpoint:  req.Endpoint,
+		RemoteIP:  req.RemoteIP,
+		Signature: signature,
+		Accepted:  reason == "",
+		Reason:    reason,
+		CreatedAt: now,
+	}
+	if err := s.partnerRequests.Create(record); err != nil {
+		return fmt.Errorf("failed to audit partner request: %w", err)
+	}
+	if record.Accepted {
+		return nil
+	}
+
+	alert := fmt.Sprintf("🚨 REJECTED PARTNER CALLBACK\n\n🔗 Endpoint: %s\n🤝 Partner: %q\n📝 Reason: %s\n🌐 IP: %s",
+		req.Endpoint, req.Partner, reason, req.RemoteIP)
+	s.SendThrottledAdminAlert(alert, "partner:ip:"+req.RemoteIP)
+	return fmt.Errorf("%w: %s", ErrPartnerUnauthorized, reason)
+}
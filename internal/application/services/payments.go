@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidPaymentTransition is returned when a payment can't move to the requested status.
+var ErrInvalidPaymentTransition = errors.New("invalid payment status transition")
+
+// paymentTransitions lists the statuses a payment may move to from each status.
+// Failed and refunded payments are final.
+var paymentTransitions = map[entities.PaymentStatus][]entities.PaymentStatus{
+	entities.PaymentPending:   {entities.PaymentSucceeded, entities.PaymentFailed},
+	entities.PaymentSucceeded: {entities.PaymentRefunded},
+}
+
+func canTransitionPayment(from, to entities.PaymentStatus) bool {
+	for _, next := range paymentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionPayment moves the payment to the given status and persists it.
+func (s *TributeService) transitionPayment(payment *entities.Payment, to entities.PaymentStatus) error {
+	if !canTransitionPayment(payment.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidPaymentTransition, payment.Status, to)
+	}
+	payment.Status = to
+	payment.UpdatedAt = time.Now()
+	return s.payments.Update(payment)
+}
+
+// createPendingPayment records that the subscriber is about to pay for a tier at one of its
+// prices, less the discount of the promo code if one is given.
+func (s *TributeService) createPendingPayment(payerID int64, tier *entities.Subscription, price *entities.TierPrice, promo *entities.PromoCode, discount money.Money, provider string) (*entities.Payment, error) {
+	amount := price.Price
+	var promoCodeID *uuid.UUID
+	if promo != nil {
+		var err error
+		amount, err = price.Price.Sub(discount)
+		if err != nil {
+			return nil, err
+		}
+		promoCodeID = &promo.ID
+	} else {
+		discount = money.Zero(price.Price.Currency)
+	}
+
+	now := time.Now()
+	creatorID := tier.UserID
+	tierID := tier.ID
+	priceID := price.ID
+	payment := &entities.Payment{
+		ID:             uuid.New(),
+		PayerID:        payerID,
+		CreatorID:      &creatorID,
+		SubscriptionID: &tierID,
+		PriceID:        &priceID,
+		Amount:         amount,
+		PromoCodeID:    promoCodeID,
+		Discount:       discount,
+		Refunded:       money.Zero(amount.Currency),
+		ReferralShare:  money.Zero(amount.Currency),
+		Status:         entities.PaymentPending,
+		Provider:       provider,
+		Description:    fmt.Sprintf("Subscription to user %d", creatorID),
+		CreatedDate:    now,
+		UpdatedAt:      now,
+	}
+	if err := s.payments.Create(payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// setProviderChargeID records the provider's ID for a charge that is still pending.
+func (s *TributeService) setProviderChargeID(payment *entities.Payment, chargeID string) error {
+	payment.ProviderChargeID = chargeID
+	payment.UpdatedAt = time.Now()
+	return s.payments.Update(payment)
+}
+
+// MarkPaymentSucceeded confirms a pending payment and credits the creator in the ledger,
+// sharing it with the referrer who brought the payer, if any. The ledger entry is keyed by
+// the payment and written first, so if saving the payment fails, confirming it again
+// credits nobody twice.
+func (s *TributeService) MarkPaymentSucceeded(payment *entities.Payment, providerChargeID string) error {
+	if payment.CreatorID == nil {
+		return fmt.Errorf("payment %s has no creator to credit", payment.ID)
+	}
+	if !canTransitionPayment(payment.Status, entities.PaymentSucceeded) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidPaymentTransition, payment.Status, entities.PaymentSucceeded)
+	}
+
+	credit := s.referralCredit(payment)
+	if _, err := s.ledger.RecordSubscriptionPayment(payment.ID.String(), *payment.CreatorID, payment.Amount, credit); err != nil {
+		return fmt.Errorf("failed to record payment in the ledger: %w", err)
+	}
+
+	payment.ProviderChargeID = providerChargeID
+	if err := s.transitionPayment(payment, entities.PaymentSucceeded); err != nil {
+		return err
+	}
+	if credit != nil {
+		s.notifyReferralCredit(payment, credit)
+	}
+	return nil
+}
+
+// MarkPaymentFailed records that a pending payment didn't go through.
+func (s *TributeService) MarkPaymentFailed(payment *entities.Payment, reason string) error {
+	payment.FailureReason = reason
+	return s.transitionPayment(payment, entities.PaymentFailed)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"tribute-back/internal/domain/card"
+	"tribute-back/internal/domain/entities"
+)
+
+// vaultBatch is how many records are migrated or rotated per query.
+const vaultBatch = 100
+
+// cardSecret is what the vault keeps for a payout card.
+type cardSecret struct {
+	Number   string `json:"number"`
+	ExpMonth int    `json:"exp_month,omitempty"`
+	ExpYear  int    `json:"exp_year,omitempty"`
+}
+
+// storePayoutCard encrypts a normalized card number in the vault.
+func (s *TributeService) storePayoutCard(number string, expMonth, expYear int) (*entities.PayoutCard, error) {
+	secret, err := json.Marshal(cardSecret{Number: number, ExpMonth: expMonth, ExpYear: expYear})
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.vault.Store(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PayoutCard{
+		Token:    token,
+		Last4:    card.Last4(number),
+		Brand:    card.Brand(number),
+		ExpMonth: expMonth,
+		ExpYear:  expYear,
+	}, nil
+}
+
+// revealCardNumber decrypts a payout card's number. Only the payout gateway should ever see it.
+func (s *TributeService) revealCardNumber(payoutCard *entities.PayoutCard) (string, error) {
+	raw, err := s.vault.Reveal(payoutCard.Token)
+	if err != nil {
+		return "", fmt.Errorf("failed to read card from the vault: %w", err)
+	}
+	var secret cardSecret
+	if err := json.Unmarshal(raw, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode card from the vault: %w", err)
+	}
+	return secret.Number, nil
+}
+
+// MigrateLegacyCards moves card numbers saved in plaintext before the vault existed into
+// the vault and clears them. Numbers that aren't valid cards are dropped. It returns how
+// many cards were moved.
+func (s *TributeService) MigrateLegacyCards() (int, error) {
+	migrated := 0
+	for {
+		numbers, err := s.users.FindLegacyCardNumbers(vaultBatch)
+		if err != nil {
+			return migrated, err
+		}
+		if len(numbers) == 0 {
+			return migrated, nil
+		}
+
+		for userID, raw := range numbers {
+			if err := s.migrateLegacyCard(userID, raw); err != nil {
+				return migrated, fmt.Errorf("user %d: %w", userID, err)
+			}
+			migrated++
+		}
+	}
+}
+
+func (s *TributeService) migrateLegacyCard(userID int64, raw string) error {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	number, err := card.NormalizeNumber(raw)
+	if err != nil {
+		fmt.Printf("Dropping invalid legacy card number of user %d: %v\n", userID, err)
+	} else if user != nil && user.PayoutCard == nil {
+		// The expiry was never collected for legacy cards
+		payoutCard, err := s.storePayoutCard(number, 0, 0)
+		if err != nil {
+			return err
+		}
+		user.PayoutCard = payoutCard
+		if err := s.users.Update(user); err != nil {
+			return err
+		}
+	}
+
+	return s.users.ClearLegacyCardNumber(userID)
+}
+
+// RotateVaultKeys re-wraps every secret in the vault with the active key and returns how many it re-wrapped.
+func (s *TributeService) RotateVaultKeys() (int, error) {
+	total := 0
+	for {
+		rotated, err := s.vault.Rotate(vaultBatch)
+		total += rotated
+		if err != nil || rotated == 0 {
+			return total, err
+		}
+	}
+}
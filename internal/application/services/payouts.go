@@ -0,0 +1,246 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/config"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/payouts"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPayoutNotAllowed is returned when an unverified user sets up or requests a payout.
+	ErrPayoutNotAllowed = errors.New("user must be verified to use payouts")
+	// ErrNoPayoutMethod is returned when the user hasn't set up a card for payouts.
+	ErrNoPayoutMethod = errors.New("payout method is not set up")
+	// ErrPayoutBelowMinimum is returned when a payout is smaller than the minimum for its currency.
+	ErrPayoutBelowMinimum = errors.New("payout is below the minimum amount")
+)
+
+// PayoutPolicy limits what creators can withdraw and how persistently payouts are retried.
+type PayoutPolicy struct {
+	// MinAmounts is the smallest payout per currency; currencies without an entry have no minimum
+	MinAmounts map[string]money.Money
+	// MaxAttempts is how many temporary gateway failures a payout survives before it fails
+	MaxAttempts int
+}
+
+// NewPayoutPolicy parses the payout configuration.
+func NewPayoutPolicy(cfg config.PayoutConfig) (PayoutPolicy, error) {
+	policy := PayoutPolicy{MinAmounts: make(map[string]money.Money), MaxAttempts: cfg.MaxAttempts}
+	for currency, amount := range cfg.MinAmounts {
+		minimum, err := money.Parse(amount, currency)
+		if err != nil {
+			return PayoutPolicy{}, fmt.Errorf("invalid minimum payout for %s: %w", currency, err)
+		}
+		policy.MinAmounts[minimum.Currency] = minimum
+	}
+	if policy.MaxAttempts < 1 {
+		return PayoutPolicy{}, fmt.Errorf("payout attempts must be at least 1, got %d", cfg.MaxAttempts)
+	}
+	return policy, nil
+}
+
+// RequestPayout reserves amount from the creator's balance and queues it for payout.
+func (s *TributeService) RequestPayout(userID int64, amount money.Money) (*entities.Payout, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.IsVerified {
+		return nil, ErrPayoutNotAllowed
+	}
+	if user.PayoutCard == nil {
+		return nil, ErrNoPayoutMethod
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrPayoutBelowMinimum)
+	}
+	if minimum, ok := s.payoutPolicy.MinAmounts[amount.Currency]; ok && amount.Amount < minimum.Amount {
+		return nil, fmt.Errorf("%w of %s", ErrPayoutBelowMinimum, minimum)
+	}
+
+	now := time.Now()
+	payout := &entities.Payout{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Amount:    amount,
+		Status:    entities.PayoutRequested,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Reserve the amount first so it can't be requested twice
+	if _, err := s.ledger.RecordPayout(payout.ID.String(), userID, amount); err != nil {
+		return nil, err
+	}
+	if err := s.payouts.Create(payout); err != nil {
+		if _, reverseErr := s.ledger.ReversePayout(payout.ID.String(), userID, amount); reverseErr != nil {
+			fmt.Printf("Failed to release reservation for payout %s: %v\n", payout.ID, reverseErr)
+		}
+		return nil, err
+	}
+	return payout, nil
+}
+
+// GetPayouts returns the user's payouts, newest first.
+func (s *TributeService) GetPayouts(userID int64) ([]*entities.Payout, error) {
+	return s.payouts.FindByUserID(userID)
+}
+
+// ProcessPayouts submits requested payouts to the gateway and checks on the ones it is
+// processing. It returns how many payouts settled (paid or failed).
+func (s *TributeService) ProcessPayouts(now time.Time) (int, error) {
+	var errs []error
+	settled := 0
+
+	requested, err := s.payouts.FindByStatus(entities.PayoutRequested)
+	if err != nil {
+		return 0, err
+	}
+	for _, payout := range requested {
+		if err := s.submitPayout(payout, now); err != nil {
+			errs = append(errs, fmt.Errorf("payout %s: %w", payout.ID, err))
+		}
+		if isSettled(payout) {
+			settled++
+		}
+	}
+
+	processing, err := s.payouts.FindByStatus(entities.PayoutProcessing)
+	if err != nil {
+		return settled, errors.Join(append(errs, err)...)
+	}
+	for _, payout := range processing {
+		if err := s.pollPayout(payout, now); err != nil {
+			errs = append(errs, fmt.Errorf("payout %s: %w", payout.ID, err))
+		}
+		if isSettled(payout) {
+			settled++
+		}
+	}
+
+	return settled, errors.Join(errs...)
+}
+
+func isSettled(payout *entities.Payout) bool {
+	return payout.Status == entities.PayoutPaid || payout.Status == entities.PayoutFailed
+}
+
+func (s *TributeService) submitPayout(payout *entities.Payout, now time.Time) error {
+	user, err := s.users.FindByID(payout.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.PayoutCard == nil {
+		return s.failPayout(payout, "payout method was removed", now)
+	}
+	cardNumber, err := s.revealCardNumber(user.PayoutCard)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.payoutGateway.CreatePayout(payouts.PayoutRequest{
+		Reference:  payout.ID.String(),
+		UserID:     payout.UserID,
+		Amount:     payout.Amount,
+		CardNumber: cardNumber,
+	})
+	if err != nil {
+		if errors.Is(err, payouts.ErrPayoutRejected) {
+			return s.failPayout(payout, err.Error(), now)
+		}
+		payout.Attempts++
+		if payout.Attempts >= s.payoutPolicy.MaxAttempts {
+			return s.failPayout(payout, fmt.Sprintf("gave up after %d attempts: %v", payout.Attempts, err), now)
+		}
+		payout.UpdatedAt = now
+		if updateErr := s.payouts.Update(payout); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+
+	payout.GatewayPayoutID = result.ID
+	// Status checks get their own attempts
+	payout.Attempts = 0
+	return s.applyPayoutResult(payout, result, now)
+}
+
+// pollPayout checks on a payout the gateway is processing. A gateway that keeps failing to
+// report on the payout, e.g. one that lost track of it, gets MaxAttempts tries before the
+// payout is given up on.
+func (s *TributeService) pollPayout(payout *entities.Payout, now time.Time) error {
+	result, err := s.payoutGateway.GetPayoutStatus(payout.GatewayPayoutID)
+	if err != nil {
+		payout.Attempts++
+		if payout.Attempts >= s.payoutPolicy.MaxAttempts {
+			return s.failPayout(payout, fmt.Sprintf("gave up after %d attempts: %v", payout.Attempts, err), now)
+		}
+		payout.UpdatedAt = now
+		if updateErr := s.payouts.Update(payout); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+	return s.applyPayoutResult(payout, result, now)
+}
+
+// applyPayoutResult moves the payout to the state reported by the gateway.
+func (s *TributeService) applyPayoutResult(payout *entities.Payout, result *payouts.PayoutResult, now time.Time) error {
+	switch result.Status {
+	case payouts.PayoutPaid:
+		// Settle first: if saving the payout fails, the next poll settles it again
+		if _, err := s.ledger.SettlePayout(payout.ID.String(), payout.UserID, payout.Amount); err != nil {
+			return fmt.Errorf("failed to settle payout in the ledger: %w", err)
+		}
+		payout.Status = entities.PayoutPaid
+		payout.CompletedAt = &now
+		payout.UpdatedAt = now
+		if err := s.payouts.Update(payout); err != nil {
+			return err
+		}
+		s.notifyPayout(payout, fmt.Sprintf("Выплата %s отправлена на вашу карту.", payout.Amount))
+		return nil
+	case payouts.PayoutFailed:
+		return s.failPayout(payout, result.FailureReason, now)
+	default:
+		if payout.Status == entities.PayoutProcessing {
+			return nil
+		}
+		payout.Status = entities.PayoutProcessing
+		payout.UpdatedAt = now
+		return s.payouts.Update(payout)
+	}
+}
+
+// failPayout gives up on a payout and returns the reserved amount to the creator's balance.
+func (s *TributeService) failPayout(payout *entities.Payout, reason string, now time.Time) error {
+	if _, err := s.ledger.ReversePayout(payout.ID.String(), payout.UserID, payout.Amount); err != nil {
+		return fmt.Errorf("failed to release payout reservation: %w", err)
+	}
+
+	payout.Status = entities.PayoutFailed
+	payout.FailureReason = reason
+	payout.CompletedAt = &now
+	payout.UpdatedAt = now
+	if err := s.payouts.Update(payout); err != nil {
+		return err
+	}
+
+	s.notifyPayout(payout, fmt.Sprintf("Не удалось выполнить выплату %s. Сумма возвращена на ваш баланс.", payout.Amount))
+	return nil
+}
+
+func (s *TributeService) notifyPayout(payout *entities.Payout, message string) {
+	if err := s.telegramBot.SendMessage(payout.UserID, message); err != nil {
+		fmt.Printf("Failed to notify user %d about payout %s: %v\n", payout.UserID, payout.ID, err)
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/payouts"
+)
+
+// requestPayout credits the creator with 900.00 RUB of earnings, saves their payout card and
+// requests a payout of 500.00 RUB.
+func (e *testEnv) requestPayout(t *testing.T) *entities.Payout {
+	t.Helper()
+	if _, err := e.service.ledger.RecordSubscriptionPayment("seed", testCreatorID, money.New(100000, "RUB"), nil); err != nil {
+		t.Fatalf("RecordSubscriptionPayment: %v", err)
+	}
+	secret, _ := json.Marshal(cardSecret{Number: "4242424242424242"})
+	token, err := e.service.vault.Store(secret)
+	if err != nil {
+		t.Fatalf("vault.Store: %v", err)
+	}
+	creator, _ := e.users.FindByID(testCreatorID)
+	creator.PayoutCard = &entities.PayoutCard{Token: token, Last4: "4242", Brand: "visa"}
+	e.users.Update(creator)
+
+	payout, err := e.service.RequestPayout(testCreatorID, money.New(50000, "RUB"))
+	if err != nil {
+		t.Fatalf("RequestPayout: %v", err)
+	}
+	return payout
+}
+
+func TestProcessPayoutsGatewayOutcomes(t *testing.T) {
+	tests := []struct {
+		name     string
+		gateway  payouts.MockConfig
+		attempts int
+		// statuses and errs are the payout's status and whether ProcessPayouts failed after each run
+		statuses []entities.PayoutStatus
+		errs     []bool
+		reason   string
+		balance  int64
+		// clearing is what is left in payouts_clearing: payouts taken from the balance but not paid yet
+		clearing int64
+		// notice is part of the message the creator gets once the payout settles
+		notice string
+	}{
+		{
+			name:     "paid right away",
+			gateway:  payouts.MockConfig{FailureMode: payouts.MockNoFailure},
+			statuses: []entities.PayoutStatus{entities.PayoutPaid},
+			errs:     []bool{false},
+			balance:  40000,
+			notice:   "отправлена",
+		},
+		{
+			name:     "paid after processing",
+			gateway:  payouts.MockConfig{FailureMode: payouts.MockNoFailure, ProcessingPolls: 1},
+			statuses: []entities.PayoutStatus{entities.PayoutProcessing, entities.PayoutPaid},
+			errs:     []bool{false, false},
+			balance:  40000,
+			notice:   "отправлена",
+		},
+		{
+			name:     "rejected",
+			gateway:  payouts.MockConfig{FailureMode: payouts.MockReject},
+			statuses: []entities.PayoutStatus{entities.PayoutFailed},
+			errs:     []bool{false},
+			reason:   "rejected",
+			balance:  90000,
+			notice:   "возвращена",
+		},
+		{
+			name:     "gateway unavailable",
+			gateway:  payouts.MockConfig{FailureMode: payouts.MockUnavailable},
+			attempts: 2,
+			statuses: []entities.PayoutStatus{entities.PayoutRequested, entities.PayoutFailed},
+			errs:     []bool{true, false},
+			reason:   "gave up after 2 attempts",
+			balance:  90000,
+			notice:   "возвращена",
+		},
+		{
+			name:     "failed while processing",
+			gateway:  payouts.MockConfig{FailureMode: payouts.MockFail, ProcessingPolls: 1},
+			statuses: []entities.PayoutStatus{entities.PayoutProcessing, entities.PayoutFailed},
+			errs:     []bool{false, false},
+			reason:   "card_declined",
+			balance:  90000,
+			notice:   "возвращена",
+		},
+		{
+			name:     "stuck processing",
+			gateway:  payouts.MockConfig{FailureMode: payouts.MockStuck},
+			statuses: []entities.PayoutStatus{entities.PayoutProcessing, entities.PayoutProcessing, entities.PayoutProcessing},
+			errs:     []bool{false, false, false},
+			balance:  40000,
+			clearing: 50000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, testOptions{payouts: tt.gateway, payoutAttempts: tt.attempts})
+			payout := env.requestPayout(t)
+
+			now := time.Now()
+			for run, want := range tt.statuses {
+				now = now.Add(time.Minute)
+				_, err := env.service.ProcessPayouts(now)
+				if (err != nil) != tt.errs[run] {
+					t.Errorf("run %d: ProcessPayouts error = %v, want error %t", run+1, err, tt.errs[run])
+				}
+				if stored, _ := env.payouts.FindByID(payout.ID); stored.Status != want {
+					t.Fatalf("run %d: payout is %s, want %s", run+1, stored.Status, want)
+				}
+			}
+
+			stored, _ := env.payouts.FindByID(payout.ID)
+			if !strings.Contains(stored.FailureReason, tt.reason) || (tt.reason == "") != (stored.FailureReason == "") {
+				t.Errorf("failure reason = %q, want %q", stored.FailureReason, tt.reason)
+			}
+			if settled := stored.Status == entities.PayoutPaid || stored.Status == entities.PayoutFailed; settled != (stored.CompletedAt != nil) {
+				t.Errorf("payout is %s with completed at %v", stored.Status, stored.CompletedAt)
+			}
+			balance, err := env.service.ledger.CreatorBalance(testCreatorID, "RUB")
+			if err != nil {
+				t.Fatalf("CreatorBalance: %v", err)
+			}
+			if want := money.New(tt.balance, "RUB"); !balance.Equal(want) {
+				t.Errorf("creator balance = %s, want %s", balance, want)
+			}
+			assertBalance(t, env, entities.LedgerPayoutsClearing, nil, tt.clearing)
+			if err := env.service.ledger.VerifyBalanced(); err != nil {
+				t.Errorf("VerifyBalanced: %v", err)
+			}
+
+			messages := env.telegram.messagesTo(testCreatorID)
+			switch {
+			case tt.notice == "" && len(messages) != 0:
+				t.Errorf("creator was told %q about an unsettled payout", messages)
+			case tt.notice != "" && (len(messages) != 1 || !strings.Contains(messages[0], tt.notice)):
+				t.Errorf("messages to creator = %q, want one saying %q", messages, tt.notice)
+			}
+		})
+	}
+}
+
+func TestProcessPayoutsGivesUpOnLostPayout(t *testing.T) {
+	env := newTestEnv(t, testOptions{payouts: payouts.MockConfig{FailureMode: payouts.MockNoFailure, ProcessingPolls: 1}, payoutAttempts: 2})
+	payout := env.requestPayout(t)
+
+	now := time.Now()
+	if _, err := env.service.ProcessPayouts(now); err != nil {
+		t.Fatalf("ProcessPayouts: %v", err)
+	}
+	// A restarted mock gateway no longer knows the payouts it accepted
+	env.service.payoutGateway = payouts.NewMockGateway(payouts.MockConfig{FailureMode: payouts.MockNoFailure})
+
+	if _, err := env.service.ProcessPayouts(now.Add(time.Minute)); err == nil {
+		t.Error("ProcessPayouts reported no error for a payout the gateway doesn't know")
+	}
+	if stored, _ := env.payouts.FindByID(payout.ID); stored.Status != entities.PayoutProcessing || stored.Attempts != 1 {
+		t.Fatalf("payout is %s after %d attempts, want processing after 1", stored.Status, stored.Attempts)
+	}
+
+	if _, err := env.service.ProcessPayouts(now.Add(2 * time.Minute)); err != nil {
+		t.Fatalf("ProcessPayouts: %v", err)
+	}
+	stored, _ := env.payouts.FindByID(payout.ID)
+	if stored.Status != entities.PayoutFailed || !strings.Contains(stored.FailureReason, "gave up after 2 attempts") {
+		t.Fatalf("payout is %s (%q), want failed after 2 attempts", stored.Status, stored.FailureReason)
+	}
+	creatorID := int64(testCreatorID)
+	assertBalance(t, env, entities.LedgerCreatorBalance, &creatorID, 90000)
+	assertBalance(t, env, entities.LedgerPayoutsClearing, nil, 0)
+}
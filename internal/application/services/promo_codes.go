@@ -0,0 +1,243 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+var (
+	// ErrInvalidPromoCode is returned when a creator picks a code that can't be typed reliably.
+	ErrInvalidPromoCode = errors.New("promo code must be 3 to 32 letters, digits, dashes or underscores")
+	// ErrPromoCodeTaken is returned when the channel already has a live code with the same text.
+	ErrPromoCodeTaken = errors.New("promo code already exists for this channel")
+	// ErrInvalidDiscount is returned for discounts that are out of range or of an unknown type.
+	ErrInvalidDiscount = errors.New("discount must be 1 to 99 percent or a positive fixed amount")
+	// ErrInvalidPromoLimits is returned for negative redemption limits.
+	ErrInvalidPromoLimits = errors.New("redemption limits can't be negative")
+	// ErrInvalidPromoWindow is returned when a code would stop being valid before it starts.
+	ErrInvalidPromoWindow = errors.New("promo code must end after it starts")
+	// ErrPromoCodeNotFound is returned when a code doesn't exist or was deleted.
+	ErrPromoCodeNotFound = errors.New("promo code not found")
+	// ErrNotPromoCodeOwner is returned when a user edits a code of someone else's channel.
+	ErrNotPromoCodeOwner = errors.New("promo code does not belong to this user")
+	// ErrPromoCodeInactive is returned when a code is used outside its validity window.
+	ErrPromoCodeInactive = errors.New("promo code is not active")
+	// ErrPromoCodeNotApplicable is returned when a code can't be used for the tier price.
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to this subscription")
+	// ErrPromoCodeExhausted is returned when a code has reached its maximum number of redemptions.
+	ErrPromoCodeExhausted = errors.New("promo code has been fully redeemed")
+	// ErrPromoCodeUsed is returned when the subscriber has used a code as often as allowed.
+	ErrPromoCodeUsed = errors.New("promo code has already been used")
+)
+
+// PromoCodeDetails are the creator-chosen settings of a promo code. Only the limits and
+// the validity window can be changed once the code exists.
+type PromoCodeDetails struct {
+	Code string
+	// TierID limits the code to one tier of the channel
+	TierID         *uuid.UUID
+	DiscountType   entities.PromoDiscountType
+	PercentOff     int
+	AmountOff      money.Money
+	MaxRedemptions int
+	PerUserLimit   int
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+}
+
+func validatePromoLimits(details PromoCodeDetails) error {
+	if details.MaxRedemptions < 0 || details.PerUserLimit < 0 {
+		return ErrInvalidPromoLimits
+	}
+	if details.StartsAt != nil && details.EndsAt != nil && !details.EndsAt.After(*details.StartsAt) {
+		return ErrInvalidPromoWindow
+	}
+	return nil
+}
+
+// normalizePromoCode makes codes case-insensitive for subscribers.
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// GetPromoCodes returns the live promo codes of one of the user's channels.
+func (s *TributeService) GetPromoCodes(userID int64, channelID uuid.UUID) ([]*entities.PromoCode, error) {
+	if _, err := s.findOwnedChannel(userID, channelID); err != nil {
+		return nil, err
+	}
+	return s.promoCodes.FindByChannelID(channelID)
+}
+
+// CreatePromoCode adds a discount code to one of the user's channels, optionally limited to one of its tiers.
+func (s *TributeService) CreatePromoCode(userID int64, channelID uuid.UUID, details PromoCodeDetails) (*entities.PromoCode, error) {
+	code := normalizePromoCode(details.Code)
+	if !promoCodePattern.MatchString(code) {
+		return nil, ErrInvalidPromoCode
+	}
+	switch details.DiscountType {
+	case entities.PromoPercent:
+		if details.PercentOff < 1 || details.PercentOff > 99 {
+			return nil, ErrInvalidDiscount
+		}
+		details.AmountOff = money.Money{}
+	case entities.PromoFixed:
+		if !details.AmountOff.IsPositive() {
+			return nil, ErrInvalidDiscount
+		}
+		details.PercentOff = 0
+	default:
+		return nil, ErrInvalidDiscount
+	}
+	if err := validatePromoLimits(details); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findOwnedChannel(userID, channelID); err != nil {
+		return nil, err
+	}
+	if details.TierID != nil {
+		tier, err := s.findOwnedTier(userID, *details.TierID)
+		if err != nil {
+			return nil, err
+		}
+		if tier.ChannelID != channelID {
+			return nil, ErrTierNotFound
+		}
+	}
+
+	existing, err := s.promoCodes.FindByCode(channelID, code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrPromoCodeTaken
+	}
+
+	promoCode := &entities.PromoCode{
+		CreatorID:      userID,
+		ChannelID:      channelID,
+		SubscriptionID: details.TierID,
+		Code:           code,
+		DiscountType:   details.DiscountType,
+		PercentOff:     details.PercentOff,
+		AmountOff:      details.AmountOff,
+		MaxRedemptions: details.MaxRedemptions,
+		PerUserLimit:   details.PerUserLimit,
+		StartsAt:       details.StartsAt,
+		EndsAt:         details.EndsAt,
+		CreatedDate:    time.Now(),
+	}
+	if err := s.promoCodes.Create(promoCode); err != nil {
+		return nil, err
+	}
+	return promoCode, nil
+}
+
+// UpdatePromoCode changes the redemption limits and validity window of a promo code.
+// Lowering a limit below the redemptions so far only stops further use.
+func (s *TributeService) UpdatePromoCode(userID int64, promoCodeID uuid.UUID, details PromoCodeDetails) (*entities.PromoCode, error) {
+	if err := validatePromoLimits(details); err != nil {
+		return nil, err
+	}
+
+	promoCode, err := s.findOwnedPromoCode(userID, promoCodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	promoCode.MaxRedemptions = details.MaxRedemptions
+	promoCode.PerUserLimit = details.PerUserLimit
+	promoCode.StartsAt = details.StartsAt
+	promoCode.EndsAt = details.EndsAt
+	if err := s.promoCodes.Update(promoCode); err != nil {
+		return nil, err
+	}
+	return promoCode, nil
+}
+
+// ArchivePromoCode stops a promo code from being used. Payments that already used it keep their discount.
+func (s *TributeService) ArchivePromoCode(userID int64, promoCodeID uuid.UUID) error {
+	promoCode, err := s.findOwnedPromoCode(userID, promoCodeID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	promoCode.ArchivedAt = &now
+	return s.promoCodes.Update(promoCode)
+}
+
+func (s *TributeService) findOwnedPromoCode(userID int64, promoCodeID uuid.UUID) (*entities.PromoCode, error) {
+	promoCode, err := s.promoCodes.FindByID(promoCodeID)
+	if err != nil {
+		return nil, err
+	}
+	if promoCode == nil || promoCode.ArchivedAt != nil {
+		return nil, ErrPromoCodeNotFound
+	}
+	if promoCode.CreatorID != userID {
+		return nil, ErrNotPromoCodeOwner
+	}
+	return promoCode, nil
+}
+
+// redeemPromoCode checks that the subscriber may use a code for the tier price and
+// returns it with the discount it gives. The caller must hold promoMu until the payment
+// using the code is created, so that concurrent checkouts can't exceed the subscriber's
+// limit. The code's total limit only counts payments that went through, so checkouts that
+// are still pending when it is reached may go over it.
+// A pending payment the subscriber started earlier with the same code is failed, so that
+// an abandoned checkout doesn't use up their redemptions.
+func (s *TributeService) redeemPromoCode(subscriberID int64, tier *entities.Subscription, price *entities.TierPrice, code string) (*entities.PromoCode, money.Money, error) {
+	promoCode, err := s.promoCodes.FindByCode(tier.ChannelID, normalizePromoCode(code))
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+	if promoCode == nil {
+		return nil, money.Money{}, ErrPromoCodeNotFound
+	}
+	if !promoCode.IsActive(time.Now()) {
+		return nil, money.Money{}, ErrPromoCodeInactive
+	}
+	if !promoCode.AppliesTo(tier) {
+		return nil, money.Money{}, ErrPromoCodeNotApplicable
+	}
+	discount, err := promoCode.Discount(price.Price)
+	if err != nil {
+		return nil, money.Money{}, fmt.Errorf("%w: %v", ErrPromoCodeNotApplicable, err)
+	}
+
+	previous, err := s.payments.FindByPromoCode(promoCode.ID, subscriberID)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+	used := 0
+	for _, payment := range previous {
+		switch payment.Status {
+		case entities.PaymentPending:
+			if err := s.MarkPaymentFailed(payment, "replaced by a new checkout"); err != nil {
+				return nil, money.Money{}, err
+			}
+		case entities.PaymentFailed:
+		default:
+			used++
+		}
+	}
+
+	if promoCode.MaxRedemptions > 0 && promoCode.Redemptions >= promoCode.MaxRedemptions {
+		return nil, money.Money{}, ErrPromoCodeExhausted
+	}
+	if promoCode.PerUserLimit > 0 && used >= promoCode.PerUserLimit {
+		return nil, money.Money{}, ErrPromoCodeUsed
+	}
+	return promoCode, discount, nil
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidReferralShare is returned when a creator offers referrers a share outside the allowed range.
+	ErrInvalidReferralShare = errors.New("referral share is out of range")
+	// ErrNoReferralProgram is returned when a referral link is requested for a channel that doesn't reward referrers.
+	ErrNoReferralProgram = errors.New("channel has no referral program")
+	// ErrSelfReferral is returned when a creator asks for a referral link to their own channel.
+	ErrSelfReferral = errors.New("cannot refer subscribers to your own channel")
+)
+
+// ReferralLinkStats is a referrer's link to a channel with what it brought in.
+type ReferralLinkStats struct {
+	Link    *entities.StartLink
+	Channel *entities.Channel
+	Stats   *entities.ReferralStats
+}
+
+// SetReferralShare sets the share of their earnings from one of the user's channels that
+// referrers get for the subscribers they bring, in basis points; 0 ends the referral
+// program. Subscribers referred earlier keep the share they were referred at.
+func (s *TributeService) SetReferralShare(userID int64, channelID uuid.UUID, shareBPS int) (*entities.Channel, error) {
+	if shareBPS < 0 || shareBPS > s.billing.ReferralMaxShareBPS {
+		return nil, fmt.Errorf("%w: must be between 0 and %d basis points", ErrInvalidReferralShare, s.billing.ReferralMaxShareBPS)
+	}
+
+	channel, err := s.findOwnedChannel(userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if shareBPS > 0 && !channel.IsVerified {
+		return nil, ErrChannelNotVerified
+	}
+
+	channel.ReferralShareBPS = shareBPS
+	if err := s.channels.Update(channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// GetReferralLink returns the user's referral link to a channel, creating it the first time.
+// Subscribers who open the link and then pay for the channel are credited to the user.
+func (s *TributeService) GetReferralLink(userID int64, channelID uuid.UUID) (*entities.StartLink, error) {
+	channel, err := s.channels.FindByID(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, ErrChannelNotFound
+	}
+	if channel.UserID == userID {
+		return nil, ErrSelfReferral
+	}
+	if !channel.IsVerified {
+		return nil, ErrChannelNotVerified
+	}
+	if channel.ReferralShareBPS == 0 {
+		return nil, ErrNoReferralProgram
+	}
+
+	link, err := s.startLinks.FindReferralLink(channel.ID, userID)
+	if err != nil || link != nil {
+		return link, err
+	}
+
+	code, err := s.newStartLinkCode()
+	if err != nil {
+		return nil, err
+	}
+	referrerID := userID
+	link = &entities.StartLink{
+		Code:       code,
+		CreatorID:  channel.UserID,
+		ReferrerID: &referrerID,
+		ChannelID:  channel.ID,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.startLinks.Create(link); err != nil {
+		// A concurrent request may have created the link first
+		if existing, findErr := s.startLinks.FindReferralLink(channel.ID, userID); findErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+// getReferralStats returns the user's referral links with their conversions and earnings.
+func (s *TributeService) getReferralStats(userID int64) ([]*ReferralLinkStats, error) {
+	links, err := s.startLinks.FindByReferrerID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*ReferralLinkStats, 0, len(links))
+	for _, link := range links {
+		channel, err := s.channels.FindByID(link.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		if channel == nil {
+			continue
+		}
+		stats, err := s.referrals.StatsByLinkID(link.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &ReferralLinkStats{Link: link, Channel: channel, Stats: stats})
+	}
+	return result, nil
+}
+
+// referralCredit works out the referrer's share of a pending payment that is about to
+// succeed and records it on the payment. Returns nil if the payer wasn't referred.
+// Attribution failures are only logged; the creator then keeps the whole amount.
+func (s *TributeService) referralCredit(payment *entities.Payment) *ReferralCredit {
+	if payment.Status != entities.PaymentPending {
+		return nil
+	}
+	referral, err := s.referralFor(payment)
+	if err != nil {
+		fmt.Printf("Failed to attribute payment %s to a referrer: %v\n", payment.ID, err)
+		return nil
+	}
+	if referral == nil {
+		return nil
+	}
+
+	share := s.ledger.ReferralCommission(payment.Amount, referral.ShareBPS)
+	if !share.IsPositive() {
+		return nil
+	}
+	payment.ReferralID = &referral.ID
+	payment.ReferralShare = share
+	return &ReferralCredit{ReferrerID: referral.ReferrerID, Amount: share}
+}
+
+// referralFor returns the referral a payment shares revenue with. A subscriber's first
+// payment to a channel makes a referral if they opened a referral link to the channel
+// within the attribution window and the channel still has a referral program; later
+// payments share revenue with the same referrer.
+func (s *TributeService) referralFor(payment *entities.Payment) (*entities.Referral, error) {
+	if payment.SubscriptionID == nil {
+		return nil, nil
+	}
+	tier, err := s.subs.FindByID(*payment.SubscriptionID)
+	if err != nil || tier == nil {
+		return nil, err
+	}
+
+	referral, err := s.referrals.FindBySubscriber(tier.ChannelID, payment.PayerID)
+	if err != nil || referral != nil {
+		return referral, err
+	}
+
+	// Only a first payment makes a referral, so that existing subscribers can't be claimed
+	paidBefore, err := s.payments.HasPaidForChannel(payment.PayerID, tier.ChannelID, payment.ID)
+	if err != nil || paidBefore {
+		return nil, err
+	}
+
+	now := time.Now()
+	link, err := s.startLinks.FindLastReferralClick(tier.ChannelID, payment.PayerID, now.Add(-s.billing.ReferralAttributionWindow))
+	if err != nil || link == nil {
+		return nil, err
+	}
+	channel, err := s.channels.FindByID(tier.ChannelID)
+	if err != nil || channel == nil {
+		return nil, err
+	}
+	if channel.ReferralShareBPS == 0 || *link.ReferrerID == payment.PayerID || *link.ReferrerID == channel.UserID {
+		return nil, nil
+	}
+
+	referral = &entities.Referral{
+		ChannelID:    channel.ID,
+		SubscriberID: payment.PayerID,
+		ReferrerID:   *link.ReferrerID,
+		LinkID:       link.ID,
+		ShareBPS:     channel.ReferralShareBPS,
+		PaymentID:    payment.ID,
+		CreatedAt:    now,
+	}
+	if err := s.referrals.Create(referral); err != nil {
+		return nil, err
+	}
+	return referral, nil
+}
+
+// notifyReferralCredit tells the referrer what a payment of a subscriber they brought earned them.
+func (s *TributeService) notifyReferralCredit(payment *entities.Payment, credit *ReferralCredit) {
+	channelName := "канал"
+	if payment.SubscriptionID != nil {
+		if tier, err := s.subs.FindByID(*payment.SubscriptionID); err == nil && tier != nil {
+			if channel, err := s.channels.FindByID(tier.ChannelID); err == nil && channel != nil {
+				channelName = "канал " + channel.ChannelTitle
+			}
+		}
+	}
+
+	message := fmt.Sprintf("Вам начислено %s: приведённый вами подписчик оплатил подписку на %s.", credit.Amount, channelName)
+	if err := s.telegramBot.SendMessage(credit.ReferrerID, message); err != nil {
+		fmt.Printf("Failed to notify referrer %d about payment %s: %v\n", credit.ReferrerID, payment.ID, err)
+	}
+}
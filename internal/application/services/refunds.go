@@ -0,0 +1,408 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/payments"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPaymentNotFound is returned when a payment ID doesn't refer to a payment.
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrNotPaymentRecipient is returned when a creator refunds a payment made to someone else.
+	ErrNotPaymentRecipient = errors.New("payment was not made to this user")
+	// ErrPaymentNotRefundable is returned for payments that didn't succeed or were refunded in full.
+	ErrPaymentNotRefundable = errors.New("payment can't be refunded")
+	// ErrInvalidRefundAmount is returned for amounts that are not positive or exceed what is left to refund.
+	ErrInvalidRefundAmount = errors.New("refund amount must be positive and at most the amount not refunded yet")
+	// ErrPartialRefundNotSupported is returned when the payment's provider can only refund payments in full.
+	ErrPartialRefundNotSupported = errors.New("payment provider can only refund the full amount")
+	// ErrIdempotencyKeyReused is returned when a key is retried with a different refund amount.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different refund")
+	// ErrRefundInProgress is returned when a refund with the same key hasn't been answered by the provider.
+	ErrRefundInProgress = errors.New("refund with this idempotency key is still in progress")
+	// ErrRefundFailed is returned when the payment provider rejects a refund.
+	ErrRefundFailed = errors.New("payment provider rejected the refund")
+)
+
+// RefundDetails describe a refund request.
+type RefundDetails struct {
+	// Amount is a decimal amount in the payment's currency; empty refunds everything not refunded yet
+	Amount string
+	Reason string
+	// RevokeAccess also ends the payer's membership for the paid tier and removes them from the channel
+	RevokeAccess bool
+	// IdempotencyKey identifies the request; retrying with the same key doesn't refund twice
+	IdempotencyKey string
+}
+
+// PaymentRefund is a refund together with the payment it was taken from.
+type PaymentRefund struct {
+	Refund  *entities.Refund
+	Payment *entities.Payment
+}
+
+// GetReceivedPayments returns the succeeded and refunded payments made to the creator, newest first.
+func (s *TributeService) GetReceivedPayments(creatorID int64) ([]*entities.Payment, error) {
+	return s.payments.FindByCreatorID(creatorID)
+}
+
+// RefundPayment returns part or all of a payment made to the creator to its payer.
+func (s *TributeService) RefundPayment(creatorID int64, paymentID uuid.UUID, details RefundDetails) (*PaymentRefund, error) {
+	payment, err := s.payments.FindByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if payment.CreatorID == nil || *payment.CreatorID != creatorID {
+		return nil, ErrNotPaymentRecipient
+	}
+	return s.refundPayment(payment.ID, entities.RefundByCreator, &creatorID, details)
+}
+
+// AdminRefundPayment returns part or all of any payment to its payer on behalf of an admin.
+func (s *TributeService) AdminRefundPayment(adminID int64, paymentID uuid.UUID, details RefundDetails) (*PaymentRefund, error) {
+	return s.refundPayment(paymentID, entities.RefundByAdmin, &adminID, details)
+}
+
+// refundPayment asks the payment's provider to return the amount to the payer and, once it
+// has, reverses the creator's earnings in the ledger. A retry with the idempotency key of a
+// succeeded refund returns that refund; a retry of a failed one tries the provider again.
+func (s *TributeService) refundPayment(paymentID uuid.UUID, source entities.RefundSource, requestedBy *int64, details RefundDetails) (*PaymentRefund, error) {
+	if details.IdempotencyKey == "" {
+		return nil, errors.New("refund idempotency key is required")
+	}
+
+	s.refundMu.Lock()
+	defer s.refundMu.Unlock()
+
+	// Load the payment under the lock so earlier refunds are accounted for
+	payment, err := s.payments.FindByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	var amount money.Money
+	if details.Amount != "" {
+		amount, err = money.Parse(details.Amount, payment.Amount.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRefundAmount, err)
+		}
+	}
+
+	refund, err := s.refunds.FindByIdempotencyKey(payment.ID, details.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if refund != nil {
+		if details.Amount != "" && !amount.Equal(refund.Amount) {
+			return nil, ErrIdempotencyKeyReused
+		}
+		switch {
+		case refund.Status == entities.RefundSucceeded:
+			return &PaymentRefund{Refund: refund, Payment: payment}, nil
+		case refund.Status == entities.RefundPending && refund.ProviderRefundID != "":
+			// The provider refunded the money but recording it was interrupted
+			return s.applyRefund(payment, refund)
+		case refund.Status == entities.RefundPending:
+			return nil, ErrRefundInProgress
+		}
+		amount = refund.Amount
+	}
+
+	if payment.Status != entities.PaymentSucceeded {
+		return nil, fmt.Errorf("%w: payment is %s", ErrPaymentNotRefundable, payment.Status)
+	}
+	refundable := payment.Refundable()
+	if details.Amount == "" && refund == nil {
+		amount = refundable
+	}
+	if !amount.IsPositive() || amount.Amount > refundable.Amount {
+		return nil, fmt.Errorf("%w: %s can still be refunded", ErrInvalidRefundAmount, refundable)
+	}
+	if payment.Provider == payments.TelegramProviderName && !amount.Equal(payment.Amount) {
+		return nil, ErrPartialRefundNotSupported
+	}
+	provider, ok := s.providers.Get(payment.Provider)
+	if !ok {
+		return nil, fmt.Errorf("payment provider %s of payment %s is not configured", payment.Provider, payment.ID)
+	}
+
+	now := time.Now()
+	if refund == nil {
+		refund = &entities.Refund{
+			PaymentID:      payment.ID,
+			Source:         source,
+			RequestedBy:    requestedBy,
+			IdempotencyKey: details.IdempotencyKey,
+			Amount:         amount,
+			Status:         entities.RefundPending,
+			Reason:         details.Reason,
+			RevokeAccess:   details.RevokeAccess,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := s.refunds.Create(refund); err != nil {
+			return nil, err
+		}
+	} else {
+		refund.Status = entities.RefundPending
+		refund.FailureReason = ""
+		refund.UpdatedAt = now
+		if err := s.refunds.Update(refund); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := provider.Refund(payment.ProviderChargeID, payment.PayerID, amount)
+	if err != nil {
+		refund.Status = entities.RefundFailed
+		refund.FailureReason = err.Error()
+		refund.UpdatedAt = time.Now()
+		if updateErr := s.refunds.Update(refund); updateErr != nil {
+			fmt.Printf("Failed to mark refund %s as failed: %v\n", refund.ID, updateErr)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRefundFailed, err)
+	}
+
+	refund.ProviderRefundID = result.ID
+	refund.UpdatedAt = time.Now()
+	if err := s.refunds.Update(refund); err != nil {
+		return nil, fmt.Errorf("provider refunded %s of payment %s but it could not be saved: %w", amount, payment.ID, err)
+	}
+	return s.applyRefund(payment, refund)
+}
+
+// recordChargeback records money the payment provider returned to the payer on its own,
+// e.g. after a dispute. The payer loses access to the channel and the creator is told.
+// Providers may report the same chargeback more than once, and may also report refunds
+// we issued ourselves; both are ignored.
+func (s *TributeService) recordChargeback(paymentID uuid.UUID, event *payments.Event) error {
+	s.refundMu.Lock()
+	defer s.refundMu.Unlock()
+
+	payment, err := s.payments.FindByID(paymentID)
+	if err != nil {
+		return err
+	}
+	if payment == nil {
+		return ErrPaymentNotFound
+	}
+
+	var refund *entities.Refund
+	key := "provider:" + event.ChargeID
+	if event.RefundID != "" {
+		key = "provider:" + event.RefundID
+		previous, err := s.refunds.FindByPaymentID(payment.ID)
+		if err != nil {
+			return err
+		}
+		for _, other := range previous {
+			if other.ProviderRefundID == event.RefundID {
+				refund = other
+			}
+		}
+	}
+	if refund == nil {
+		if refund, err = s.refunds.FindByIdempotencyKey(payment.ID, key); err != nil {
+			return err
+		}
+	}
+	switch {
+	case refund != nil && refund.Status == entities.RefundSucceeded:
+		return nil
+	case refund != nil:
+		_, err := s.applyRefund(payment, refund)
+		return err
+	case payment.Status == entities.PaymentRefunded:
+		return nil
+	case payment.Status != entities.PaymentSucceeded:
+		return fmt.Errorf("%w: payment %s is %s", ErrPaymentNotRefundable, payment.ID, payment.Status)
+	}
+
+	refundable := payment.Refundable()
+	amount := event.Amount
+	if amount.IsZero() {
+		amount = refundable
+	}
+	if !amount.SameCurrency(refundable) || !amount.IsPositive() || amount.Amount > refundable.Amount {
+		return fmt.Errorf("%w: chargeback of %s for payment %s, %s can still be refunded", ErrInvalidRefundAmount, amount, payment.ID, refundable)
+	}
+
+	now := time.Now()
+	refund = &entities.Refund{
+		PaymentID:        payment.ID,
+		Source:           entities.RefundChargeback,
+		IdempotencyKey:   key,
+		Amount:           amount,
+		Status:           entities.RefundPending,
+		Reason:           "chargeback",
+		RevokeAccess:     true,
+		ProviderRefundID: event.RefundID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.refunds.Create(refund); err != nil {
+		return err
+	}
+	if _, err := s.applyRefund(payment, refund); err != nil {
+		return err
+	}
+
+	alert := fmt.Sprintf("⚠️ Платёж %s пользователя %d оспорен, возвращено %s", payment.ID, payment.PayerID, amount)
+	if err := s.telegramBot.SendAdminMessage(alert); err != nil {
+		fmt.Printf("Failed to send chargeback alert: %v\n", err)
+	}
+	if payment.CreatorID != nil {
+		message := fmt.Sprintf("Подписчик оспорил платёж на %s, сумма списана с вашего баланса, а доступ подписчика к каналу закрыт.", amount)
+		if err := s.telegramBot.SendMessage(*payment.CreatorID, message); err != nil {
+			fmt.Printf("Failed to notify user %d about a chargeback: %v\n", *payment.CreatorID, err)
+		}
+	}
+	return nil
+}
+
+// applyRefund records a refund the provider has made: it reverses the creator's earnings,
+// adds the refund to the payment and marks it succeeded, then revokes access if asked to
+// and tells the payer. Every step can be repeated, so an interrupted refund can be resumed.
+func (s *TributeService) applyRefund(payment *entities.Payment, refund *entities.Refund) (*PaymentRefund, error) {
+	if payment.CreatorID == nil {
+		return nil, fmt.Errorf("payment %s has no creator to charge the refund to", payment.ID)
+	}
+
+	previous, err := s.refunds.FindByPaymentID(payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	refundedBefore := money.Zero(payment.Amount.Currency)
+	for _, other := range previous {
+		if other.ID != refund.ID && other.Status == entities.RefundSucceeded {
+			if refundedBefore, err = refundedBefore.Add(other.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := s.ledger.RecordRefund(refund.ID.String(), payment.ID.String(), *payment.CreatorID, payment.Amount, refundedBefore, refund.Amount); err != nil {
+		return nil, fmt.Errorf("failed to record refund in the ledger: %w", err)
+	}
+
+	if payment.Refunded, err = refundedBefore.Add(refund.Amount); err != nil {
+		return nil, err
+	}
+	if payment.Status == entities.PaymentSucceeded && payment.Refunded.Equal(payment.Amount) {
+		err = s.transitionPayment(payment, entities.PaymentRefunded)
+	} else {
+		payment.UpdatedAt = time.Now()
+		err = s.payments.Update(payment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	refund.Status = entities.RefundSucceeded
+	refund.UpdatedAt = time.Now()
+	if err := s.refunds.Update(refund); err != nil {
+		return nil, err
+	}
+
+	// The refund is already recorded, so Telegram failures must not fail it
+	s.notifyRefund(payment, refund)
+	return &PaymentRefund{Refund: refund, Payment: payment}, nil
+}
+
+// notifyRefund ends the payer's membership if the refund asks for it and the refunded payment
+// paid for the current period, and tells them about the refund.
+func (s *TributeService) notifyRefund(payment *entities.Payment, refund *entities.Refund) {
+	var channel *entities.Channel
+	var membership *entities.Membership
+	if payment.SubscriptionID != nil {
+		tier, err := s.subs.FindByID(*payment.SubscriptionID)
+		if err == nil && tier != nil {
+			channel, err = s.channels.FindByID(tier.ChannelID)
+		}
+		if err == nil && refund.RevokeAccess {
+			membership, err = s.memberships.FindCurrent(payment.PayerID, *payment.SubscriptionID)
+		}
+		if err == nil && membership != nil {
+			var funded bool
+			if funded, err = s.fundsCurrentPeriod(membership, payment); err == nil && !funded {
+				// Refunding an earlier period leaves the period the subscriber is in now paid for
+				fmt.Printf("Refunded payment %s didn't pay for the current period of membership %s, keeping access\n", payment.ID, membership.ID)
+				membership = nil
+			}
+		}
+		if err != nil {
+			fmt.Printf("Failed to load the subscription of refunded payment %s: %v\n", payment.ID, err)
+		}
+	}
+
+	revoked := false
+	if membership != nil {
+		// Like ExpireMemberships, only expire the membership once the subscriber is out of the channel
+		var removeErr error
+		if channel != nil {
+			removeErr = s.removeFromChannel(membership, channel)
+			revoked = removeErr == nil
+		}
+		if removeErr == nil {
+			membership.Status = entities.MembershipExpired
+		} else {
+			// End the period now so that ExpireMemberships retries the removal, and don't
+			// invoice the subscriber for a renewal in the meantime
+			fmt.Printf("Failed to revoke channel access for membership %s, leaving it to expire: %v\n", membership.ID, removeErr)
+			now := time.Now()
+			membership.CurrentPeriodEnd = &now
+			membership.RenewalInvoicedAt = &now
+		}
+		if err := s.memberships.Update(membership); err != nil {
+			fmt.Printf("Failed to end membership %s of refunded payment %s: %v\n", membership.ID, payment.ID, err)
+		}
+	}
+
+	message := fmt.Sprintf("Вам возвращено %s.", refund.Amount)
+	if channel != nil {
+		message = fmt.Sprintf("Вам возвращено %s за подписку на канал %s.", refund.Amount, channel.ChannelTitle)
+	}
+	if revoked {
+		message += " Доступ к каналу закрыт."
+	}
+	if refund.Reason != "" && refund.Source != entities.RefundChargeback {
+		message += "\nПричина: " + refund.Reason
+	}
+	if err := s.telegramBot.SendMessage(payment.PayerID, message); err != nil {
+		fmt.Printf("Failed to notify user %d about refund %s: %v\n", payment.PayerID, refund.ID, err)
+	}
+}
+
+// fundsCurrentPeriod reports whether the payment paid for the membership's current period.
+// Memberships that don't record their payment were paid by the payer's latest payment for the tier.
+func (s *TributeService) fundsCurrentPeriod(membership *entities.Membership, payment *entities.Payment) (bool, error) {
+	if membership.PaymentID != nil {
+		return *membership.PaymentID == payment.ID, nil
+	}
+	if membership.IsTrialPeriod() {
+		return false, nil
+	}
+	payments, err := s.payments.FindByPayerID(payment.PayerID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range payments {
+		paid := p.Status == entities.PaymentSucceeded || p.Status == entities.PaymentRefunded
+		if paid && p.SubscriptionID != nil && *p.SubscriptionID == membership.SubscriptionID {
+			return p.ID == payment.ID, nil
+		}
+	}
+	return false, nil
+}
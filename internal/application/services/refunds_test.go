@@ -0,0 +1,132 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/payments"
+)
+
+// assertBalance checks the balance of a ledger account in rubles.
+func assertBalance(t *testing.T, env *testEnv, accountType entities.LedgerAccountType, ownerID *int64, want int64) {
+	t.Helper()
+	balance, err := env.ledger.Balance(accountType, ownerID, "RUB")
+	if err != nil {
+		t.Fatalf("Balance of %s: %v", accountType, err)
+	}
+	if !balance.Equal(money.New(want, "RUB")) {
+		t.Errorf("%s balance = %s, want %s", accountType, balance, money.New(want, "RUB"))
+	}
+}
+
+func TestRefundRevokesAccess(t *testing.T) {
+	env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName})
+	payment := env.checkout(t).Payment
+	assertActivated(t, env, payment)
+
+	refund, err := env.service.RefundPayment(testCreatorID, payment.ID, RefundDetails{Reason: "по просьбе", RevokeAccess: true, IdempotencyKey: "refund-1"})
+	if err != nil {
+		t.Fatalf("RefundPayment: %v", err)
+	}
+	if refund.Payment.Status != entities.PaymentRefunded {
+		t.Errorf("payment is %s, want refunded", refund.Payment.Status)
+	}
+	if stored := env.subscriberMemberships(t)[0]; stored.Status != entities.MembershipExpired {
+		t.Errorf("membership is %s, want expired", stored.Status)
+	}
+	if bans := env.telegram.called("banChatMember"); len(bans) != 1 {
+		t.Errorf("banned %d times, want 1", len(bans))
+	}
+	messages := env.telegram.messagesTo(testSubscriberID)
+	if last := messages[len(messages)-1]; !strings.Contains(last, "Доступ к каналу закрыт") {
+		t.Errorf("last message to subscriber = %q, want it to say access was revoked", last)
+	}
+	creatorID := int64(testCreatorID)
+	assertBalance(t, env, entities.LedgerCreatorBalance, &creatorID, 0)
+}
+
+func TestRefundRetriesFailedRemoval(t *testing.T) {
+	env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName})
+	payment := env.checkout(t).Payment
+	assertActivated(t, env, payment)
+
+	env.telegram.fail("banChatMember", 1)
+	if _, err := env.service.RefundPayment(testCreatorID, payment.ID, RefundDetails{RevokeAccess: true, IdempotencyKey: "refund-1"}); err != nil {
+		t.Fatalf("RefundPayment: %v", err)
+	}
+	if stored := env.payment(t, payment.ID); stored.Status != entities.PaymentRefunded {
+		t.Errorf("payment is %s, want refunded", stored.Status)
+	}
+	stored := env.subscriberMemberships(t)[0]
+	if stored.Status != entities.MembershipActive {
+		t.Fatalf("membership is %s while the subscriber is still in the channel, want active", stored.Status)
+	}
+	if stored.CurrentPeriodEnd == nil || stored.CurrentPeriodEnd.After(time.Now()) {
+		t.Errorf("membership period ends %v, want it ended", stored.CurrentPeriodEnd)
+	}
+	messages := env.telegram.messagesTo(testSubscriberID)
+	if last := messages[len(messages)-1]; strings.Contains(last, "Доступ к каналу закрыт") {
+		t.Errorf("last message to subscriber = %q, says access was revoked", last)
+	}
+
+	if invoiced, err := env.service.ProcessRenewals(time.Now()); err != nil || invoiced != 0 {
+		t.Errorf("ProcessRenewals = %d, %v, want the refunded membership not invoiced", invoiced, err)
+	}
+	expired, err := env.service.ExpireMemberships(time.Now())
+	if err != nil || expired != 1 {
+		t.Fatalf("ExpireMemberships = %d, %v, want the removal retried", expired, err)
+	}
+	if stored := env.subscriberMemberships(t)[0]; stored.Status != entities.MembershipExpired {
+		t.Errorf("membership is %s, want expired", stored.Status)
+	}
+	if bans := env.telegram.called("banChatMember"); len(bans) != 2 {
+		t.Errorf("banned %d times, want 2", len(bans))
+	}
+}
+
+func TestPartialRefundsReverseLedgerProportionally(t *testing.T) {
+	env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName})
+	payment := env.checkout(t).Payment
+	assertActivated(t, env, payment)
+	creatorID := int64(testCreatorID)
+
+	// 199.00 RUB paid: 19.90 commission, 179.10 to the creator
+	refund, err := env.service.RefundPayment(testCreatorID, payment.ID, RefundDetails{Amount: "33.33", IdempotencyKey: "refund-1"})
+	if err != nil {
+		t.Fatalf("RefundPayment: %v", err)
+	}
+	if refund.Payment.Status != entities.PaymentSucceeded || !refund.Payment.Refunded.Equal(money.New(3333, "RUB")) {
+		t.Errorf("payment is %s with %s refunded, want succeeded with 33.33 refunded", refund.Payment.Status, refund.Payment.Refunded)
+	}
+	// The commission gives back 19.90 * 33.33 / 199.00 = 3.333, rounded to 3.33
+	assertBalance(t, env, entities.LedgerPlatformRevenue, nil, 1990-333)
+	assertBalance(t, env, entities.LedgerCreatorBalance, &creatorID, 17910-3000)
+	assertBalance(t, env, entities.LedgerPaymentsClearing, nil, -19900+3333)
+
+	// Retrying with the same key refunds nothing more
+	if _, err := env.service.RefundPayment(testCreatorID, payment.ID, RefundDetails{Amount: "33.33", IdempotencyKey: "refund-1"}); err != nil {
+		t.Fatalf("retried RefundPayment: %v", err)
+	}
+	assertBalance(t, env, entities.LedgerCreatorBalance, &creatorID, 17910-3000)
+
+	// Refunding the rest reverses exactly what the payment credited
+	refund, err = env.service.RefundPayment(testCreatorID, payment.ID, RefundDetails{IdempotencyKey: "refund-2"})
+	if err != nil {
+		t.Fatalf("RefundPayment of the rest: %v", err)
+	}
+	if refund.Payment.Status != entities.PaymentRefunded || !refund.Refund.Amount.Equal(money.New(16567, "RUB")) {
+		t.Errorf("refunded %s and payment is %s, want 165.67 refunded and the payment refunded", refund.Refund.Amount, refund.Payment.Status)
+	}
+	assertBalance(t, env, entities.LedgerPlatformRevenue, nil, 0)
+	assertBalance(t, env, entities.LedgerCreatorBalance, &creatorID, 0)
+	assertBalance(t, env, entities.LedgerPaymentsClearing, nil, 0)
+
+	if stored := env.subscriberMemberships(t)[0]; stored.Status != entities.MembershipActive {
+		t.Errorf("membership is %s after refunds that keep access, want active", stored.Status)
+	}
+	if _, err := env.service.RefundPayment(testCreatorID, payment.ID, RefundDetails{Amount: "1.00", IdempotencyKey: "refund-3"}); err == nil {
+		t.Error("refunded more than was paid")
+	}
+}
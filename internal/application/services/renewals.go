@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+)
+
+// ProcessRenewals invoices subscribers whose paid period ends within the configured renewal
+// window for the next period at the price they last paid, notifying them through the
+// payment provider. Paying extends the membership by a period from the end of the current
+// one; unpaid memberships are expired by ExpireMemberships. Cancelled memberships aren't
+// invoiced. Returns how many subscribers were invoiced.
+func (s *TributeService) ProcessRenewals(now time.Time) (int, error) {
+	due, err := s.memberships.FindDueForRenewal(now.Add(s.billing.RenewalInvoiceBefore))
+	if err != nil {
+		return 0, err
+	}
+
+	invoiced := 0
+	for _, membership := range due {
+		// Mark the invoice first: the checkout below may renew the membership right away
+		membership.RenewalInvoicedAt = &now
+		if err := s.memberships.Update(membership); err != nil {
+			return invoiced, fmt.Errorf("failed to mark renewal invoice of membership %s: %w", membership.ID, err)
+		}
+		invoiced++
+
+		s.invoiceRenewal(membership)
+	}
+	return invoiced, nil
+}
+
+// invoiceRenewal invoices the subscriber for the next period and tells them when the current
+// one ends. Failures are only logged; the membership then simply expires.
+func (s *TributeService) invoiceRenewal(membership *entities.Membership) {
+	channel, err := s.channels.FindByID(membership.ChannelID)
+	if err != nil || channel == nil {
+		fmt.Printf("Failed to load channel %s to renew membership %s: %v\n", membership.ChannelID, membership.ID, err)
+		return
+	}
+	periodEnd := membership.CurrentPeriodEnd.Format("02.01.2006 15:04")
+
+	checkout, err := s.StartSubscriptionCheckout(membership.SubscriberID, channel.ID, membership.PriceID, "", true)
+	var message string
+	switch {
+	case err != nil:
+		fmt.Printf("Failed to invoice renewal of membership %s: %v\n", membership.ID, err)
+		message = fmt.Sprintf("Подписка на канал %s закончится %s. Продлить её не получится, после этого доступ к каналу будет закрыт.",
+			channel.ChannelTitle, periodEnd)
+	case checkout.Payment.Status == entities.PaymentSucceeded:
+		// The provider charged right away and the subscriber was already told about the payment
+		return
+	case checkout.Payment.Status == entities.PaymentFailed:
+		message = fmt.Sprintf("Подписка на канал %s закончится %s. Оплатить продление не удалось, после этого доступ к каналу будет закрыт.",
+			channel.ChannelTitle, periodEnd)
+	default:
+		message = fmt.Sprintf("Подписка на канал %s закончится %s. Чтобы продлить её, оплатите %s.",
+			channel.ChannelTitle, periodEnd, checkout.Payment.Amount)
+		if checkout.CheckoutURL != "" {
+			message += "\n" + checkout.CheckoutURL
+		}
+	}
+
+	if err := s.telegramBot.SendMessage(membership.SubscriberID, message); err != nil {
+		fmt.Printf("Failed to send renewal invoice to user %d: %v\n", membership.SubscriberID, err)
+	}
+}
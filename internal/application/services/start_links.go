@@ -0,0 +1,164 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// startLinkCodeBytes is the entropy of a start link code; base64url turns 6 bytes into 8
+// characters, all of which Telegram allows in a startapp parameter.
+const startLinkCodeBytes = 6
+
+var (
+	// ErrStartLinkNotFound is returned when a code or ID doesn't refer to a live start link.
+	ErrStartLinkNotFound = errors.New("start link not found")
+	// ErrNotStartLinkOwner is returned when a user deletes someone else's start link.
+	ErrNotStartLinkOwner = errors.New("start link does not belong to this user")
+)
+
+// StartLinkOffer is the channel offer a start link opened, with the link itself.
+type StartLinkOffer struct {
+	Link  *entities.StartLink
+	Offer *ChannelOffer
+}
+
+// StartLinkLaunch describes a launch of the Mini App through a start link, from its initData.
+type StartLinkLaunch struct {
+	UserID       int64
+	StartParam   string
+	ChatType     string
+	ChatInstance string
+	AuthDate     time.Time
+}
+
+// StartLinkURL returns the t.me link that opens the Mini App with the code.
+func (s *TributeService) StartLinkURL(code string) (string, error) {
+	return s.telegramBot.StartAppLink(code)
+}
+
+// GetStartLinks returns the live start links of one of the user's channels with their clicks.
+func (s *TributeService) GetStartLinks(userID int64, channelID uuid.UUID) ([]*entities.StartLink, error) {
+	if _, err := s.findOwnedChannel(userID, channelID); err != nil {
+		return nil, err
+	}
+	return s.startLinks.FindByChannelID(channelID)
+}
+
+// CreateStartLink creates a short link to the offer of one of the user's verified channels,
+// optionally preselecting one of its prices.
+func (s *TributeService) CreateStartLink(userID int64, channelID uuid.UUID, label string, priceID *uuid.UUID) (*entities.StartLink, error) {
+	channel, err := s.findOwnedChannel(userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if !channel.IsVerified {
+		return nil, ErrChannelNotVerified
+	}
+	if priceID != nil {
+		if _, _, _, err := s.findCheckoutPrice(channel.ID, priceID); err != nil {
+			return nil, err
+		}
+	}
+
+	code, err := s.newStartLinkCode()
+	if err != nil {
+		return nil, err
+	}
+	link := &entities.StartLink{
+		Code:      code,
+		CreatorID: userID,
+		ChannelID: channel.ID,
+		PriceID:   priceID,
+		Label:     label,
+		CreatedAt: time.Now(),
+	}
+	if err := s.startLinks.Create(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// ArchiveStartLink stops a start link from opening the offer. Its clicks are kept, and
+// subscribers it already brought stay referred.
+func (s *TributeService) ArchiveStartLink(userID int64, linkID uuid.UUID) error {
+	link, err := s.startLinks.FindByID(linkID)
+	if err != nil {
+		return err
+	}
+	if link == nil || link.ArchivedAt != nil {
+		return ErrStartLinkNotFound
+	}
+	if link.OwnerID() != userID {
+		return ErrNotStartLinkOwner
+	}
+
+	now := time.Now()
+	link.ArchivedAt = &now
+	return s.startLinks.Update(link)
+}
+
+// OpenStartLink resolves the start_param the Mini App was launched with to a channel offer
+// and records the launch as a click on the link. Launches by the creator or by the referrer
+// the link belongs to aren't counted.
+func (s *TributeService) OpenStartLink(launch StartLinkLaunch) (*StartLinkOffer, error) {
+	link, err := s.startLinks.FindByCode(launch.StartParam)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.ArchivedAt != nil {
+		return nil, ErrStartLinkNotFound
+	}
+
+	channel, err := s.channels.FindByID(link.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, ErrStartLinkNotFound
+	}
+	offer, err := s.channelOffer(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	if launch.UserID != link.CreatorID && launch.UserID != link.OwnerID() {
+		click := &entities.StartLinkClick{
+			LinkID:       link.ID,
+			UserID:       launch.UserID,
+			ChatType:     launch.ChatType,
+			ChatInstance: launch.ChatInstance,
+			AuthDate:     launch.AuthDate,
+			CreatedAt:    time.Now(),
+		}
+		// Losing a click must not keep the subscriber from the offer
+		if err := s.startLinks.RecordClick(click); err != nil {
+			fmt.Printf("Failed to record click on start link %s: %v\n", link.Code, err)
+		}
+	}
+	return &StartLinkOffer{Link: link, Offer: offer}, nil
+}
+
+// newStartLinkCode picks a random code that no link has used yet.
+func (s *TributeService) newStartLinkCode() (string, error) {
+	raw := make([]byte, startLinkCodeBytes)
+	for attempt := 0; attempt < 5; attempt++ {
+		if _, err := rand.Read(raw); err != nil {
+			return "", err
+		}
+		code := base64.RawURLEncoding.EncodeToString(raw)
+		existing, err := s.startLinks.FindByCode(code)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return code, nil
+		}
+	}
+	return "", errors.New("failed to pick an unused start link code")
+}
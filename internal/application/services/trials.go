@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNoTrial is returned when a free trial is requested for a price that doesn't offer one.
+	ErrNoTrial = errors.New("this price has no free trial")
+	// ErrTrialUsed is returned when the subscriber already had a free trial in the channel.
+	ErrTrialUsed = errors.New("free trial has already been used for this channel")
+	// ErrAlreadySubscribed is returned when the subscriber already has access to the channel.
+	ErrAlreadySubscribed = errors.New("already subscribed to this channel")
+)
+
+// StartTrial gives the subscriber free access to a channel for the trial days of one of its
+// tier prices (the channel's default offer if priceID is nil) and sends them an invite link.
+// Each subscriber gets one trial per channel. Before the trial ends the subscriber is
+// invoiced for the price; if they don't pay, they are removed from the channel.
+func (s *TributeService) StartTrial(subscriberID int64, channelID uuid.UUID, priceID *uuid.UUID) (*entities.Membership, error) {
+	tier, price, channel, err := s.findCheckoutPrice(channelID, priceID)
+	if err != nil {
+		return nil, err
+	}
+	if tier.UserID == subscriberID {
+		return nil, ErrSelfSubscription
+	}
+	if price.TrialDays == 0 {
+		return nil, ErrNoTrial
+	}
+
+	used, err := s.memberships.HasTrial(subscriberID, channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, ErrTrialUsed
+	}
+
+	now := time.Now()
+	current, err := s.memberships.FindBySubscriberID(subscriberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, membership := range current {
+		if membership.ChannelID == channel.ID && membership.HasAccess(now) {
+			return nil, ErrAlreadySubscribed
+		}
+	}
+
+	trialEnd := now.AddDate(0, 0, price.TrialDays)
+	priceRef := price.ID
+	membership := &entities.Membership{
+		SubscriberID:     subscriberID,
+		SubscriptionID:   tier.ID,
+		PriceID:          &priceRef,
+		ChannelID:        channel.ID,
+		Status:           entities.MembershipTrialing,
+		StartedAt:        now,
+		CurrentPeriodEnd: &trialEnd,
+		TrialEndsAt:      &trialEnd,
+	}
+	if err := s.memberships.Create(membership); err != nil {
+		return nil, err
+	}
+
+	// The trial is already recorded, so a Telegram failure must not fail the request
+	if err := s.grantChannelAccess(membership, channel); err != nil {
+		fmt.Printf("Failed to grant trial access to user %d: %v\n", subscriberID, err)
+	}
+	return membership, nil
+}
+
+// ProcessTrials reminds subscribers whose free trial ends within the configured reminder
+// window and invoices them for the trial price, notifying them through the payment
+// provider. Paying converts the trial into a paid membership that starts when the trial
+// ends; unpaid trials are expired by ExpireMemberships. Returns how many subscribers
+// were reminded.
+func (s *TributeService) ProcessTrials(now time.Time) (int, error) {
+	due, err := s.memberships.FindTrialsDueForReminder(now.Add(s.billing.TrialReminderBefore))
+	if err != nil {
+		return 0, err
+	}
+
+	reminded := 0
+	for _, membership := range due {
+		// Mark the reminder first: the checkout below may convert the membership right away
+		membership.TrialReminderSentAt = &now
+		if err := s.memberships.Update(membership); err != nil {
+			return reminded, fmt.Errorf("failed to mark trial reminder of membership %s: %w", membership.ID, err)
+		}
+		reminded++
+
+		s.remindTrialEnding(membership)
+	}
+	return reminded, nil
+}
+
+// remindTrialEnding invoices the subscriber for the trial price and tells them when the trial
+// ends. Failures are only logged; the trial then simply expires.
+func (s *TributeService) remindTrialEnding(membership *entities.Membership) {
+	channel, err := s.channels.FindByID(membership.ChannelID)
+	if err != nil || channel == nil {
+		fmt.Printf("Failed to load channel %s to remind membership %s: %v\n", membership.ChannelID, membership.ID, err)
+		return
+	}
+	trialEnd := membership.CurrentPeriodEnd.Format("02.01.2006 15:04")
+
+	checkout, err := s.StartSubscriptionCheckout(membership.SubscriberID, channel.ID, membership.PriceID, "", true)
+	var message string
+	switch {
+	case err != nil:
+		fmt.Printf("Failed to invoice trial membership %s: %v\n", membership.ID, err)
+		message = fmt.Sprintf("Пробный период в канале %s закончится %s, после чего доступ к каналу будет закрыт.",
+			channel.ChannelTitle, trialEnd)
+	case checkout.Payment.Status == entities.PaymentSucceeded:
+		// The provider charged right away and the subscriber was already told about the payment
+		return
+	case checkout.Payment.Status == entities.PaymentFailed:
+		message = fmt.Sprintf("Пробный период в канале %s закончится %s. Оплатить подписку не удалось, после окончания пробного периода доступ к каналу будет закрыт.",
+			channel.ChannelTitle, trialEnd)
+	default:
+		message = fmt.Sprintf("Пробный период в канале %s закончится %s. Чтобы сохранить доступ, оплатите подписку %s.",
+			channel.ChannelTitle, trialEnd, checkout.Payment.Amount)
+		if checkout.CheckoutURL != "" {
+			message += "\n" + checkout.CheckoutURL
+		}
+	}
+
+	if err := s.telegramBot.SendMessage(membership.SubscriberID, message); err != nil {
+		fmt.Printf("Failed to send trial reminder to user %d: %v\n", membership.SubscriberID, err)
+	}
+}
@@ -1,53 +1,141 @@
 package services
 
 import (
-	"encoding/base64"
+	"context"
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
+	"sync"
 	"time"
+	"tribute-back/internal/config"
+	"tribute-back/internal/domain/card"
 	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
 	"tribute-back/internal/domain/repositories"
+	"tribute-back/internal/infrastructure/auth"
 	"tribute-back/internal/infrastructure/database/postgres"
+	"tribute-back/internal/infrastructure/documents"
+	"tribute-back/internal/infrastructure/payments"
 	"tribute-back/internal/infrastructure/payouts"
 	"tribute-back/internal/infrastructure/telegram"
+	"tribute-back/internal/infrastructure/vault"
+	"tribute-back/migrations"
 
 	"github.com/google/uuid"
 )
 
+// ErrPriceMismatch is returned when a confirmed payment doesn't match the price it was invoiced at.
+var ErrPriceMismatch = errors.New("paid amount does not match the invoice")
+
+var (
+	// ErrChannelNotFound is returned when a channel ID doesn't refer to an added channel.
+	ErrChannelNotFound = errors.New("channel not found")
+	// ErrNotChannelOwner is returned when a user acts on a channel added by someone else.
+	ErrNotChannelOwner = errors.New("channel does not belong to this user")
+	// ErrChannelNotVerified is returned when a channel's ownership hasn't been confirmed yet.
+	ErrChannelNotVerified = errors.New("channel is not verified")
+	// ErrNoSubscriptionTier is returned when a channel has no published subscription tier.
+	ErrNoSubscriptionTier = errors.New("channel has no subscription tier")
+)
+
 type TributeService struct {
-	users         repositories.UserRepository
-	channels      repositories.ChannelRepository
-	subs          repositories.SubscriptionRepository
-	payments      repositories.PaymentRepository
-	telegramBot   *telegram.BotService
-	payoutGateway payouts.Gateway
+	users           repositories.UserRepository
+	channels        repositories.ChannelRepository
+	subs            repositories.SubscriptionRepository
+	prices          repositories.TierPriceRepository
+	payments        repositories.PaymentRepository
+	refunds         repositories.RefundRepository
+	promoCodes      repositories.PromoCodeRepository
+	startLinks      repositories.StartLinkRepository
+	referrals       repositories.ReferralRepository
+	memberships     repositories.MembershipRepository
+	payouts         repositories.PayoutRepository
+	partnerRequests repositories.PartnerRequestRepository
+	verifications   repositories.VerificationRepository
+	ledger          *LedgerService
+	telegramBot     *telegram.BotService
+	alerts          telegram.AlertThrottle
+	providers       *payments.Registry
+	payoutGateway   payouts.Gateway
+	payoutPolicy    PayoutPolicy
+	vault           *vault.Vault
+	documents       documents.Store
+	partners        *auth.PartnerAuthenticator
+	billing         config.BillingConfig
+	channelChecks   config.ChannelCheckConfig
+	// promoMu keeps concurrent checkouts from redeeming a promo code beyond its limits
+	promoMu sync.Mutex
+	// refundMu keeps concurrent refunds from returning more than a payment's amount
+	refundMu sync.Mutex
+	// partnerMu keeps a replayed partner request from being accepted while the original is
+	partnerMu sync.Mutex
 }
 
 func NewTributeService(
 	users repositories.UserRepository,
 	channels repositories.ChannelRepository,
 	subs repositories.SubscriptionRepository,
+	prices repositories.TierPriceRepository,
 	payments repositories.PaymentRepository,
+	refunds repositories.RefundRepository,
+	promoCodes repositories.PromoCodeRepository,
+	startLinks repositories.StartLinkRepository,
+	referrals repositories.ReferralRepository,
+	memberships repositories.MembershipRepository,
+	payouts repositories.PayoutRepository,
+	partnerRequests repositories.PartnerRequestRepository,
+	verifications repositories.VerificationRepository,
+	ledger *LedgerService,
 	telegramBot *telegram.BotService,
+	alerts telegram.AlertThrottle,
+	providers *payments.Registry,
 	payoutGateway payouts.Gateway,
+	payoutPolicy PayoutPolicy,
+	vault *vault.Vault,
+	documents documents.Store,
+	partners *auth.PartnerAuthenticator,
+	billing config.BillingConfig,
+	channelChecks config.ChannelCheckConfig,
 ) *TributeService {
 	return &TributeService{
-		users:         users,
-		channels:      channels,
-		subs:          subs,
-		payments:      payments,
-		telegramBot:   telegramBot,
-		payoutGateway: payoutGateway,
+		users:           users,
+		channels:        channels,
+		subs:            subs,
+		prices:          prices,
+		payments:        payments,
+		refunds:         refunds,
+		promoCodes:      promoCodes,
+		startLinks:      startLinks,
+		referrals:       referrals,
+		memberships:     memberships,
+		payouts:         payouts,
+		partnerRequests: partnerRequests,
+		verifications:   verifications,
+		ledger:          ledger,
+		telegramBot:     telegramBot,
+		alerts:          alerts,
+		providers:       providers,
+		payoutGateway:   payoutGateway,
+		payoutPolicy:    payoutPolicy,
+		vault:           vault,
+		documents:       documents,
+		partners:        partners,
+		billing:         billing,
+		channelChecks:   channelChecks,
 	}
 }
 
+// DefaultCurrency returns the currency used when a request doesn't specify one.
+func (s *TributeService) DefaultCurrency() string {
+	return s.billing.Currency
+}
+
 type DashboardData struct {
 	User          *entities.User
 	Channels      []*entities.Channel
 	Subscriptions []*entities.Subscription
 	Payments      []*entities.Payment
+	// Referrals are the user's referral links to other creators' channels
+	Referrals []*ReferralLinkStats
 }
 
 func (s *TributeService) GetDashboardData(userID int64) (*DashboardData, error) {
@@ -68,30 +156,86 @@ func (s *TributeService) GetDashboardData(userID int64) (*DashboardData, error)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.loadTierPrices(subscriptions); err != nil {
+		return nil, err
+	}
 
-	payments, err := s.payments.FindByUserID(userID)
+	payments, err := s.payments.FindByPayerID(userID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.loadEarned(user, subscriptions); err != nil {
+		return nil, err
+	}
+
+	referrals, err := s.getReferralStats(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referrals: %w", err)
+	}
+
 	return &DashboardData{
 		User:          user,
 		Channels:      channels,
 		Subscriptions: subscriptions,
 		Payments:      payments,
+		Referrals:     referrals,
 	}, nil
 }
 
+// loadEarned fills in the user's earnings from the ledger. Earnings are reported in the
+// currency of the user's first tier price, or the default currency if they have none.
+// The tiers' prices must already be loaded.
+func (s *TributeService) loadEarned(user *entities.User, subscriptions []*entities.Subscription) error {
+	currency := s.billing.Currency
+	for _, tier := range subscriptions {
+		if price := tier.DefaultPrice(); price != nil {
+			currency = price.Price.Currency
+			break
+		}
+	}
+
+	earned, err := s.ledger.CreatorBalance(user.ID, currency)
+	if err != nil {
+		return fmt.Errorf("failed to load balance: %w", err)
+	}
+	user.Earned = earned
+	return nil
+}
+
 // SendTelegramMessage sends a message to a user via Telegram bot
 func (s *TributeService) SendTelegramMessage(userID int64, message string) error {
 	return s.telegramBot.SendMessage(userID, message)
 }
 
-// SendAdminMessage sends a message to the admin chat
+// SendAdminMessage sends a message to the configured admin chat
 func (s *TributeService) SendAdminMessage(message string) error {
-	// Get admin chat ID from environment variable
-	adminChatID := int64(-4935327333) // Using the provided chat ID
-	return s.telegramBot.SendMessage(adminChatID, message)
+	return s.telegramBot.SendAdminMessage(message)
+}
+
+// SendThrottledAdminAlert sends an alert to the admin chat unless an alert about any of the
+// subjects, e.g. the client IP and the user, was sent within the throttle window, so that a
+// misbehaving client can't flood the chat. Returns whether the alert was sent.
+func (s *TributeService) SendThrottledAdminAlert(message string, subjects ...string) bool {
+	allowed := true
+	for _, subject := range subjects {
+		// Every subject is marked, even once the alert is known to be held back
+		ok, err := s.alerts.Allow(context.Background(), subject)
+		if err != nil {
+			// Losing the throttle must not hide alerts
+			fmt.Printf("Failed to throttle admin alert about %s: %v\n", subject, err)
+			ok = true
+		}
+		allowed = allowed && ok
+	}
+	if !allowed {
+		return false
+	}
+
+	if err := s.SendAdminMessage(message); err != nil {
+		fmt.Printf("Failed to send admin alert: %v\n", err)
+	}
+	return true
 }
 
 func (s *TributeService) AddBot(userID int64, channelTitle, channelUsername string) (*entities.Channel, error) {
@@ -106,11 +250,14 @@ func (s *TributeService) AddBot(userID int64, channelTitle, channelUsername stri
 		}
 	}
 
+	// The channel is checked in the background until the bot has been given the rights it needs
+	now := time.Now()
 	channel := &entities.Channel{
-		UserID:          userID,
-		ChannelTitle:    channelTitle,
-		ChannelUsername: channelUsername,
-		IsVerified:      false,
+		UserID:             userID,
+		ChannelTitle:       channelTitle,
+		ChannelUsername:    channelUsername,
+		IsVerified:         false,
+		NextVerificationAt: &now,
 	}
 
 	err = s.channels.Create(channel)
@@ -135,166 +282,46 @@ func (s *TributeService) GetChannelList(userID int64) ([]*entities.Channel, erro
 	return s.channels.FindByUserID(userID)
 }
 
-func (s *TributeService) CheckChannel(userID int64, channelID uuid.UUID) (bool, error) {
-	// Get channel by ID
-	channel, err := s.channels.FindByID(channelID)
-	if err != nil {
-		return false, err
-	}
-	if channel == nil {
-		return false, errors.New("channel not found")
-	}
-
-	// Check if user owns this channel
-	if channel.UserID != userID {
-		return false, errors.New("channel does not belong to this user")
-	}
-
-	// Check if user is owner/admin of the channel via Telegram API
-	chatMember, err := s.telegramBot.CheckChannelMembership(channel.ChannelUsername, userID)
-	if err != nil {
-		return false, fmt.Errorf("failed to check channel membership: %w", err)
-	}
-
-	// Check if user is owner or administrator
-	if chatMember.Status == "creator" || chatMember.Status == "administrator" {
-		// User is owner/admin, update verification status
-		channel.IsVerified = true
-		if err := s.channels.Update(channel); err != nil {
-			return false, fmt.Errorf("failed to update channel verification: %w", err)
-		}
-
-		// Send success message to user
-		successMessage := fmt.Sprintf("Good! You added bot to channel: %s (@%s)", channel.ChannelTitle, channel.ChannelUsername)
-		fmt.Printf("Attempting to send success message to user %d: %s\n", userID, successMessage)
-
-		if err := s.telegramBot.SendMessage(userID, successMessage); err != nil {
-			fmt.Printf("Failed to send success message to user %d: %v\n", userID, err)
-		} else {
-			fmt.Printf("Successfully sent success message to user %d\n", userID)
-		}
-
-		return true, nil
-	} else {
-		// User is not owner/admin, delete the channel
-		if err := s.channels.Delete(channelID); err != nil {
-			return false, fmt.Errorf("failed to delete channel: %w", err)
-		}
-		return false, nil
-	}
-}
-
-func (s *TributeService) RequestVerification(userID int64, userPhotoB64, userPassportB64 string) error {
-	photoReader := base64.NewDecoder(base64.StdEncoding, strings.NewReader(userPhotoB64))
-	passportReader := base64.NewDecoder(base64.StdEncoding, strings.NewReader(userPassportB64))
-
-	return s.telegramBot.SendVerificationRequest(userID, photoReader, passportReader)
-}
-
-func (s *TributeService) HandleVerificationCallback(chatID int64, messageID int, callbackData string) error {
-	parts := strings.Split(callbackData, "_")
-	if len(parts) != 3 || parts[0] != "verify" {
-		return fmt.Errorf("invalid callback data format: %s", callbackData)
-	}
-
-	action := parts[1]
-	userID, err := strconv.ParseInt(parts[2], 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid user id in callback data: %w", err)
-	}
-
+// SetUpPayouts saves the card the user is paid out to. The card number is validated and
+// kept only in the vault; the user record gets a token and the details safe to display.
+func (s *TributeService) SetUpPayouts(userID int64, cardNumber, cardExpiry string) error {
 	user, err := s.users.FindByID(userID)
 	if err != nil {
 		return err
 	}
 	if user == nil {
-		return errors.New("user for verification not found")
+		return errors.New("user not found")
 	}
-
-	if action == "approve" {
-		user.IsVerified = true
-		if err := s.users.Update(user); err != nil {
-			return err
-		}
-		// Verification successful, delete the message from admin chat
-		return s.telegramBot.DeleteMessage(chatID, messageID)
-	} else if action == "reject" {
-		// Verification rejected, notify the user and delete message from admin chat
-		if err := s.telegramBot.SendMessage(userID, "Ваша верификация была отклонена."); err != nil {
-			// Log error but don't block deletion
-		}
-		return s.telegramBot.DeleteMessage(chatID, messageID)
+	if !user.IsVerified {
+		return ErrPayoutNotAllowed
 	}
 
-	return fmt.Errorf("unknown action in callback data: %s", action)
-}
-
-func (s *TributeService) SetUpPayouts(userID int64, cardNumber string) error {
-	// Here you could add any business logic before contacting the payment gateway.
-	// For example, check if the user is verified.
-	user, err := s.users.FindByID(userID)
+	number, err := card.NormalizeNumber(cardNumber)
 	if err != nil {
 		return err
 	}
-	if !user.IsVerified {
-		return errors.New("user must be verified to set up payouts")
-	}
-
-	// Save card number to database
-	user.CardNumber = cardNumber
-	if err := s.users.Update(user); err != nil {
-		return fmt.Errorf("failed to save card number to database: %w", err)
+	expMonth, expYear, err := card.ParseExpiry(cardExpiry, time.Now())
+	if err != nil {
+		return err
 	}
 
-	// Note: We only save the card number to our database
-	// Payment gateway integration would be implemented here if needed
-	return nil
-}
-
-func (s *TributeService) PublishSubscription(userID int64, title, description, buttonText string, price float64) (*entities.Subscription, error) {
-	// Assumption: We use the user's first channel.
-	channels, err := s.channels.FindByUserID(userID)
+	payoutCard, err := s.storePayoutCard(number, expMonth, expYear)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to store card: %w", err)
 	}
-	if len(channels) == 0 {
-		return nil, errors.New("user has no channels to publish a subscription for")
-	}
-	channel := channels[0] // Use the first channel
 
-	// Check if a subscription for this channel already exists
-	subscription, err := s.subs.FindByChannelID(channel.ID)
-	if err != nil {
-		return nil, err
+	previous := user.PayoutCard
+	user.PayoutCard = payoutCard
+	if err := s.users.Update(user); err != nil {
+		return fmt.Errorf("failed to save card to database: %w", err)
 	}
 
-	if subscription != nil {
-		// Update existing subscription
-		subscription.Title = title
-		subscription.Description = description
-		subscription.ButtonText = buttonText
-		subscription.Price = price
-		if err := s.subs.Update(subscription); err != nil {
-			return nil, err
-		}
-	} else {
-		// Create new subscription
-		subscription = &entities.Subscription{
-			ChannelID:       channel.ID,
-			UserID:          userID,
-			ChannelUsername: channel.ChannelUsername,
-			Title:           title,
-			Description:     description,
-			ButtonText:      buttonText,
-			Price:           price,
-			CreatedDate:     time.Now(),
-		}
-		if err := s.subs.Create(subscription); err != nil {
-			return nil, err
+	if previous != nil {
+		if err := s.vault.Delete(previous.Token); err != nil {
+			fmt.Printf("Failed to delete previous card of user %d from the vault: %v\n", userID, err)
 		}
 	}
-
-	return subscription, nil
+	return nil
 }
 
 // OnboardUser creates a user if they don't exist and returns dashboard data
@@ -305,12 +332,23 @@ func (s *TributeService) OnboardUser(userID int64) (*entities.User, bool, error)
 	}
 
 	if user != nil {
+		subscriptions, err := s.subs.FindByUserID(userID)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := s.loadTierPrices(subscriptions); err != nil {
+			return nil, false, err
+		}
+		if err := s.loadEarned(user, subscriptions); err != nil {
+			return nil, false, err
+		}
 		return user, false, nil // User already exists
 	}
 
 	// Create new user
 	user = &entities.User{
 		ID:          userID,
+		Earned:      money.Zero(s.billing.Currency),
 		IsVerified:  false,
 		IsOnboarded: true,
 	}
@@ -336,6 +374,7 @@ func (s *TributeService) CreateUser(userID int64) (*entities.User, error) {
 	// Create new user
 	user = &entities.User{
 		ID:          userID,
+		Earned:      money.Zero(s.billing.Currency),
 		IsVerified:  false,
 		IsOnboarded: true,
 	}
@@ -347,38 +386,19 @@ func (s *TributeService) CreateUser(userID int64) (*entities.User, error) {
 	return user, nil
 }
 
-// CreateSubscription creates a subscription for a user
-func (s *TributeService) CreateSubscription(subscriberID int64, creatorID int64, price float64) error {
-	// Get creator's subscription
-	creatorChannels, err := s.channels.FindByUserID(creatorID)
-	if err != nil {
-		return err
-	}
-	if len(creatorChannels) == 0 {
-		return errors.New("creator has no channels")
-	}
-
-	creatorSubscription, err := s.subs.FindByChannelID(creatorChannels[0].ID)
+// findOwnedChannel returns the channel if it exists and was added by the user.
+func (s *TributeService) findOwnedChannel(userID int64, channelID uuid.UUID) (*entities.Channel, error) {
+	channel, err := s.channels.FindByID(channelID)
 	if err != nil {
-		return err
-	}
-	if creatorSubscription == nil {
-		return errors.New("creator has no subscription tier")
+		return nil, err
 	}
-
-	// Create payment record
-	payment := &entities.Payment{
-		ID:          uuid.New(),
-		UserID:      subscriberID,
-		Description: fmt.Sprintf("Subscription to user %d", creatorID),
-		CreatedDate: time.Now(),
+	if channel == nil {
+		return nil, ErrChannelNotFound
 	}
-
-	if err := s.payments.Create(payment); err != nil {
-		return err
+	if channel.UserID != userID {
+		return nil, ErrNotChannelOwner
 	}
-
-	return nil
+	return channel, nil
 }
 
 // ResetDatabase resets all data in the database (for development/testing)
@@ -386,65 +406,12 @@ func (s *TributeService) ResetDatabase() error {
 	// Get database connection from user repository
 	db := s.users.(*postgres.PgUserRepository).GetDB()
 
-	// Drop all tables in correct order (due to foreign key constraints)
-	queries := []string{
-		"DROP TABLE IF EXISTS payments CASCADE",
-		"DROP TABLE IF EXISTS subscriptions CASCADE",
-		"DROP TABLE IF EXISTS channels CASCADE",
-		"DROP TABLE IF EXISTS users CASCADE",
-	}
-
-	for _, query := range queries {
-		if _, err := db.Exec(query); err != nil {
-			return fmt.Errorf("failed to execute query '%s': %w", query, err)
-		}
+	// Revert every migration (drops all tables) and apply them again
+	if err := migrations.Down(db); err != nil {
+		return fmt.Errorf("failed to drop tables: %w", err)
 	}
-
-	// Recreate tables by running the migration
-	migrationQueries := []string{
-		`CREATE TABLE IF NOT EXISTS users (
-			user_id BIGINT PRIMARY KEY,
-			earned NUMERIC(10, 2) DEFAULT 0.00,
-			is_verified BOOLEAN DEFAULT FALSE,
-			is_sub_published BOOLEAN DEFAULT FALSE,
-			is_onboarded BOOLEAN DEFAULT FALSE,
-			card_number VARCHAR(255)
-		)`,
-		`CREATE TABLE IF NOT EXISTS channels (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
-			channel_title VARCHAR(255) NOT NULL,
-			channel_username VARCHAR(255) UNIQUE NOT NULL,
-			is_verified BOOLEAN DEFAULT FALSE
-		)`,
-		`CREATE TABLE IF NOT EXISTS subscriptions (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			channel_id UUID NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
-			user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
-			channel_username VARCHAR(255) NOT NULL,
-			title VARCHAR(255) NOT NULL,
-			description TEXT,
-			button_text VARCHAR(255),
-			price NUMERIC(10, 2) NOT NULL,
-			created_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS payments (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
-			description TEXT,
-			created_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_users_user_id ON users(user_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_channels_user_id ON channels(user_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id ON subscriptions(user_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_subscriptions_channel_id ON subscriptions(channel_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_payments_user_id ON payments(user_id)`,
-	}
-
-	for _, query := range migrationQueries {
-		if _, err := db.Exec(query); err != nil {
-			return fmt.Errorf("failed to recreate table: %w", err)
-		}
+	if err := migrations.Up(db); err != nil {
+		return fmt.Errorf("failed to recreate tables: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/telegram"
+
+	"github.com/google/uuid"
+)
+
+// UpdateDispatcher routes incoming Telegram updates to the service methods that handle them.
+// It is shared by every update source (webhook and long polling).
+type UpdateDispatcher struct {
+	tribute     *TributeService
+	telegramBot *telegram.BotService
+}
+
+func NewUpdateDispatcher(tribute *TributeService, telegramBot *telegram.BotService) *UpdateDispatcher {
+	return &UpdateDispatcher{
+		tribute:     tribute,
+		telegramBot: telegramBot,
+	}
+}
+
+// Dispatch handles a single update. Returned errors are meant for logging only:
+// Telegram must not redeliver an update just because our handling of it failed.
+func (d *UpdateDispatcher) Dispatch(update *telegram.Update) error {
+	switch {
+	case update.CallbackQuery != nil:
+		return d.handleCallbackQuery(update.CallbackQuery)
+	case update.PreCheckoutQuery != nil:
+		return d.handlePreCheckoutQuery(update.PreCheckoutQuery)
+	case update.MyChatMember != nil:
+		return d.handleMyChatMember(update.MyChatMember)
+	case update.Message != nil && update.Message.SuccessfulPayment != nil:
+		return d.handleSuccessfulPayment(update.Message)
+	case update.Message != nil && d.telegramBot.IsCommand(update.Message.Text, "/refund"):
+		return d.handleRefundCommand(update.Message)
+	default:
+		// Update types we don't act on are acknowledged silently.
+		return nil
+	}
+}
+
+func (d *UpdateDispatcher) handleCallbackQuery(query *telegram.CallbackQuery) error {
+	if strings.HasPrefix(query.Data, "verify_") {
+		return d.handleVerificationCallback(query)
+	}
+
+	// Unknown buttons still have to be answered, otherwise the client keeps showing a spinner.
+	return d.telegramBot.AnswerCallbackQuery(query.ID, "")
+}
+
+func (d *UpdateDispatcher) handleVerificationCallback(query *telegram.CallbackQuery) error {
+	// Verification buttons are only ever sent to the admin chat.
+	if query.Message == nil || !d.telegramBot.IsAdminChat(query.Message.Chat.ID) {
+		if err := d.telegramBot.AnswerCallbackQuery(query.ID, "Недостаточно прав"); err != nil {
+			fmt.Printf("Failed to answer callback query %s: %v\n", query.ID, err)
+		}
+		return fmt.Errorf("verification callback from user %d outside the admin chat", query.From.ID)
+	}
+
+	err := d.tribute.HandleVerificationCallback(query.Message.Chat.ID, query.Message.MessageID, query.From.ID, query.Data)
+
+	answer := "Готово"
+	if err != nil {
+		answer = "Не удалось обработать запрос"
+	}
+	if answerErr := d.telegramBot.AnswerCallbackQuery(query.ID, answer); answerErr != nil && err == nil {
+		return answerErr
+	}
+	return err
+}
+
+func (d *UpdateDispatcher) handlePreCheckoutQuery(query *telegram.PreCheckoutQuery) error {
+	paid := money.New(query.TotalAmount, query.Currency)
+	err := d.tribute.ValidateCheckout(query.From.ID, query.InvoicePayload, paid)
+	if err == nil {
+		return d.telegramBot.AnswerPreCheckoutQuery(query.ID, true, "")
+	}
+
+	reason := "Не удалось проверить счёт, попробуйте позже"
+	if errors.Is(err, ErrInvoiceNotPayable) {
+		reason = "Этот счёт уже оплачен или больше не действителен"
+	}
+	if answerErr := d.telegramBot.AnswerPreCheckoutQuery(query.ID, false, reason); answerErr != nil {
+		fmt.Printf("Failed to answer pre-checkout query %s: %v\n", query.ID, answerErr)
+	}
+	return fmt.Errorf("rejected pre-checkout query %s: %w", query.ID, err)
+}
+
+// handleMyChatMember registers, checks or unverifies a channel when the bot's status in it changes.
+func (d *UpdateDispatcher) handleMyChatMember(update *telegram.ChatMemberUpdated) error {
+	return d.tribute.HandleBotMembership(BotMembershipChange{
+		ChatID:       update.Chat.ID,
+		ChatType:     update.Chat.Type,
+		ChatTitle:    update.Chat.Title,
+		ChatUsername: update.Chat.Username,
+		ChangedBy:    update.From.ID,
+		Status:       update.NewChatMember.Status,
+	})
+}
+
+func (d *UpdateDispatcher) handleSuccessfulPayment(message *telegram.Message) error {
+	payment := message.SuccessfulPayment
+	// Invoices are paid in the private chat with the bot, whose ID is the user's
+	payerID := message.Chat.ID
+	if message.From != nil {
+		payerID = message.From.ID
+	}
+
+	paid := money.New(payment.TotalAmount, payment.Currency)
+	if err := d.tribute.CompleteInvoicePayment(payerID, payment.InvoicePayload, paid, payment.TelegramPaymentChargeID); err != nil {
+		// The user has been charged, so this needs a human
+		alert := fmt.Sprintf("⚠️ Оплата %s от пользователя %d не обработана: %v", payment.TelegramPaymentChargeID, payerID, err)
+		if alertErr := d.telegramBot.SendAdminMessage(alert); alertErr != nil {
+			fmt.Printf("Failed to send payment alert: %v\n", alertErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// refundUsage explains the /refund command to admins.
+const refundUsage = "Использование: /refund <id платежа> [сумма] [revoke]"
+
+// handleRefundCommand lets admins refund a payment from the admin chat:
+// /refund <payment id> [amount] [revoke]. Without an amount the payment is refunded in
+// full; "revoke" also removes the payer from the channel. The command message's ID is the
+// idempotency key, so a redelivered update doesn't refund twice.
+func (d *UpdateDispatcher) handleRefundCommand(message *telegram.Message) error {
+	// Other chats may use the bot's commands too; only the admin chat can refund
+	if !d.telegramBot.IsAdminChat(message.Chat.ID) || message.From == nil {
+		return nil
+	}
+
+	args := strings.Fields(message.Text)[1:]
+	details := RefundDetails{
+		Reason:         "возврат по решению администратора",
+		IdempotencyKey: fmt.Sprintf("admin:%d", message.MessageID),
+	}
+	if len(args) > 0 && args[len(args)-1] == "revoke" {
+		details.RevokeAccess = true
+		args = args[:len(args)-1]
+	}
+	if len(args) == 2 {
+		details.Amount = args[1]
+	}
+	var paymentID uuid.UUID
+	var err error
+	if len(args) > 0 && len(args) <= 2 {
+		paymentID, err = uuid.Parse(args[0])
+	}
+	if len(args) == 0 || len(args) > 2 || err != nil {
+		return d.telegramBot.SendAdminMessage(refundUsage)
+	}
+
+	refund, err := d.tribute.AdminRefundPayment(message.From.ID, paymentID, details)
+	var reply string
+	if err != nil {
+		reply = fmt.Sprintf("Не удалось вернуть платёж %s: %v", paymentID, err)
+	} else {
+		reply = fmt.Sprintf("Платёж %s: возвращено %s, всего возвращено %s из %s.",
+			paymentID, refund.Refund.Amount, refund.Payment.Refunded, refund.Payment.Amount)
+	}
+	if replyErr := d.telegramBot.SendAdminMessage(reply); replyErr != nil && err == nil {
+		return replyErr
+	}
+	return err
+}
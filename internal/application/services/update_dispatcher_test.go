@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/infrastructure/payments"
+	"tribute-back/internal/infrastructure/telegram"
+)
+
+func TestRefundCommandMatchesExactly(t *testing.T) {
+	tests := []struct {
+		command  string
+		refunded bool
+	}{
+		{command: "/refund", refunded: true},
+		{command: "/refund@tribute_test_bot", refunded: true},
+		{command: "/refund@Tribute_Test_Bot", refunded: true},
+		{command: "/refunds"},
+		{command: "/refundxyz"},
+		{command: "/refund@other_bot"},
+		{command: "/refund_all"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			env := newTestEnv(t, testOptions{provider: payments.SimulatorProviderName})
+			payment := env.checkout(t).Payment
+
+			err := env.dispatcher.Dispatch(&telegram.Update{Message: &telegram.Message{
+				MessageID: 10,
+				From:      &telegram.User{ID: 1},
+				Chat:      telegram.Chat{ID: testAdminChatID, Type: "supergroup"},
+				Text:      tt.command + " " + payment.ID.String(),
+			}})
+			if err != nil {
+				t.Fatalf("Dispatch: %v", err)
+			}
+
+			refunded := env.payment(t, payment.ID).Status == entities.PaymentRefunded
+			if refunded != tt.refunded {
+				t.Errorf("payment refunded = %t, want %t", refunded, tt.refunded)
+			}
+			if replies := env.telegram.messagesTo(testAdminChatID); (len(replies) != 0) != tt.refunded {
+				t.Errorf("admin chat replies = %q, want a reply only to the refund command", replies)
+			}
+		})
+	}
+}
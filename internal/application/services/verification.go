@@ -0,0 +1,365 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tribute-back/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidDocument is returned when an uploaded document isn't valid base64 or is empty.
+	ErrInvalidDocument = errors.New("documents must be non-empty base64 encoded images")
+	// ErrAlreadyVerified is returned when a verified user submits documents again.
+	ErrAlreadyVerified = errors.New("user is already verified")
+	// ErrVerificationPending is returned when the user already has a request waiting for a decision.
+	ErrVerificationPending = errors.New("a verification request is already being reviewed")
+	// ErrVerificationNotFound is returned when a request ID doesn't refer to a request of the user.
+	ErrVerificationNotFound = errors.New("verification request not found")
+	// ErrVerificationDecided is returned when a request that was already decided is decided again.
+	ErrVerificationDecided = errors.New("verification request has already been decided")
+)
+
+// verificationDeliveryTimeout is how long a delivery may take before the request is
+// considered abandoned, e.g. because the server stopped while sending it, and is sent again.
+const verificationDeliveryTimeout = 10 * time.Minute
+
+// Reasons given to the user when a request is decided with a button in the admin chat.
+const (
+	defaultRejectReason   = "Документы не прошли проверку."
+	defaultResubmitReason = "Не удалось прочитать документы, загрузите их заново."
+)
+
+// VerificationOverview is a user's verification status with their latest request and its history.
+type VerificationOverview struct {
+	IsVerified bool
+	// Request is nil if the user never submitted documents
+	Request *entities.VerificationRequest
+	History []*entities.VerificationEvent
+}
+
+// RequestVerification stores the user's photo and passport scan and opens a verification
+// request for them, then sends the documents to the admin chat for review. If Telegram
+// can't be reached the request stays submitted and DeliverVerifications sends it later.
+func (s *TributeService) RequestVerification(userID int64, userPhotoB64, userPassportB64 string) (*entities.VerificationRequest, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if user.IsVerified {
+		return nil, ErrAlreadyVerified
+	}
+	latest, err := s.verifications.FindLatestByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.IsOpen() {
+		return nil, ErrVerificationPending
+	}
+
+	photo, err := decodeDocument(userPhotoB64)
+	if err != nil {
+		return nil, err
+	}
+	passport, err := decodeDocument(userPassportB64)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	request := &entities.VerificationRequest{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Status:      entities.VerificationSubmitted,
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+	request.PhotoKey = fmt.Sprintf("verification/%s/photo.jpg", request.ID)
+	request.PassportKey = fmt.Sprintf("verification/%s/passport.jpg", request.ID)
+	if err := s.documents.Put(request.PhotoKey, bytes.NewReader(photo)); err != nil {
+		return nil, fmt.Errorf("failed to store photo: %w", err)
+	}
+	if err := s.documents.Put(request.PassportKey, bytes.NewReader(passport)); err != nil {
+		s.deleteDocuments(request)
+		return nil, fmt.Errorf("failed to store passport: %w", err)
+	}
+	if err := s.verifications.Create(request); err != nil {
+		s.deleteDocuments(request)
+		return nil, err
+	}
+
+	// The request is safely stored, so a Telegram failure must not fail it
+	if _, err := s.deliverVerification(request); err != nil {
+		fmt.Printf("Failed to send verification request %s to the admin chat: %v\n", request.ID, err)
+	}
+	return request, nil
+}
+
+// GetVerification returns the user's verification status and their latest request with its history.
+func (s *TributeService) GetVerification(userID int64) (*VerificationOverview, error) {
+	user, err := s.users.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	overview := &VerificationOverview{IsVerified: user.IsVerified}
+	overview.Request, err = s.verifications.FindLatestByUserID(userID)
+	if err != nil || overview.Request == nil {
+		return overview, err
+	}
+	overview.History, err = s.verifications.FindEvents(overview.Request.ID)
+	if err != nil {
+		return nil, err
+	}
+	return overview, nil
+}
+
+// DeliverVerifications sends the requests that haven't reached the admin chat yet. Returns
+// how many were sent.
+func (s *TributeService) DeliverVerifications() (int, error) {
+	pending, err := s.verifications.FindDueForDelivery(time.Now().Add(-verificationDeliveryTimeout))
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, request := range pending {
+		sent, err := s.deliverVerification(request)
+		if err != nil {
+			// The request goes back to submitted and is tried on the next run
+			fmt.Printf("Failed to send verification request %s to the admin chat: %v\n", request.ID, err)
+			continue
+		}
+		if sent {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliverVerification claims the request, sends its documents to the admin chat and puts it
+// in review. Claiming keeps RequestVerification and the delivery worker from both sending
+// the same request; it reports false if the request was claimed by another delivery.
+func (s *TributeService) deliverVerification(request *entities.VerificationRequest) (bool, error) {
+	request.Status = entities.VerificationDelivering
+	request.UpdatedAt = time.Now()
+	claimed, err := s.verifications.ClaimForDelivery(request, request.UpdatedAt.Add(-verificationDeliveryTimeout))
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	if err := s.sendVerification(request); err != nil {
+		request.Status = entities.VerificationSubmitted
+		request.UpdatedAt = time.Now()
+		if _, releaseErr := s.verifications.Transition(request, entities.VerificationDelivering); releaseErr != nil {
+			fmt.Printf("Failed to release verification request %s for another delivery: %v\n", request.ID, releaseErr)
+		}
+		return false, err
+	}
+
+	// A request decided while it was being sent keeps its decision
+	request.Status = entities.VerificationInReview
+	request.UpdatedAt = time.Now()
+	if _, err := s.verifications.Transition(request, entities.VerificationDelivering); err != nil {
+		return true, fmt.Errorf("failed to put verification request in review: %w", err)
+	}
+	return true, nil
+}
+
+// sendVerification sends the request's documents to the admin chat.
+func (s *TributeService) sendVerification(request *entities.VerificationRequest) error {
+	photo, err := s.documents.Get(request.PhotoKey)
+	if err != nil {
+		return fmt.Errorf("failed to open photo: %w", err)
+	}
+	defer photo.Close()
+	passport, err := s.documents.Get(request.PassportKey)
+	if err != nil {
+		return fmt.Errorf("failed to open passport: %w", err)
+	}
+	defer passport.Close()
+
+	return s.telegramBot.SendVerificationRequest(request.ID.String(), request.UserID, photo, passport)
+}
+
+// ReviewVerification decides an open verification request: approving it verifies the user,
+// rejecting it or asking for new documents tells the user why. The reviewer is recorded
+// with the decision.
+func (s *TributeService) ReviewVerification(requestID uuid.UUID, status entities.VerificationStatus, reviewer, reason string) (*entities.VerificationRequest, error) {
+	switch status {
+	case entities.VerificationApproved, entities.VerificationRejected, entities.VerificationResubmit:
+	default:
+		return nil, fmt.Errorf("unknown verification decision: %s", status)
+	}
+
+	request, err := s.verifications.FindByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrVerificationNotFound
+	}
+	if !request.IsOpen() {
+		return nil, ErrVerificationDecided
+	}
+
+	user, err := s.users.FindByID(request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user for verification not found")
+	}
+
+	// The user is verified before the decision is saved, so that a failure leaves the request
+	// open to be decided again
+	var message string
+	switch status {
+	case entities.VerificationApproved:
+		if !user.IsVerified {
+			user.IsVerified = true
+			if err := s.users.Update(user); err != nil {
+				return nil, fmt.Errorf("failed to update user verification status: %w", err)
+			}
+		}
+		message = "Ваша верификация подтверждена."
+	case entities.VerificationRejected:
+		message = "Ваша верификация была отклонена."
+	default:
+		message = "Для верификации нужно загрузить документы заново."
+	}
+
+	now := time.Now()
+	from := request.Status
+	request.Status = status
+	request.Reviewer = reviewer
+	request.Reason = reason
+	request.UpdatedAt = now
+	request.ReviewedAt = &now
+	saved, err := s.verifications.Transition(request, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save verification decision: %w", err)
+	}
+	if !saved {
+		return nil, ErrVerificationDecided
+	}
+
+	if reason != "" {
+		message += "\n" + reason
+	}
+	if err := s.telegramBot.SendMessage(request.UserID, message); err != nil {
+		fmt.Printf("Failed to send verification decision to user %d: %v\n", request.UserID, err)
+	}
+	return request, nil
+}
+
+// HandleVerificationCallback decides a verification request from a button in the admin chat
+// and removes the request's message. The buttons carry the request ID; buttons sent before
+// requests were stored carry the user ID and decide the user's open request.
+func (s *TributeService) HandleVerificationCallback(chatID int64, messageID int, reviewerID int64, callbackData string) error {
+	parts := strings.Split(callbackData, "_")
+	if len(parts) != 3 || parts[0] != "verify" {
+		return fmt.Errorf("invalid callback data format: %s", callbackData)
+	}
+
+	var status entities.VerificationStatus
+	var reason string
+	switch parts[1] {
+	case "approve":
+		status = entities.VerificationApproved
+	case "reject":
+		status, reason = entities.VerificationRejected, defaultRejectReason
+	case "resubmit":
+		status, reason = entities.VerificationResubmit, defaultResubmitReason
+	default:
+		return fmt.Errorf("unknown action in callback data: %s", parts[1])
+	}
+
+	requestID, err := uuid.Parse(parts[2])
+	if err != nil {
+		userID, parseErr := strconv.ParseInt(parts[2], 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("invalid request id in callback data: %w", err)
+		}
+		request, err := s.verifications.FindLatestByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if request == nil {
+			return ErrVerificationNotFound
+		}
+		requestID = request.ID
+	}
+
+	_, err = s.ReviewVerification(requestID, status, fmt.Sprintf("admin:%d", reviewerID), reason)
+	if err != nil && !errors.Is(err, ErrVerificationDecided) {
+		return err
+	}
+	// A request decided elsewhere, e.g. by the KYC provider, doesn't need its message any more
+	if deleteErr := s.telegramBot.DeleteMessage(chatID, messageID); deleteErr != nil && err == nil {
+		return deleteErr
+	}
+	return err
+}
+
+// ApplyPartnerVerification applies the decision a KYC partner made about a user. With a
+// request ID the decision is recorded on that request; without one it goes to the user's
+// open request, or only sets the user's status if they have none.
+func (s *TributeService) ApplyPartnerVerification(partner string, userID int64, requestID *uuid.UUID, isVerified bool, reason string) error {
+	var request *entities.VerificationRequest
+	var err error
+	if requestID != nil {
+		request, err = s.verifications.FindByID(*requestID)
+		if err != nil {
+			return err
+		}
+		if request == nil || request.UserID != userID {
+			return ErrVerificationNotFound
+		}
+	} else {
+		request, err = s.verifications.FindLatestByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if request == nil || !request.IsOpen() {
+			return s.UpdateUserVerification(userID, isVerified)
+		}
+	}
+
+	status := entities.VerificationRejected
+	if isVerified {
+		status = entities.VerificationApproved
+	}
+	_, err = s.ReviewVerification(request.ID, status, "partner:"+partner, reason)
+	return err
+}
+
+// decodeDocument decodes an uploaded base64 document.
+func decodeDocument(encoded string) ([]byte, error) {
+	document, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(document) == 0 {
+		return nil, ErrInvalidDocument
+	}
+	return document, nil
+}
+
+// deleteDocuments removes a request's documents after it failed to be stored.
+func (s *TributeService) deleteDocuments(request *entities.VerificationRequest) {
+	for _, key := range []string{request.PhotoKey, request.PassportKey} {
+		if err := s.documents.Delete(key); err != nil {
+			fmt.Printf("Failed to delete document %s: %v\n", key, err)
+		}
+	}
+}
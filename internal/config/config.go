@@ -1,7 +1,11 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -19,6 +23,69 @@ func GetEnv(key, fallback string) string {
 	return fallback
 }
 
+// GetDurationEnv retrieves a duration (e.g. "30s", "5m") from an environment variable with a fallback value
+func GetDurationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration %q in %s, using %s", value, key, fallback)
+		return fallback
+	}
+	return d
+}
+
+// GetIntEnv retrieves an integer from an environment variable with a fallback value
+func GetIntEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid integer %q in %s, using %d", value, key, fallback)
+		return fallback
+	}
+	return n
+}
+
+// GetListEnv retrieves comma-separated values (e.g. "10.0.0.1,10.0.1.0/24") from an
+// environment variable with a fallback value
+func GetListEnv(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// GetMapEnv retrieves comma-separated key:value pairs (e.g. "RUB:500.00,USD:10.00")
+// from an environment variable with a fallback value
+func GetMapEnv(key string, fallback map[string]string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || k == "" {
+			log.Printf("Invalid entry %q in %s, using %v", pair, key, fallback)
+			return fallback
+		}
+		result[k] = v
+	}
+	return result
+}
+
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
 	Host     string
@@ -72,3 +139,205 @@ func GetJWTConfig() JWTConfig {
 		Expiry: GetEnv("JWT_EXPIRY", "24h"),
 	}
 }
+
+// Telegram update delivery modes
+const (
+	TelegramUpdatesWebhook = "webhook"
+	TelegramUpdatesPolling = "polling"
+)
+
+// TelegramConfig holds Telegram Bot API configuration
+type TelegramConfig struct {
+	BotToken      string
+	AdminChatID   string
+	APIURL        string
+	UpdatesMode   string
+	WebhookURL    string
+	WebhookSecret string
+	PollTimeout   time.Duration
+	// PaymentProviderToken comes from @BotFather and is needed for invoices in fiat currencies
+	PaymentProviderToken string
+	// BotUsername and MiniAppName build the t.me links that open the Mini App; without
+	// MiniAppName links open the bot's main Mini App
+	BotUsername string
+	MiniAppName string
+	// AlertThrottleWindow is how long further admin alerts about the same client are held back
+	AlertThrottleWindow time.Duration
+}
+
+// GetTelegramConfig returns Telegram configuration from environment variables
+func GetTelegramConfig() TelegramConfig {
+	return TelegramConfig{
+		BotToken:             GetEnv("TELEGRAM_BOT_TOKEN", ""),
+		AdminChatID:          GetEnv("TELEGRAM_ADMIN_CHAT_ID", ""),
+		APIURL:               GetEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+		UpdatesMode:          GetEnv("TELEGRAM_UPDATES_MODE", TelegramUpdatesWebhook),
+		WebhookURL:           GetEnv("TELEGRAM_WEBHOOK_URL", ""),
+		WebhookSecret:        GetEnv("TELEGRAM_WEBHOOK_SECRET", ""),
+		PollTimeout:          GetDurationEnv("TELEGRAM_POLL_TIMEOUT", 30*time.Second),
+		PaymentProviderToken: GetEnv("TELEGRAM_PAYMENT_PROVIDER_TOKEN", ""),
+		BotUsername:          GetEnv("TELEGRAM_BOT_USERNAME", ""),
+		MiniAppName:          GetEnv("TELEGRAM_MINI_APP_NAME", ""),
+		AlertThrottleWindow:  GetDurationEnv("ADMIN_ALERT_THROTTLE_WINDOW", 10*time.Minute),
+	}
+}
+
+// ServiceAuthConfig holds configuration for requests signed by our own services
+type ServiceAuthConfig struct {
+	// Secret is shared with the services, e.g. the bot process, that sign their requests
+	Secret string
+	// MaxSkew is how far the signed timestamp may be from the server's clock
+	MaxSkew time.Duration
+}
+
+// GetServiceAuthConfig returns service request signing configuration from environment variables
+func GetServiceAuthConfig() ServiceAuthConfig {
+	return ServiceAuthConfig{
+		Secret:  GetEnv("SERVICE_AUTH_SECRET", ""),
+		MaxSkew: GetDurationEnv("SERVICE_AUTH_MAX_SKEW", 5*time.Minute),
+	}
+}
+
+// BillingConfig holds subscription billing configuration
+type BillingConfig struct {
+	// Currency is the ISO 4217 code used when a request doesn't specify one
+	Currency string
+	// InviteLinkTTL is how long a one-time channel invite link stays valid
+	InviteLinkTTL time.Duration
+	// CommissionBPS is the platform commission in basis points (1000 = 10%)
+	CommissionBPS int
+	// TrialReminderBefore is how long before a free trial ends the subscriber is reminded and invoiced
+	TrialReminderBefore time.Duration
+	// RenewalInvoiceBefore is how long before a paid period ends the subscriber is invoiced for the next one
+	RenewalInvoiceBefore time.Duration
+	// ReferralMaxShareBPS caps the share of their earnings creators can give referrers, in basis points
+	ReferralMaxShareBPS int
+	// ReferralAttributionWindow is how long after opening a referral link a first payment counts as referred
+	ReferralAttributionWindow time.Duration
+}
+
+// GetBillingConfig returns billing configuration from environment variables
+func GetBillingConfig() BillingConfig {
+	return BillingConfig{
+		Currency:                  GetEnv("DEFAULT_CURRENCY", "RUB"),
+		InviteLinkTTL:             GetDurationEnv("INVITE_LINK_TTL", 24*time.Hour),
+		CommissionBPS:             GetIntEnv("PLATFORM_COMMISSION_BPS", 1000),
+		TrialReminderBefore:       GetDurationEnv("TRIAL_REMINDER_BEFORE", 24*time.Hour),
+		RenewalInvoiceBefore:      GetDurationEnv("RENEWAL_INVOICE_BEFORE", 72*time.Hour),
+		ReferralMaxShareBPS:       GetIntEnv("REFERRAL_MAX_SHARE_BPS", 5000),
+		ReferralAttributionWindow: GetDurationEnv("REFERRAL_ATTRIBUTION_WINDOW", 30*24*time.Hour),
+	}
+}
+
+// ChannelCheckConfig holds configuration for verifying channels in Telegram
+type ChannelCheckConfig struct {
+	// Interval is how often pending channels are looked at
+	Interval time.Duration
+	// RetryInterval is how long a pending channel waits between checks
+	RetryInterval time.Duration
+	// MaxAttempts is how many failed checks in a row are made before retries are given up
+	MaxAttempts int
+}
+
+// GetChannelCheckConfig returns channel verification configuration from environment variables
+func GetChannelCheckConfig() ChannelCheckConfig {
+	return ChannelCheckConfig{
+		Interval:      GetDurationEnv("CHANNEL_CHECK_INTERVAL", time.Minute),
+		RetryInterval: GetDurationEnv("CHANNEL_CHECK_RETRY_INTERVAL", 10*time.Minute),
+		MaxAttempts:   GetIntEnv("CHANNEL_CHECK_MAX_ATTEMPTS", 36),
+	}
+}
+
+// PartnerAuthConfig holds configuration for callbacks signed by integration partners
+type PartnerAuthConfig struct {
+	// Secrets are the signing secrets keyed by partner ID, e.g. kyc:<secret>
+	Secrets map[string]string
+	// MaxSkew is how far a partner's signed timestamp may be from the server's clock
+	MaxSkew time.Duration
+}
+
+// GetPartnerAuthConfig returns partner request signing configuration from environment variables
+func GetPartnerAuthConfig() PartnerAuthConfig {
+	return PartnerAuthConfig{
+		Secrets: GetMapEnv("PARTNER_SECRETS", map[string]string{}),
+		MaxSkew: GetDurationEnv("PARTNER_SIGNATURE_MAX_SKEW", 5*time.Minute),
+	}
+}
+
+// VerificationConfig holds configuration for identity verification requests
+type VerificationConfig struct {
+	// DocumentsDir is where users' verification documents are stored
+	DocumentsDir string
+	// DeliveryInterval is how often requests that haven't reached the admin chat are sent again
+	DeliveryInterval time.Duration
+}
+
+// GetVerificationConfig returns identity verification configuration from environment variables
+func GetVerificationConfig() VerificationConfig {
+	return VerificationConfig{
+		DocumentsDir:     GetEnv("DOCUMENTS_DIR", "./data/documents"),
+		DeliveryInterval: GetDurationEnv("VERIFICATION_DELIVERY_INTERVAL", time.Minute),
+	}
+}
+
+// PaymentsConfig holds payment collection configuration
+type PaymentsConfig struct {
+	// Provider is the payment provider new charges go through: "telegram" or "simulator"
+	Provider string
+	// SimulatorWebhookSecret signs webhooks sent to the simulator
+	SimulatorWebhookSecret string
+	// SimulatorDelay is how long delayed simulator charges wait before confirming themselves
+	SimulatorDelay time.Duration
+}
+
+// GetPaymentsConfig returns payments configuration from environment variables
+func GetPaymentsConfig() PaymentsConfig {
+	return PaymentsConfig{
+		Provider:               GetEnv("PAYMENT_PROVIDER", "telegram"),
+		SimulatorWebhookSecret: GetEnv("PAYMENT_SIMULATOR_WEBHOOK_SECRET", ""),
+		SimulatorDelay:         GetDurationEnv("PAYMENT_SIMULATOR_DELAY", 5*time.Second),
+	}
+}
+
+// PayoutConfig holds payout pipeline configuration
+type PayoutConfig struct {
+	// MinAmounts is the smallest payout per currency, as decimal amounts in major units
+	MinAmounts map[string]string
+	// MaxAttempts is how many times a payout is submitted before it is given up
+	MaxAttempts int
+	// Interval is how often pending payouts are submitted and checked
+	Interval time.Duration
+	// MockFailureMode and MockProcessingPolls configure the mock payout gateway
+	MockFailureMode     string
+	MockProcessingPolls int
+}
+
+// GetPayoutConfig returns payout configuration from environment variables
+func GetPayoutConfig() PayoutConfig {
+	return PayoutConfig{
+		MinAmounts:          GetMapEnv("PAYOUT_MIN_AMOUNTS", map[string]string{"RUB": "500.00"}),
+		MaxAttempts:         GetIntEnv("PAYOUT_MAX_ATTEMPTS", 5),
+		Interval:            GetDurationEnv("PAYOUT_INTERVAL", time.Minute),
+		MockFailureMode:     GetEnv("PAYOUT_MOCK_FAILURE_MODE", "none"),
+		MockProcessingPolls: GetIntEnv("PAYOUT_MOCK_PROCESSING_POLLS", 1),
+	}
+}
+
+// VaultConfig holds encryption configuration for stored secrets
+type VaultConfig struct {
+	// Keys are base64-encoded 256-bit key encryption keys by ID
+	Keys map[string]string
+	// ActiveKey is the ID of the key new secrets are encrypted with
+	ActiveKey string
+	// RotationInterval is how often secrets are re-wrapped with the active key
+	RotationInterval time.Duration
+}
+
+// GetVaultConfig returns vault configuration from environment variables
+func GetVaultConfig() VaultConfig {
+	return VaultConfig{
+		Keys:             GetMapEnv("VAULT_KEYS", map[string]string{}),
+		ActiveKey:        GetEnv("VAULT_ACTIVE_KEY", ""),
+		RotationInterval: GetDurationEnv("VAULT_ROTATION_INTERVAL", time.Hour),
+	}
+}
@@ -0,0 +1,132 @@
+// Package card validates and describes payment card numbers without storing them.
+package card
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidNumber is returned for card numbers that are malformed or fail the Luhn check.
+	ErrInvalidNumber = errors.New("invalid card number")
+	// ErrInvalidExpiry is returned for malformed or past expiry dates.
+	ErrInvalidExpiry = errors.New("invalid card expiry")
+)
+
+// Card brands recognised from the number prefix
+const (
+	BrandVisa       = "visa"
+	BrandMastercard = "mastercard"
+	BrandMir        = "mir"
+	BrandAmex       = "amex"
+	BrandUnionPay   = "unionpay"
+	BrandMaestro    = "maestro"
+	BrandUnknown    = "unknown"
+)
+
+// NormalizeNumber strips spaces and dashes and checks the length and Luhn checksum.
+func NormalizeNumber(number string) (string, error) {
+	number = strings.NewReplacer(" ", "", "-", "").Replace(number)
+	if len(number) < 12 || len(number) > 19 {
+		return "", fmt.Errorf("%w: must have 12 to 19 digits", ErrInvalidNumber)
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: must contain only digits", ErrInvalidNumber)
+		}
+	}
+	if !luhn(number) {
+		return "", fmt.Errorf("%w: checksum mismatch", ErrInvalidNumber)
+	}
+	return number, nil
+}
+
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// Brand guesses the card brand from a normalized number.
+func Brand(number string) string {
+	prefix := func(n int) int {
+		if len(number) < n {
+			return -1
+		}
+		v, _ := strconv.Atoi(number[:n])
+		return v
+	}
+
+	switch {
+	case prefix(4) >= 2200 && prefix(4) <= 2204:
+		return BrandMir
+	case prefix(1) == 4:
+		return BrandVisa
+	case prefix(2) >= 51 && prefix(2) <= 55, prefix(4) >= 2221 && prefix(4) <= 2720:
+		return BrandMastercard
+	case prefix(2) == 34 || prefix(2) == 37:
+		return BrandAmex
+	case prefix(2) == 62:
+		return BrandUnionPay
+	case prefix(2) == 50, prefix(2) >= 56 && prefix(2) <= 69:
+		return BrandMaestro
+	default:
+		return BrandUnknown
+	}
+}
+
+// Last4 returns the last four digits of a normalized number.
+func Last4(number string) string {
+	if len(number) < 4 {
+		return number
+	}
+	return number[len(number)-4:]
+}
+
+// Mask renders a card for display using only its last four digits.
+func Mask(last4 string) string {
+	if last4 == "" {
+		return ""
+	}
+	return "**** **** **** " + last4
+}
+
+// ParseExpiry parses an expiry date in MM/YY or MM/YYYY form and rejects cards that
+// expired before now. Cards are valid through the last day of their expiry month.
+func ParseExpiry(expiry string, now time.Time) (month, year int, err error) {
+	m, y, ok := strings.Cut(strings.TrimSpace(expiry), "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: expected MM/YY", ErrInvalidExpiry)
+	}
+	month, err = strconv.Atoi(strings.TrimSpace(m))
+	if err != nil || month < 1 || month > 12 {
+		return 0, 0, fmt.Errorf("%w: bad month", ErrInvalidExpiry)
+	}
+	y = strings.TrimSpace(y)
+	year, err = strconv.Atoi(y)
+	if err != nil || (len(y) != 2 && len(y) != 4) {
+		return 0, 0, fmt.Errorf("%w: bad year", ErrInvalidExpiry)
+	}
+	if len(y) == 2 {
+		year += 2000
+	}
+
+	if time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC).Before(now) {
+		return 0, 0, fmt.Errorf("%w: card has expired", ErrInvalidExpiry)
+	}
+	return month, year, nil
+}
@@ -1,6 +1,58 @@
 package entities
 
-import "github.com/google/uuid"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channel verification states
+const (
+	ChannelPending  = "pending"
+	ChannelVerified = "verified"
+)
+
+// ChannelPermission is something that must hold in Telegram before a channel can be verified.
+type ChannelPermission string
+
+const (
+	// PermissionOwnerAdmin: the user who added the channel is its creator or an administrator.
+	// It can only be checked once the bot is an administrator.
+	PermissionOwnerAdmin ChannelPermission = "owner_is_admin"
+	// PermissionBotAdmin: the bot is an administrator of the channel.
+	PermissionBotAdmin ChannelPermission = "bot_is_admin"
+	// PermissionBotInviteUsers: the bot may create invite links for subscribers.
+	PermissionBotInviteUsers ChannelPermission = "bot_can_invite_users"
+	// PermissionBotRestrictMembers: the bot may remove subscribers whose access ended.
+	PermissionBotRestrictMembers ChannelPermission = "bot_can_restrict_members"
+)
+
+// Subject returns who the permission is about: "owner" or "bot".
+func (p ChannelPermission) Subject() string {
+	if p == PermissionOwnerAdmin {
+		return "owner"
+	}
+	return "bot"
+}
+
+// Description explains to the creator what to change in Telegram.
+func (p ChannelPermission) Description() string {
+	switch p {
+	case PermissionOwnerAdmin:
+		return "You must be the owner or an administrator of the channel"
+	case PermissionBotAdmin:
+		return "Add the bot to the channel as an administrator"
+	case PermissionBotInviteUsers:
+		return "Allow the bot to invite users via link"
+	case PermissionBotRestrictMembers:
+		return "Allow the bot to ban users"
+	default:
+		return string(p)
+	}
+}
 
 // Channel represents a channel entity.
 type Channel struct {
@@ -8,5 +60,46 @@ type Channel struct {
 	UserID          int64
 	ChannelTitle    string
 	ChannelUsername string
-	IsVerified      bool
+	// TelegramChatID is the channel's ID in Telegram, known once the bot has been added to it;
+	// private channels have no username and are only reachable by it
+	TelegramChatID *int64
+	IsVerified     bool
+	// ReferralShareBPS is the share of the creator's earnings from a referred subscriber that
+	// goes to the referrer, in basis points; 0 means the channel has no referral program
+	ReferralShareBPS int
+	// MissingPermissions is what kept the last check from verifying the channel
+	MissingPermissions []ChannelPermission
+	// VerificationAttempts counts the failed checks since the channel was added or checked on request
+	VerificationAttempts  int
+	VerificationCheckedAt *time.Time
+	// NextVerificationAt is when a pending channel is checked again; nil once retries are given up
+	NextVerificationAt *time.Time
+}
+
+// ChatID returns the identifier used to address the channel in the Bot API. The chat ID is
+// preferred because it survives username changes.
+func (c *Channel) ChatID() string {
+	if c.TelegramChatID != nil {
+		return strconv.FormatInt(*c.TelegramChatID, 10)
+	}
+	return "@" + strings.TrimPrefix(c.ChannelUsername, "@")
+}
+
+// DisplayName returns the channel's title with its @username, or only the title for a
+// private channel.
+func (c *Channel) DisplayName() string {
+	username := strings.TrimPrefix(c.ChannelUsername, "@")
+	if username == "" {
+		return c.ChannelTitle
+	}
+	return fmt.Sprintf("%s (@%s)", c.ChannelTitle, username)
+}
+
+// VerificationStatus returns ChannelVerified once the owner and the bot have been confirmed
+// to have the rights the channel needs, and ChannelPending until then.
+func (c *Channel) VerificationStatus() string {
+	if c.IsVerified {
+		return ChannelVerified
+	}
+	return ChannelPending
 }
@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+// LedgerAccountType identifies what a ledger account tracks.
+type LedgerAccountType string
+
+const (
+	// LedgerCreatorBalance is what the platform owes a creator. Owned by the creator.
+	LedgerCreatorBalance LedgerAccountType = "creator_balance"
+	// LedgerPlatformRevenue collects the platform commission.
+	LedgerPlatformRevenue LedgerAccountType = "platform_revenue"
+	// LedgerPaymentsClearing is the counterpart of money received from subscribers.
+	LedgerPaymentsClearing LedgerAccountType = "payments_clearing"
+	// LedgerPayoutsClearing holds payouts that were taken from creator balances until the
+	// gateway pays them out.
+	LedgerPayoutsClearing LedgerAccountType = "payouts_clearing"
+	// LedgerOpeningBalance is the counterpart of balances carried over from before the ledger existed.
+	LedgerOpeningBalance LedgerAccountType = "opening_balance"
+)
+
+// Journal entry kinds
+const (
+	JournalSubscriptionPayment = "subscription_payment"
+	JournalPayout              = "payout"
+	JournalPayoutReversal      = "payout_reversal"
+	JournalPayoutSettlement    = "payout_settlement"
+	JournalRefund              = "refund"
+)
+
+// LedgerAccount is a single balance in the ledger. Platform accounts have no owner.
+type LedgerAccount struct {
+	ID        uuid.UUID
+	Type      LedgerAccountType
+	OwnerID   *int64
+	Currency  string
+	CreatedAt time.Time
+}
+
+// Posting moves an amount into (positive) or out of (negative) an account.
+type Posting struct {
+	ID        uuid.UUID
+	EntryID   uuid.UUID
+	AccountID uuid.UUID
+	Amount    money.Money
+}
+
+// JournalEntry groups postings that describe one business event. The postings
+// of an entry always sum to zero in every currency.
+type JournalEntry struct {
+	ID          uuid.UUID
+	Kind        string
+	Reference   string
+	Description string
+	CreatedAt   time.Time
+	Postings    []*Posting
+}
+
+// Validate checks that the entry has postings and that they balance.
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return errors.New("journal entry needs at least two postings")
+	}
+
+	totals := make(map[string]int64)
+	for _, p := range e.Postings {
+		totals[p.Amount.Currency] += p.Amount.Amount
+	}
+	for currency, total := range totals {
+		if total != 0 {
+			return fmt.Errorf("journal entry %s/%s does not balance: %d %s", e.Kind, e.Reference, total, currency)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MembershipStatus is the lifecycle state of a membership.
+type MembershipStatus string
+
+const (
+	// MembershipTrialing grants free access until the trial ends, when it is either paid for or expired.
+	MembershipTrialing MembershipStatus = "trialing"
+	// MembershipActive renews at the end of the current period: the subscriber is invoiced
+	// before it ends and paying extends the membership by another period.
+	MembershipActive MembershipStatus = "active"
+	// MembershipCancelled keeps access until the end of the current period but does not renew.
+	MembershipCancelled MembershipStatus = "cancelled"
+	// MembershipExpired no longer grants access.
+	MembershipExpired MembershipStatus = "expired"
+)
+
+// Membership records that a subscriber has access to a channel through a
+// subscription tier until the end of the current period.
+type Membership struct {
+	ID             uuid.UUID
+	SubscriberID   int64
+	SubscriptionID uuid.UUID
+	// PriceID is the price the current period was paid at; nil for memberships created before prices existed
+	PriceID *uuid.UUID
+	// PaymentID is the payment that funded the current period; nil during a free trial and for
+	// memberships paid before payments were recorded on them
+	PaymentID *uuid.UUID
+	ChannelID uuid.UUID
+	Status    MembershipStatus
+	StartedAt time.Time
+	// CurrentPeriodEnd is nil for lifetime access
+	CurrentPeriodEnd *time.Time
+	CancelledAt      *time.Time
+	// InviteLink is the most recent one-time link issued to the subscriber
+	InviteLink string
+	// TrialEndsAt is set if the membership started with a free trial and is kept once it is paid for
+	TrialEndsAt *time.Time
+	// TrialReminderSentAt is when the subscriber was reminded that the trial is ending
+	TrialReminderSentAt *time.Time
+	// RenewalInvoicedAt is when the subscriber was invoiced for the next period; cleared when it is paid for
+	RenewalInvoicedAt *time.Time
+}
+
+// IsTrialPeriod reports whether the current period is the free trial.
+func (m *Membership) IsTrialPeriod() bool {
+	return m.TrialEndsAt != nil && m.CurrentPeriodEnd != nil && m.CurrentPeriodEnd.Equal(*m.TrialEndsAt)
+}
+
+// HasAccess reports whether the membership grants access at the given time.
+func (m *Membership) HasAccess(now time.Time) bool {
+	if m.Status == MembershipExpired {
+		return false
+	}
+	return m.CurrentPeriodEnd == nil || now.Before(*m.CurrentPeriodEnd)
+}
@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PartnerRequest is the audit record of a callback an integration partner made, or that
+// someone made claiming to be one.
+type PartnerRequest struct {
+	ID uuid.UUID
+	// Partner is the partner the request claimed to come from
+	Partner  string
+	Endpoint string
+	RemoteIP string
+	// Signature is the request's signature as sent; accepted signatures are never accepted again
+	Signature string
+	Accepted  bool
+	// Reason is why the request was rejected
+	Reason    string
+	CreatedAt time.Time
+}
@@ -2,14 +2,64 @@ package entities
 
 import (
 	"time"
+	"tribute-back/internal/domain/money"
 
 	"github.com/google/uuid"
 )
 
+// PaymentStatus is the lifecycle state of a payment.
+type PaymentStatus string
+
+const (
+	// PaymentPending has been created but not yet confirmed by the provider.
+	PaymentPending PaymentStatus = "pending"
+	// PaymentSucceeded has been charged and credited to the creator.
+	PaymentSucceeded PaymentStatus = "succeeded"
+	// PaymentFailed was declined or abandoned; no money moved.
+	PaymentFailed PaymentStatus = "failed"
+	// PaymentRefunded was returned to the payer in full after succeeding. Partially
+	// refunded payments stay succeeded.
+	PaymentRefunded PaymentStatus = "refunded"
+)
+
 // Payment represents a payment entity.
 type Payment struct {
-	ID          uuid.UUID
-	UserID      int64
-	Description string
-	CreatedDate time.Time
+	ID uuid.UUID
+	// PayerID is the subscriber who pays
+	PayerID int64
+	// CreatorID is the user being paid; nil for payments recorded before it was tracked
+	CreatorID *int64
+	// SubscriptionID is the subscription tier being paid for; nil if unknown or deleted
+	SubscriptionID *uuid.UUID
+	// PriceID is the tier price being paid; nil for payments recorded before prices existed
+	PriceID *uuid.UUID
+	// Amount is what the payer is charged, after any discount
+	Amount money.Money
+	// PromoCodeID is the promo code applied to the payment, if any
+	PromoCodeID *uuid.UUID
+	// Discount is how much the promo code took off the price, in the payment's currency
+	Discount money.Money
+	// Refunded is how much of the amount has been returned to the payer so far
+	Refunded money.Money
+	// ReferralID is the referral the payment shares revenue with, if the payer was referred
+	ReferralID *uuid.UUID
+	// ReferralShare is what the payment credited to the referrer out of the creator's earnings
+	ReferralShare money.Money
+	Status        PaymentStatus
+	// Provider is the payment provider that handles the payment
+	Provider         string
+	ProviderChargeID string
+	FailureReason    string
+	Description      string
+	CreatedDate      time.Time
+	UpdatedAt        time.Time
+}
+
+// Refundable returns how much of the payment can still be returned to the payer.
+func (p *Payment) Refundable() money.Money {
+	remaining, err := p.Amount.Sub(p.Refunded)
+	if err != nil || p.Status != PaymentSucceeded {
+		return money.Zero(p.Amount.Currency)
+	}
+	return remaining
 }
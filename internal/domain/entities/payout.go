@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+// PayoutStatus is the lifecycle state of a payout.
+type PayoutStatus string
+
+const (
+	// PayoutRequested is reserved in the ledger and waiting to be submitted to the gateway.
+	PayoutRequested PayoutStatus = "requested"
+	// PayoutProcessing has been accepted by the gateway.
+	PayoutProcessing PayoutStatus = "processing"
+	// PayoutPaid has reached the creator.
+	PayoutPaid PayoutStatus = "paid"
+	// PayoutFailed was rejected or given up on; the reserved amount is back on the creator's balance.
+	PayoutFailed PayoutStatus = "failed"
+)
+
+// Payout is a creator's request to withdraw part of their balance.
+type Payout struct {
+	ID              uuid.UUID
+	UserID          int64
+	Amount          money.Money
+	Status          PayoutStatus
+	GatewayPayoutID string
+	FailureReason   string
+	// Attempts counts submissions to the gateway that failed temporarily and, once it
+	// accepted the payout, status checks that failed
+	Attempts    int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
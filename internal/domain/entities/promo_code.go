@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"errors"
+	"time"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+// PromoDiscountType is how a promo code reduces the price.
+type PromoDiscountType string
+
+const (
+	// PromoPercent takes a percentage off the price.
+	PromoPercent PromoDiscountType = "percent"
+	// PromoFixed takes a fixed amount off prices in the same currency.
+	PromoFixed PromoDiscountType = "fixed"
+)
+
+// ErrDiscountNotApplicable is returned when a promo code can't reduce a price, e.g.
+// because the currencies differ or nothing would be left to pay.
+var ErrDiscountNotApplicable = errors.New("discount does not apply to this price")
+
+// PromoCode is a discount a creator offers on the tiers of one of their channels.
+type PromoCode struct {
+	ID        uuid.UUID
+	CreatorID int64
+	ChannelID uuid.UUID
+	// SubscriptionID limits the code to one tier; nil applies it to every tier of the channel
+	SubscriptionID *uuid.UUID
+	// Code is what subscribers enter, stored in upper case
+	Code         string
+	DiscountType PromoDiscountType
+	// PercentOff is set for percent discounts, AmountOff for fixed ones
+	PercentOff int
+	AmountOff  money.Money
+	// MaxRedemptions and PerUserLimit are unlimited when zero
+	MaxRedemptions int
+	PerUserLimit   int
+	// StartsAt and EndsAt bound when the code can be used; nil leaves that side open
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	CreatedDate time.Time
+	ArchivedAt  *time.Time
+	// Redemptions counts payments that used the code and went through; filled in when loaded
+	Redemptions int
+}
+
+// IsActive reports whether the code can be used at the given time.
+func (p *PromoCode) IsActive(now time.Time) bool {
+	if p.ArchivedAt != nil {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	return p.EndsAt == nil || now.Before(*p.EndsAt)
+}
+
+// AppliesTo reports whether the code can be used for the tier.
+func (p *PromoCode) AppliesTo(tier *Subscription) bool {
+	if tier.ChannelID != p.ChannelID {
+		return false
+	}
+	return p.SubscriptionID == nil || *p.SubscriptionID == tier.ID
+}
+
+// Discount returns how much the code takes off the price. Percent discounts are
+// rounded half up to the minor unit. Something must always be left to pay.
+func (p *PromoCode) Discount(price money.Money) (money.Money, error) {
+	var discount money.Money
+	switch p.DiscountType {
+	case PromoPercent:
+		discount = money.New((price.Amount*int64(p.PercentOff)+50)/100, price.Currency)
+	case PromoFixed:
+		if !p.AmountOff.SameCurrency(price) {
+			return money.Money{}, ErrDiscountNotApplicable
+		}
+		discount = p.AmountOff
+	default:
+		return money.Money{}, ErrDiscountNotApplicable
+	}
+	if discount.Amount >= price.Amount {
+		return money.Money{}, ErrDiscountNotApplicable
+	}
+	return discount, nil
+}
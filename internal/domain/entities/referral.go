@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+// Referral attributes a subscriber of a channel to the referrer whose link brought them.
+// It is made at the subscriber's first payment to the channel, and the referrer then gets
+// a share of every payment the subscriber makes to it.
+type Referral struct {
+	ID           uuid.UUID
+	ChannelID    uuid.UUID
+	SubscriberID int64
+	ReferrerID   int64
+	// LinkID is the referral link the subscriber last opened before paying
+	LinkID uuid.UUID
+	// ShareBPS is the channel's referral share when the subscriber was referred; later
+	// changes to the channel's share don't affect it
+	ShareBPS int
+	// PaymentID is the first payment of the subscriber, which made the referral
+	PaymentID uuid.UUID
+	CreatedAt time.Time
+}
+
+// ReferralStats sums up what a referral link brought in.
+type ReferralStats struct {
+	// Conversions counts the subscribers referred through the link
+	Conversions int
+	// Earned is what the link's referrals credited to the referrer, per currency, less refunds
+	Earned []money.Money
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"time"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+// RefundStatus is the lifecycle state of a refund.
+type RefundStatus string
+
+const (
+	// RefundPending has been sent to the payment provider, which hasn't answered yet.
+	RefundPending RefundStatus = "pending"
+	// RefundSucceeded has been returned to the payer and reversed in the ledger.
+	RefundSucceeded RefundStatus = "succeeded"
+	// RefundFailed was rejected by the provider; retrying with the same key tries again.
+	RefundFailed RefundStatus = "failed"
+)
+
+// RefundSource is who initiated a refund.
+type RefundSource string
+
+const (
+	// RefundByCreator was issued by the creator who received the payment.
+	RefundByCreator RefundSource = "creator"
+	// RefundByAdmin was issued from the admin chat.
+	RefundByAdmin RefundSource = "admin"
+	// RefundChargeback was reported by the payment provider, e.g. a disputed card payment.
+	RefundChargeback RefundSource = "chargeback"
+)
+
+// Refund is money returned to the payer of a succeeded payment. A payment can be
+// refunded in several parts, up to its amount.
+type Refund struct {
+	ID        uuid.UUID
+	PaymentID uuid.UUID
+	Source    RefundSource
+	// RequestedBy is the creator or admin who issued the refund; nil for chargebacks
+	RequestedBy *int64
+	// IdempotencyKey identifies the request that created the refund; retries with the same key return it
+	IdempotencyKey string
+	// Amount is in the payment's currency
+	Amount money.Money
+	Status RefundStatus
+	Reason string
+	// RevokeAccess ends the payer's membership for the paid tier
+	RevokeAccess     bool
+	ProviderRefundID string
+	FailureReason    string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
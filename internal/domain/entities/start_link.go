@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StartLink is a short link (t.me/<bot>?startapp=<code>) that opens the Mini App on the offer
+// of a creator's channel. Creators make links for their own campaigns; referral links are
+// made by referrers, who earn a share of what the subscribers they bring pay.
+type StartLink struct {
+	ID   uuid.UUID
+	Code string
+	// CreatorID is the owner of the channel, also for referral links
+	CreatorID int64
+	// ReferrerID is the user a referral link belongs to; nil for the creator's own links
+	ReferrerID *int64
+	ChannelID  uuid.UUID
+	// PriceID preselects one of the channel's prices on the offer; nil leaves the choice to the subscriber
+	PriceID *uuid.UUID
+	// Label tells the creator's links apart, e.g. by where they were posted
+	Label      string
+	CreatedAt  time.Time
+	ArchivedAt *time.Time
+	// Clicks counts how often the link opened the Mini App and Visitors for how many different users
+	Clicks   int
+	Visitors int
+}
+
+// OwnerID returns the user who made the link and may delete it.
+func (l *StartLink) OwnerID() int64 {
+	if l.ReferrerID != nil {
+		return *l.ReferrerID
+	}
+	return l.CreatorID
+}
+
+// StartLinkClick records that a user opened the Mini App through a start link.
+type StartLinkClick struct {
+	ID     uuid.UUID
+	LinkID uuid.UUID
+	UserID int64
+	// ChatType and ChatInstance describe the chat the link was opened from, if Telegram reports it
+	ChatType     string
+	ChatInstance string
+	// AuthDate is the auth_date of the initData, so that one launch of the Mini App is counted once
+	AuthDate  time.Time
+	CreatedAt time.Time
+}
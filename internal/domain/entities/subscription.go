@@ -6,7 +6,8 @@ import (
 	"github.com/google/uuid"
 )
 
-// Subscription represents a subscription entity.
+// Subscription represents a subscription tier of a channel. A channel can offer
+// several tiers, each sold at one or more prices.
 type Subscription struct {
 	ID              uuid.UUID
 	ChannelID       uuid.UUID
@@ -15,6 +16,19 @@ type Subscription struct {
 	Title           string
 	Description     string
 	ButtonText      string
-	Price           float64
-	CreatedDate     time.Time
+	// SortOrder positions the tier among the channel's tiers, lowest first
+	SortOrder   int
+	CreatedDate time.Time
+	// ArchivedAt is set once the creator removes the tier; existing memberships keep working
+	ArchivedAt *time.Time
+	// Prices are not stored with the tier and are populated in the service layer
+	Prices []*TierPrice
+}
+
+// DefaultPrice returns the first price the tier is offered at, or nil if it has none.
+func (s *Subscription) DefaultPrice() *TierPrice {
+	if len(s.Prices) == 0 {
+		return nil
+	}
+	return s.Prices[0]
 }
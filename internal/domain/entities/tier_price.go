@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+	"tribute-back/internal/domain/money"
+
+	"github.com/google/uuid"
+)
+
+// BillingInterval is how long one payment for a price grants access.
+type BillingInterval string
+
+const (
+	IntervalMonth    BillingInterval = "month"
+	IntervalQuarter  BillingInterval = "quarter"
+	IntervalYear     BillingInterval = "year"
+	IntervalLifetime BillingInterval = "lifetime"
+)
+
+// IsValid reports whether the interval is one of the supported billing intervals.
+func (i BillingInterval) IsValid() bool {
+	switch i {
+	case IntervalMonth, IntervalQuarter, IntervalYear, IntervalLifetime:
+		return true
+	}
+	return false
+}
+
+// PeriodEnd returns the end of a billing period that starts at from, or nil if
+// the interval grants access forever.
+func (i BillingInterval) PeriodEnd(from time.Time) *time.Time {
+	var end time.Time
+	switch i {
+	case IntervalLifetime:
+		return nil
+	case IntervalQuarter:
+		end = from.AddDate(0, 3, 0)
+	case IntervalYear:
+		end = from.AddDate(1, 0, 0)
+	default:
+		end = from.AddDate(0, 1, 0)
+	}
+	return &end
+}
+
+// TierPrice is one way to pay for a subscription tier.
+type TierPrice struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	Interval       BillingInterval
+	Price          money.Money
+	// TrialDays is how many days new subscribers get before they are charged
+	TrialDays int
+	// SortOrder positions the price among the tier's prices, lowest first
+	SortOrder   int
+	CreatedDate time.Time
+	// ArchivedAt is set once the creator removes the price; it can no longer be bought
+	ArchivedAt *time.Time
+}
@@ -1,16 +1,30 @@
 package entities
 
 import (
+	"tribute-back/internal/domain/money"
+
 	"github.com/google/uuid"
 )
 
 // User represents a user in the system.
 type User struct {
 	ID             int64
-	Earned         float64
+	Earned         money.Money
 	IsVerified     bool
 	Subscriptions  []uuid.UUID // Assuming this holds IDs of subscriptions
 	IsSubPublished bool
 	IsOnboarded    bool
-	CardNumber     string
+	// PayoutCard is nil until the user sets up payouts
+	PayoutCard *PayoutCard
+}
+
+// PayoutCard describes the card a user is paid out to. The card number itself is only
+// kept encrypted in the vault, under Token.
+type PayoutCard struct {
+	Token string
+	Last4 string
+	Brand string
+	// ExpMonth and ExpYear are zero for cards saved before the expiry was collected
+	ExpMonth int
+	ExpYear  int
 }
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// VaultRecord is an encrypted secret kept by the vault. The secret is encrypted with
+// its own data key, which is in turn encrypted ("wrapped") with the key named by KeyID.
+type VaultRecord struct {
+	Token      string
+	KeyID      string
+	WrappedKey []byte
+	Ciphertext []byte
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
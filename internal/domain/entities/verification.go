@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationStatus is the lifecycle state of an identity verification request.
+type VerificationStatus string
+
+const (
+	// VerificationSubmitted has its documents stored and is waiting to reach the reviewers.
+	VerificationSubmitted VerificationStatus = "submitted"
+	// VerificationDelivering is being sent to the reviewers by one delivery attempt.
+	VerificationDelivering VerificationStatus = "delivering"
+	// VerificationInReview has been sent to the reviewers.
+	VerificationInReview VerificationStatus = "in_review"
+	// VerificationApproved verified the user.
+	VerificationApproved VerificationStatus = "approved"
+	// VerificationRejected was turned down; Reason says why.
+	VerificationRejected VerificationStatus = "rejected"
+	// VerificationResubmit was turned down because the user has to upload new documents.
+	VerificationResubmit VerificationStatus = "resubmit"
+)
+
+// VerificationRequest is a user's request to verify their identity with a photo and a passport scan.
+type VerificationRequest struct {
+	ID     uuid.UUID
+	UserID int64
+	Status VerificationStatus
+	// PhotoKey and PassportKey locate the documents in the document store
+	PhotoKey    string
+	PassportKey string
+	// Reviewer is who decided the request, e.g. "admin:<telegram id>" or "partner:<id>"
+	Reviewer    string
+	Reason      string
+	SubmittedAt time.Time
+	UpdatedAt   time.Time
+	ReviewedAt  *time.Time
+}
+
+// IsOpen reports whether the request is still waiting for a decision.
+func (r *VerificationRequest) IsOpen() bool {
+	return r.Status == VerificationSubmitted || r.Status == VerificationDelivering || r.Status == VerificationInReview
+}
+
+// VerificationEvent is one change of status of a verification request.
+type VerificationEvent struct {
+	ID        uuid.UUID
+	RequestID uuid.UUID
+	Status    VerificationStatus
+	Reviewer  string
+	Reason    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,152 @@
+// Package money implements amounts of money as integer minor units with an explicit currency.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCurrencyMismatch is returned when an operation combines amounts in different currencies.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrUnsupportedCurrency is returned for currency codes we don't know the minor units of.
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrInvalidAmount is returned when a decimal amount cannot be parsed.
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// minorUnits maps supported ISO 4217 codes to the number of digits after the decimal point.
+// XTR is Telegram Stars, which are indivisible.
+var minorUnits = map[string]int{
+	"RUB": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"KZT": 2,
+	"UAH": 2,
+	"JPY": 0,
+	"XTR": 0,
+}
+
+// Money is an amount in the minor units of its currency, e.g. kopecks for RUB.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// New creates an amount of minor units in the given currency.
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero returns a zero amount in the given currency.
+func Zero(currency string) Money {
+	return Money{Currency: currency}
+}
+
+// ValidateCurrency checks that the currency code is supported.
+func ValidateCurrency(currency string) error {
+	if _, ok := minorUnits[currency]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+	return nil
+}
+
+// Parse converts a decimal string such as "199.90" into Money. More fractional
+// digits than the currency has are rejected rather than rounded.
+func Parse(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	digits, ok := minorUnits[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+
+	amount = strings.TrimSpace(amount)
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+
+	whole, fraction, hasPoint := strings.Cut(amount, ".")
+	if whole == "" || (hasPoint && fraction == "") || len(fraction) > digits || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	fraction += strings.Repeat("0", digits-len(fraction))
+
+	minor, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal formats the amount in major units, e.g. "199.90".
+func (m Money) Decimal() string {
+	digits := minorUnits[m.Currency]
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if digits == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+
+	scale := int64(math.Pow10(digits))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, digits, amount%scale)
+}
+
+// String formats the amount with its currency, e.g. "199.90 RUB".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// SameCurrency reports whether both amounts are in the same currency.
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency == other.Currency
+}
+
+// Add returns m + other.
+func (m Money) Add(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m - other.
+func (m Money) Sub(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Equal reports whether both amounts and currencies are equal.
+func (m Money) Equal(other Money) bool {
+	return m.Amount == other.Amount && m.Currency == other.Currency
+}
+
+// IsPositive reports whether the amount is greater than zero.
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
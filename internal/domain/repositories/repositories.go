@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"time"
 	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
 
 	"github.com/google/uuid"
 )
@@ -11,6 +13,9 @@ type UserRepository interface {
 	FindByID(id int64) (*entities.User, error)
 	Update(user *entities.User) error
 	Create(user *entities.User) error
+	// FindLegacyCardNumbers returns up to limit plaintext card numbers saved before the vault existed, by user ID
+	FindLegacyCardNumbers(limit int) (map[int64]string, error)
+	ClearLegacyCardNumber(userID int64) error
 	// Add other necessary methods
 }
 
@@ -18,6 +23,11 @@ type UserRepository interface {
 type ChannelRepository interface {
 	FindByUserID(userID int64) ([]*entities.Channel, error)
 	FindByID(id uuid.UUID) (*entities.Channel, error)
+	// FindByUsername matches the username with or without the @ and ignoring case, preferring a verified channel
+	FindByUsername(username string) (*entities.Channel, error)
+	FindByTelegramChatID(chatID int64) (*entities.Channel, error)
+	// FindDueForVerification returns the pending channels whose next verification check is due
+	FindDueForVerification(now time.Time) ([]*entities.Channel, error)
 	Create(channel *entities.Channel) error
 	Update(channel *entities.Channel) error
 	Delete(id uuid.UUID) error
@@ -27,16 +37,167 @@ type ChannelRepository interface {
 // SubscriptionRepository defines the interface for subscription data operations
 type SubscriptionRepository interface {
 	FindByID(id uuid.UUID) (*entities.Subscription, error)
+	// FindByUserID and FindByChannelID skip archived tiers and return the rest in sort order
 	FindByUserID(userID int64) ([]*entities.Subscription, error)
-	FindByChannelID(channelID uuid.UUID) (*entities.Subscription, error)
+	FindByChannelID(channelID uuid.UUID) ([]*entities.Subscription, error)
 	Create(subscription *entities.Subscription) error
 	Update(subscription *entities.Subscription) error
 	// Add other necessary methods
 }
 
+// TierPriceRepository defines the interface for subscription tier price data operations
+type TierPriceRepository interface {
+	FindByID(id uuid.UUID) (*entities.TierPrice, error)
+	// FindBySubscriptionID skips archived prices and returns the rest in sort order
+	FindBySubscriptionID(subscriptionID uuid.UUID) ([]*entities.TierPrice, error)
+	Create(price *entities.TierPrice) error
+	Update(price *entities.TierPrice) error
+}
+
 // PaymentRepository defines the interface for payment data operations
 type PaymentRepository interface {
-	FindByUserID(userID int64) ([]*entities.Payment, error)
+	FindByID(id uuid.UUID) (*entities.Payment, error)
+	FindByPayerID(payerID int64) ([]*entities.Payment, error)
+	// FindByCreatorID returns the succeeded and refunded payments to the creator, newest first
+	FindByCreatorID(creatorID int64) ([]*entities.Payment, error)
+	// FindByPromoCode returns the payer's payments that used the promo code, newest first
+	FindByPromoCode(promoCodeID uuid.UUID, payerID int64) ([]*entities.Payment, error)
+	// HasPaidForChannel reports whether the payer has a succeeded or refunded payment other
+	// than exceptID for any tier of the channel
+	HasPaidForChannel(payerID int64, channelID uuid.UUID, exceptID uuid.UUID) (bool, error)
 	Create(payment *entities.Payment) error
+	Update(payment *entities.Payment) error
 	// Add other necessary methods
 }
+
+// RefundRepository defines the interface for refund data operations
+type RefundRepository interface {
+	FindByPaymentID(paymentID uuid.UUID) ([]*entities.Refund, error)
+	FindByIdempotencyKey(paymentID uuid.UUID, key string) (*entities.Refund, error)
+	Create(refund *entities.Refund) error
+	Update(refund *entities.Refund) error
+}
+
+// PromoCodeRepository defines the interface for promo code data operations.
+// Loaded codes have their redemptions counted.
+type PromoCodeRepository interface {
+	FindByID(id uuid.UUID) (*entities.PromoCode, error)
+	// FindByCode returns the channel's live (not archived) code, matched exactly
+	FindByCode(channelID uuid.UUID, code string) (*entities.PromoCode, error)
+	// FindByChannelID returns the channel's live codes, newest first
+	FindByChannelID(channelID uuid.UUID) ([]*entities.PromoCode, error)
+	Create(promoCode *entities.PromoCode) error
+	Update(promoCode *entities.PromoCode) error
+}
+
+// StartLinkRepository defines the interface for start link data operations.
+// Loaded links have their clicks counted.
+type StartLinkRepository interface {
+	FindByID(id uuid.UUID) (*entities.StartLink, error)
+	// FindByCode also returns archived links, whose codes are never reused
+	FindByCode(code string) (*entities.StartLink, error)
+	// FindByChannelID returns the channel's live links, newest first, without referral links
+	FindByChannelID(channelID uuid.UUID) ([]*entities.StartLink, error)
+	// FindByReferrerID returns the referrer's live referral links, newest first
+	FindByReferrerID(referrerID int64) ([]*entities.StartLink, error)
+	// FindReferralLink returns the referrer's live referral link to the channel
+	FindReferralLink(channelID uuid.UUID, referrerID int64) (*entities.StartLink, error)
+	// FindLastReferralClick returns the referral link to the channel the user opened last
+	// since the given time, archived or not
+	FindLastReferralClick(channelID uuid.UUID, userID int64, since time.Time) (*entities.StartLink, error)
+	Create(link *entities.StartLink) error
+	Update(link *entities.StartLink) error
+	// RecordClick stores a click unless the same launch of the Mini App was already recorded
+	RecordClick(click *entities.StartLinkClick) error
+}
+
+// ReferralRepository defines the interface for referral data operations
+type ReferralRepository interface {
+	FindByID(id uuid.UUID) (*entities.Referral, error)
+	// FindBySubscriber returns who referred the subscriber to the channel, if anyone
+	FindBySubscriber(channelID uuid.UUID, subscriberID int64) (*entities.Referral, error)
+	Create(referral *entities.Referral) error
+	// StatsByLinkID counts the referrals made through a link and sums what their payments
+	// credited to the referrer, less the part taken back by refunds
+	StatsByLinkID(linkID uuid.UUID) (*entities.ReferralStats, error)
+}
+
+// VerificationRepository defines the interface for identity verification requests and their history
+type VerificationRepository interface {
+	FindByID(id uuid.UUID) (*entities.VerificationRequest, error)
+	// FindLatestByUserID returns the user's most recently submitted request, if any
+	FindLatestByUserID(userID int64) (*entities.VerificationRequest, error)
+	// FindDueForDelivery returns the submitted requests and those whose delivery started before
+	// staleBefore and never finished, oldest first
+	FindDueForDelivery(staleBefore time.Time) ([]*entities.VerificationRequest, error)
+	// Create stores the request with its first event
+	Create(request *entities.VerificationRequest) error
+	// ClaimForDelivery saves the request as delivering if it is still submitted or its delivery
+	// started before staleBefore, and records it as an event; reports whether it was claimed
+	ClaimForDelivery(request *entities.VerificationRequest, staleBefore time.Time) (bool, error)
+	// Transition saves the request's new status if it is still in status from, and records it
+	// as an event; reports whether it was saved
+	Transition(request *entities.VerificationRequest, from entities.VerificationStatus) (bool, error)
+	// FindEvents returns the status history of a request, oldest first
+	FindEvents(requestID uuid.UUID) ([]*entities.VerificationEvent, error)
+}
+
+// PartnerRequestRepository defines the interface for the audit log of partner callbacks
+type PartnerRequestRepository interface {
+	Create(request *entities.PartnerRequest) error
+	// FindAcceptedBySignature returns the accepted request that was signed with the signature, if any
+	FindAcceptedBySignature(signature string) (*entities.PartnerRequest, error)
+}
+
+// MembershipRepository defines the interface for membership data operations
+type MembershipRepository interface {
+	FindByID(id uuid.UUID) (*entities.Membership, error)
+	FindBySubscriberID(subscriberID int64) ([]*entities.Membership, error)
+	// FindCurrent returns the subscriber's non-expired membership for a tier, if any
+	FindCurrent(subscriberID int64, subscriptionID uuid.UUID) (*entities.Membership, error)
+	// FindDueForExpiry returns non-expired memberships whose period ended at or before now
+	FindDueForExpiry(now time.Time) ([]*entities.Membership, error)
+	// FindTrialsDueForReminder returns trialing memberships ending at or before the given
+	// time whose subscribers haven't been reminded yet
+	FindTrialsDueForReminder(before time.Time) ([]*entities.Membership, error)
+	// FindDueForRenewal returns active memberships ending at or before the given time whose
+	// subscribers haven't been invoiced for the next period yet
+	FindDueForRenewal(before time.Time) ([]*entities.Membership, error)
+	// HasTrial reports whether the subscriber ever had a free trial in the channel
+	HasTrial(subscriberID int64, channelID uuid.UUID) (bool, error)
+	Create(membership *entities.Membership) error
+	Update(membership *entities.Membership) error
+}
+
+// LedgerRepository defines the interface for ledger data operations
+type LedgerRepository interface {
+	// FindOrCreateAccount returns the account of the given type, owner and currency, creating it on first use
+	FindOrCreateAccount(accountType entities.LedgerAccountType, ownerID *int64, currency string) (*entities.LedgerAccount, error)
+	// PostEntry stores an entry and its postings atomically
+	PostEntry(entry *entities.JournalEntry) error
+	FindEntryByReference(kind, reference string) (*entities.JournalEntry, error)
+	Balance(accountType entities.LedgerAccountType, ownerID *int64, currency string) (money.Money, error)
+	// FindUnbalancedEntries returns the IDs of entries whose postings don't sum to zero
+	FindUnbalancedEntries() ([]uuid.UUID, error)
+}
+
+// PayoutRepository defines the interface for payout data operations
+type PayoutRepository interface {
+	FindByID(id uuid.UUID) (*entities.Payout, error)
+	FindByUserID(userID int64) ([]*entities.Payout, error)
+	// FindByStatus returns payouts in the given status, oldest first
+	FindByStatus(status entities.PayoutStatus) ([]*entities.Payout, error)
+	Create(payout *entities.Payout) error
+	Update(payout *entities.Payout) error
+}
+
+// VaultRepository defines the interface for encrypted secret storage
+type VaultRepository interface {
+	FindByToken(token string) (*entities.VaultRecord, error)
+	// FindNotWrappedWith returns up to limit records whose data key is wrapped with a key other than keyID
+	FindNotWrappedWith(keyID string, limit int) ([]*entities.VaultRecord, error)
+	Create(record *entities.VaultRecord) error
+	// UpdateKey stores a data key re-wrapped with another key
+	UpdateKey(record *entities.VaultRecord) error
+	Delete(token string) error
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NonceStore remembers the nonces of accepted requests so that a captured request can't be
+// replayed while its signed timestamp is still within the allowed clock skew.
+type NonceStore interface {
+	// Claim reports whether the nonce wasn't seen before, and if so remembers it for ttl.
+	Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// RedisNonceStore keeps one expiring Redis key per nonce, so that a nonce accepted by one
+// instance of the server is rejected by every other.
+type RedisNonceStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisNonceStore creates a nonce store backed by Redis.
+func NewRedisNonceStore(client *redis.Client, prefix string) NonceStore {
+	return &RedisNonceStore{client: client, prefix: prefix}
+}
+
+func (s *RedisNonceStore) Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.prefix+nonce, 1, ttl).Result()
+}
+
+// MemoryNonceStore keeps nonces in process memory. It is used when Redis is unavailable;
+// a request can then be replayed once against every other instance.
+type MemoryNonceStore struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// NewMemoryNonceStore creates an in-memory nonce store.
+func NewMemoryNonceStore() NonceStore {
+	return &MemoryNonceStore{until: make(map[string]time.Time)}
+}
+
+func (s *MemoryNonceStore) Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if until, ok := s.until[nonce]; ok && now.Before(until) {
+		return false, nil
+	}
+	// Forget expired nonces so that the map doesn't grow with every request
+	for seen, until := range s.until {
+		if !now.Before(until) {
+			delete(s.until, seen)
+		}
+	}
+	s.until[nonce] = now.Add(ttl)
+	return true, nil
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Headers of a request signed by one of our own services.
+const (
+	// ServiceTimestampHeader carries the Unix time, in seconds, the request was signed at
+	ServiceTimestampHeader = "X-Signature-Timestamp"
+	// ServiceNonceHeader carries a unique ID of a service request, e.g. a UUID
+	ServiceNonceHeader = "X-Signature-Nonce"
+	// ServiceSignatureHeader carries the hex HMAC-SHA256 of "<timestamp>.<nonce>.<body>" for
+	// service requests and of "<timestamp>.<body>" for partner requests
+	ServiceSignatureHeader = "X-Signature"
+)
+
+// maxNonceLength bounds the nonces kept to detect replays.
+const maxNonceLength = 64
+
+var (
+	// ErrInvalidSignature is returned for service requests that are unsigned, badly signed or
+	// signed too long ago.
+	ErrInvalidSignature = errors.New("invalid request signature")
+	// ErrReplayedRequest is returned for service requests whose nonce was already accepted.
+	ErrReplayedRequest = errors.New("request was already accepted")
+)
+
+// ServiceAuthenticator verifies requests our own services, e.g. the bot process, sign with a
+// shared secret. The signed timestamp keeps a captured request from being replayed later,
+// and the signed nonce from being replayed while the timestamp is still valid.
+type ServiceAuthenticator struct {
+	secret  []byte
+	maxSkew time.Duration
+	nonces  NonceStore
+}
+
+// NewServiceAuthenticator creates an authenticator for the shared secret. Requests are
+// accepted when they were signed at most maxSkew away from now; an empty secret rejects
+// every request. nonces remembers the nonces of accepted requests; without it VerifyRequest
+// rejects every request.
+func NewServiceAuthenticator(secret string, maxSkew time.Duration, nonces NonceStore) *ServiceAuthenticator {
+	return &ServiceAuthenticator{secret: []byte(secret), maxSkew: maxSkew, nonces: nonces}
+}
+
+// Sign returns the signature of the body at the timestamp.
+func (a *ServiceAuthenticator) Sign(timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a request with the body.
+func (a *ServiceAuthenticator) Verify(timestamp, signature string, body []byte, now time.Time) error {
+	if len(a.secret) == 0 {
+		return ErrInvalidSignature
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if skew := now.Sub(time.Unix(signedAt, 0)); skew > a.maxSkew || skew < -a.maxSkew {
+		return ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(a.Sign(signedAt, body))
+	actual, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(actual, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// SignRequest returns the signature of a service request with the nonce and body at the timestamp.
+func (a *ServiceAuthenticator) SignRequest(timestamp int64, nonce string, body []byte) string {
+	return a.Sign(timestamp, append([]byte(nonce+"."), body...))
+}
+
+// VerifyRequest checks the signature, timestamp and nonce headers of a service request with
+// the body, and that the nonce wasn't accepted before. Nonces are remembered for as long as
+// a timestamp can stay within the allowed skew.
+func (a *ServiceAuthenticator) VerifyRequest(ctx context.Context, timestamp, nonce, signature string, body []byte, now time.Time) error {
+	// A nonce with a dot could shift part of the body into the nonce and still match the signature
+	if a.nonces == nil || !validNonce(nonce) {
+		return ErrInvalidSignature
+	}
+	if err := a.Verify(timestamp, signature, append([]byte(nonce+"."), body...), now); err != nil {
+		return err
+	}
+	fresh, err := a.nonces.Claim(ctx, nonce, 2*a.maxSkew)
+	if err != nil {
+		return fmt.Errorf("failed to check request nonce: %w", err)
+	}
+	if !fresh {
+		return ErrReplayedRequest
+	}
+	return nil
+}
+
+func validNonce(nonce string) bool {
+	if nonce == "" || len(nonce) > maxNonceLength {
+		return false
+	}
+	for _, r := range nonce {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// CanonicalSignature returns the signature as lowercase hex, so that the same signature
+// sent with a different letter case compares equal. Signatures that aren't hex are
+// returned unchanged; Verify rejects them.
+func CanonicalSignature(signature string) string {
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return signature
+	}
+	return hex.EncodeToString(decoded)
+}
+
+// PartnerHeader names the integration partner that signed a request.
+const PartnerHeader = "X-Partner-Id"
+
+// ErrUnknownPartner is returned for requests from partners that aren't configured.
+var ErrUnknownPartner = errors.New("unknown partner")
+
+// PartnerAuthenticator verifies requests integration partners sign like our own services
+// do, each with its own secret.
+type PartnerAuthenticator struct {
+	partners map[string]*ServiceAuthenticator
+}
+
+// NewPartnerAuthenticator creates an authenticator for the partners' secrets, keyed by
+// partner ID. Partners with an empty secret are left out.
+func NewPartnerAuthenticator(secrets map[string]string, maxSkew time.Duration) *PartnerAuthenticator {
+	partners := make(map[string]*ServiceAuthenticator, len(secrets))
+	for partner, secret := range secrets {
+		if secret != "" {
+			// Partner requests carry no nonce; AuthenticatePartnerRequest accepts each signature once
+			partners[partner] = NewServiceAuthenticator(secret, maxSkew, nil)
+		}
+	}
+	return &PartnerAuthenticator{partners: partners}
+}
+
+// Verify checks that the request was signed by the partner within the allowed clock skew.
+func (a *PartnerAuthenticator) Verify(partner, timestamp, signature string, body []byte, now time.Time) error {
+	authenticator, ok := a.partners[partner]
+	if !ok {
+		return ErrUnknownPartner
+	}
+	return authenticator.Verify(timestamp, signature, body, now)
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyRequestRejectsReplays(t *testing.T) {
+	authenticator := NewServiceAuthenticator("service-secret", 5*time.Minute, NewMemoryNonceStore())
+	now := time.Now()
+	body := []byte(`{"user_id":1}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := authenticator.SignRequest(now.Unix(), "nonce-1", body)
+
+	if err := authenticator.VerifyRequest(context.Background(), timestamp, "nonce-1", signature, body, now); err != nil {
+		t.Fatalf("VerifyRequest: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		timestamp string
+		nonce     string
+		signature string
+		want      error
+	}{
+		{name: "replayed", timestamp: timestamp, nonce: "nonce-1", signature: signature, want: ErrReplayedRequest},
+		{name: "other nonce", timestamp: timestamp, nonce: "nonce-2", signature: signature, want: ErrInvalidSignature},
+		{name: "no nonce", timestamp: timestamp, signature: authenticator.Sign(now.Unix(), append([]byte("."), body...)), want: ErrInvalidSignature},
+		// The dot would let "nonce-1" sign "1.<body>" as the body
+		{name: "dot in nonce", timestamp: timestamp, nonce: "nonce-1.1", signature: authenticator.SignRequest(now.Unix(), "nonce-1.1", body), want: ErrInvalidSignature},
+		{name: "stale", timestamp: strconv.FormatInt(now.Add(-time.Hour).Unix(), 10), nonce: "nonce-3", signature: authenticator.SignRequest(now.Add(-time.Hour).Unix(), "nonce-3", body), want: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authenticator.VerifyRequest(context.Background(), tt.timestamp, tt.nonce, tt.signature, body, now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyRequest = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// A rejected request doesn't use up its nonce
+	if err := authenticator.VerifyRequest(context.Background(), timestamp, "nonce-2", authenticator.SignRequest(now.Unix(), "nonce-2", body), body, now); err != nil {
+		t.Errorf("VerifyRequest with an unused nonce: %v", err)
+	}
+}
+
+func TestVerifyRequestWithoutNonceStore(t *testing.T) {
+	authenticator := NewServiceAuthenticator("service-secret", 5*time.Minute, nil)
+	now := time.Now()
+	signature := authenticator.SignRequest(now.Unix(), "nonce-1", nil)
+	err := authenticator.VerifyRequest(context.Background(), strconv.FormatInt(now.Unix(), 10), "nonce-1", signature, nil, now)
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyRequest = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -24,9 +24,18 @@ type InitDataUser struct {
 
 // ParsedInitData holds the structured data from the initData string.
 type ParsedInitData struct {
-	User     InitDataUser `json:"user"`
-	AuthDate int64        `json:"auth_date"`
-	Hash     string       `json:"hash"`
+	User InitDataUser `json:"user"`
+	// Receiver is the chat partner when the Mini App was opened from an attachment menu in a private chat
+	Receiver *InitDataUser `json:"receiver,omitempty"`
+	// QueryID is set when the Mini App was opened from a keyboard button or inline query
+	QueryID string `json:"query_id,omitempty"`
+	// ChatType and ChatInstance describe the chat the Mini App was opened from, if any
+	ChatType     string `json:"chat_type,omitempty"`
+	ChatInstance string `json:"chat_instance,omitempty"`
+	// StartParam is the startapp parameter of the link that opened the Mini App
+	StartParam string `json:"start_param,omitempty"`
+	AuthDate   int64  `json:"auth_date"`
+	Hash       string `json:"hash"`
 }
 
 // TelegramAuthService provides methods to validate Telegram initData.
@@ -85,6 +94,18 @@ func (s *TelegramAuthService) Validate(initData string) (*ParsedInitData, error)
 		return nil, fmt.Errorf("failed to unmarshal user data: %w", err)
 	}
 
+	if receiverJSON := q.Get("receiver"); receiverJSON != "" {
+		parsedData.Receiver = &InitDataUser{}
+		if err := json.Unmarshal([]byte(receiverJSON), parsedData.Receiver); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal receiver data: %w", err)
+		}
+	}
+	parsedData.QueryID = q.Get("query_id")
+	parsedData.ChatType = q.Get("chat_type")
+	parsedData.ChatInstance = q.Get("chat_instance")
+	parsedData.StartParam = q.Get("start_param")
+	parsedData.Hash = hash
+
 	authDate, _ := strconv.ParseInt(q.Get("auth_date"), 10, 64)
 	parsedData.AuthDate = authDate
 
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"database/sql"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgLedgerRepository struct {
+	db *sql.DB
+}
+
+func NewPgLedgerRepository(db *sql.DB) repositories.LedgerRepository {
+	return &PgLedgerRepository{db: db}
+}
+
+func (r *PgLedgerRepository) FindOrCreateAccount(accountType entities.LedgerAccountType, ownerID *int64, currency string) (*entities.LedgerAccount, error) {
+	// Platform accounts have no owner; COALESCE lets the unique index treat them as one account per currency.
+	insert := `INSERT INTO ledger_accounts (id, account_type, owner_id, currency) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_type, (COALESCE(owner_id, 0)), currency) DO NOTHING`
+	if _, err := r.db.Exec(insert, uuid.New(), accountType, ownerID, currency); err != nil {
+		return nil, err
+	}
+
+	account := &entities.LedgerAccount{}
+	var owner sql.NullInt64
+	query := `SELECT id, account_type, owner_id, currency, created_at FROM ledger_accounts
+		WHERE account_type = $1 AND COALESCE(owner_id, 0) = COALESCE($2::BIGINT, 0) AND currency = $3`
+	err := r.db.QueryRow(query, accountType, ownerID, currency).Scan(&account.ID, &account.Type, &owner, &account.Currency, &account.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if owner.Valid {
+		account.OwnerID = &owner.Int64
+	}
+	return account, nil
+}
+
+func (r *PgLedgerRepository) PostEntry(entry *entities.JournalEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	entry.ID = uuid.New()
+	query := `INSERT INTO journal_entries (id, kind, reference, description, created_at) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(query, entry.ID, entry.Kind, entry.Reference, entry.Description, entry.CreatedAt); err != nil {
+		return err
+	}
+
+	for _, posting := range entry.Postings {
+		posting.ID = uuid.New()
+		posting.EntryID = entry.ID
+		query := `INSERT INTO postings (id, entry_id, account_id, amount, currency) VALUES ($1, $2, $3, $4, $5)`
+		if _, err := tx.Exec(query, posting.ID, posting.EntryID, posting.AccountID, posting.Amount.Amount, posting.Amount.Currency); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PgLedgerRepository) FindEntryByReference(kind, reference string) (*entities.JournalEntry, error) {
+	entry := &entities.JournalEntry{}
+	query := `SELECT id, kind, reference, description, created_at FROM journal_entries WHERE kind = $1 AND reference = $2`
+	err := r.db.QueryRow(query, kind, reference).Scan(&entry.ID, &entry.Kind, &entry.Reference, &entry.Description, &entry.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`SELECT id, entry_id, account_id, amount, currency FROM postings WHERE entry_id = $1`, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		posting := &entities.Posting{}
+		if err := rows.Scan(&posting.ID, &posting.EntryID, &posting.AccountID, &posting.Amount.Amount, &posting.Amount.Currency); err != nil {
+			return nil, err
+		}
+		entry.Postings = append(entry.Postings, posting)
+	}
+	return entry, rows.Err()
+}
+
+func (r *PgLedgerRepository) Balance(accountType entities.LedgerAccountType, ownerID *int64, currency string) (money.Money, error) {
+	balance := money.Zero(currency)
+	query := `SELECT COALESCE(SUM(p.amount), 0) FROM postings p
+		JOIN ledger_accounts a ON a.id = p.account_id
+		WHERE a.account_type = $1 AND COALESCE(a.owner_id, 0) = COALESCE($2::BIGINT, 0) AND a.currency = $3`
+	err := r.db.QueryRow(query, accountType, ownerID, currency).Scan(&balance.Amount)
+	return balance, err
+}
+
+func (r *PgLedgerRepository) FindUnbalancedEntries() ([]uuid.UUID, error) {
+	query := `SELECT DISTINCT entry_id FROM postings GROUP BY entry_id, currency HAVING SUM(amount) <> 0`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgMembershipRepository struct {
+	db *sql.DB
+}
+
+func NewPgMembershipRepository(db *sql.DB) repositories.MembershipRepository {
+	return &PgMembershipRepository{db: db}
+}
+
+const membershipColumns = `id, subscriber_id, subscription_id, price_id, channel_id, status, started_at, current_period_end, cancelled_at, invite_link, trial_ends_at, trial_reminder_sent_at, payment_id, renewal_invoiced_at`
+
+func scanMembership(row interface{ Scan(...interface{}) error }) (*entities.Membership, error) {
+	m := &entities.Membership{}
+	var priceID, paymentID uuid.NullUUID
+	var periodEnd, cancelledAt, trialEndsAt, trialReminderSentAt, renewalInvoicedAt sql.NullTime
+	var inviteLink sql.NullString
+	if err := row.Scan(&m.ID, &m.SubscriberID, &m.SubscriptionID, &priceID, &m.ChannelID, &m.Status, &m.StartedAt, &periodEnd, &cancelledAt, &inviteLink,
+		&trialEndsAt, &trialReminderSentAt, &paymentID, &renewalInvoicedAt); err != nil {
+		return nil, err
+	}
+	if trialEndsAt.Valid {
+		m.TrialEndsAt = &trialEndsAt.Time
+	}
+	if trialReminderSentAt.Valid {
+		m.TrialReminderSentAt = &trialReminderSentAt.Time
+	}
+	if priceID.Valid {
+		m.PriceID = &priceID.UUID
+	}
+	if renewalInvoicedAt.Valid {
+		m.RenewalInvoicedAt = &renewalInvoicedAt.Time
+	}
+	if paymentID.Valid {
+		m.PaymentID = &paymentID.UUID
+	}
+	if periodEnd.Valid {
+		m.CurrentPeriodEnd = &periodEnd.Time
+	}
+	if cancelledAt.Valid {
+		m.CancelledAt = &cancelledAt.Time
+	}
+	m.InviteLink = inviteLink.String
+	return m, nil
+}
+
+func (r *PgMembershipRepository) queryMemberships(query string, args ...interface{}) ([]*entities.Membership, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []*entities.Membership
+	for rows.Next() {
+		m, err := scanMembership(rows)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, m)
+	}
+	return memberships, rows.Err()
+}
+
+func (r *PgMembershipRepository) FindByID(id uuid.UUID) (*entities.Membership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM memberships WHERE id = $1`
+	m, err := scanMembership(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *PgMembershipRepository) FindBySubscriberID(subscriberID int64) ([]*entities.Membership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM memberships WHERE subscriber_id = $1 ORDER BY started_at DESC`
+	return r.queryMemberships(query, subscriberID)
+}
+
+func (r *PgMembershipRepository) FindCurrent(subscriberID int64, subscriptionID uuid.UUID) (*entities.Membership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM memberships WHERE subscriber_id = $1 AND subscription_id = $2 AND status <> $3 ORDER BY current_period_end DESC NULLS FIRST LIMIT 1`
+	m, err := scanMembership(r.db.QueryRow(query, subscriberID, subscriptionID, entities.MembershipExpired))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *PgMembershipRepository) FindDueForExpiry(now time.Time) ([]*entities.Membership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM memberships WHERE status <> $1 AND current_period_end <= $2`
+	return r.queryMemberships(query, entities.MembershipExpired, now)
+}
+
+func (r *PgMembershipRepository) FindTrialsDueForReminder(before time.Time) ([]*entities.Membership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM memberships WHERE status = $1 AND trial_reminder_sent_at IS NULL AND current_period_end <= $2`
+	return r.queryMemberships(query, entities.MembershipTrialing, before)
+}
+
+func (r *PgMembershipRepository) FindDueForRenewal(before time.Time) ([]*entities.Membership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM memberships WHERE status = $1 AND renewal_invoiced_at IS NULL AND current_period_end <= $2`
+	return r.queryMemberships(query, entities.MembershipActive, before)
+}
+
+func (r *PgMembershipRepository) HasTrial(subscriberID int64, channelID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM memberships WHERE subscriber_id = $1 AND channel_id = $2 AND trial_ends_at IS NOT NULL)`
+	err := r.db.QueryRow(query, subscriberID, channelID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PgMembershipRepository) Create(membership *entities.Membership) error {
+	membership.ID = uuid.New()
+	query := `INSERT INTO memberships (` + membershipColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+	_, err := r.db.Exec(query, membership.ID, membership.SubscriberID, membership.SubscriptionID, membership.PriceID, membership.ChannelID, membership.Status, membership.StartedAt, membership.CurrentPeriodEnd, membership.CancelledAt, membership.InviteLink,
+		membership.TrialEndsAt, membership.TrialReminderSentAt, membership.PaymentID, membership.RenewalInvoicedAt)
+	return err
+}
+
+func (r *PgMembershipRepository) Update(membership *entities.Membership) error {
+	query := `UPDATE memberships SET price_id = $2, status = $3, current_period_end = $4, cancelled_at = $5, invite_link = $6, trial_reminder_sent_at = $7, payment_id = $8, renewal_invoiced_at = $9 WHERE id = $1`
+	_, err := r.db.Exec(query, membership.ID, membership.PriceID, membership.Status, membership.CurrentPeriodEnd, membership.CancelledAt, membership.InviteLink,
+		membership.TrialReminderSentAt, membership.PaymentID, membership.RenewalInvoicedAt)
+	return err
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"database/sql"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgPartnerRequestRepository struct {
+	db *sql.DB
+}
+
+func NewPgPartnerRequestRepository(db *sql.DB) repositories.PartnerRequestRepository {
+	return &PgPartnerRequestRepository{db: db}
+}
+
+const partnerRequestColumns = `id, partner, endpoint, remote_ip, signature, accepted, reason, created_at`
+
+func (r *PgPartnerRequestRepository) Create(request *entities.PartnerRequest) error {
+	request.ID = uuid.New()
+	query := `INSERT INTO partner_requests (` + partnerRequestColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := r.db.Exec(query, request.ID, request.Partner, request.Endpoint, request.RemoteIP, request.Signature,
+		request.Accepted, request.Reason, request.CreatedAt)
+	return err
+}
+
+func (r *PgPartnerRequestRepository) FindAcceptedBySignature(signature string) (*entities.PartnerRequest, error) {
+	request := &entities.PartnerRequest{}
+	query := `SELECT ` + partnerRequestColumns + ` FROM partner_requests WHERE signature = $1 AND accepted`
+	err := r.db.QueryRow(query, signature).Scan(&request.ID, &request.Partner, &request.Endpoint, &request.RemoteIP,
+		&request.Signature, &request.Accepted, &request.Reason, &request.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return request, nil
+}
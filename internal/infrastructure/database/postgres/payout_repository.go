@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"database/sql"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgPayoutRepository struct {
+	db *sql.DB
+}
+
+func NewPgPayoutRepository(db *sql.DB) repositories.PayoutRepository {
+	return &PgPayoutRepository{db: db}
+}
+
+const payoutColumns = `id, user_id, amount, currency, status, gateway_payout_id, failure_reason, attempts, created_at, updated_at, completed_at`
+
+func scanPayout(row interface{ Scan(...interface{}) error }) (*entities.Payout, error) {
+	p := &entities.Payout{}
+	var gatewayPayoutID, failureReason sql.NullString
+	var completedAt sql.NullTime
+	err := row.Scan(&p.ID, &p.UserID, &p.Amount.Amount, &p.Amount.Currency, &p.Status, &gatewayPayoutID, &failureReason,
+		&p.Attempts, &p.CreatedAt, &p.UpdatedAt, &completedAt)
+	if err != nil {
+		return nil, err
+	}
+	p.GatewayPayoutID = gatewayPayoutID.String
+	p.FailureReason = failureReason.String
+	if completedAt.Valid {
+		p.CompletedAt = &completedAt.Time
+	}
+	return p, nil
+}
+
+func (r *PgPayoutRepository) queryPayouts(query string, args ...interface{}) ([]*entities.Payout, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*entities.Payout
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+	return payouts, rows.Err()
+}
+
+func (r *PgPayoutRepository) FindByID(id uuid.UUID) (*entities.Payout, error) {
+	query := `SELECT ` + payoutColumns + ` FROM payouts WHERE id = $1`
+	payout, err := scanPayout(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return payout, nil
+}
+
+func (r *PgPayoutRepository) FindByUserID(userID int64) ([]*entities.Payout, error) {
+	return r.queryPayouts(`SELECT `+payoutColumns+` FROM payouts WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+}
+
+func (r *PgPayoutRepository) FindByStatus(status entities.PayoutStatus) ([]*entities.Payout, error) {
+	return r.queryPayouts(`SELECT `+payoutColumns+` FROM payouts WHERE status = $1 ORDER BY created_at`, status)
+}
+
+func (r *PgPayoutRepository) Create(payout *entities.Payout) error {
+	if payout.ID == uuid.Nil {
+		payout.ID = uuid.New()
+	}
+	query := `INSERT INTO payouts (` + payoutColumns + `) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10, $11)`
+	_, err := r.db.Exec(query, payout.ID, payout.UserID, payout.Amount.Amount, payout.Amount.Currency, payout.Status, payout.GatewayPayoutID,
+		payout.FailureReason, payout.Attempts, payout.CreatedAt, payout.UpdatedAt, payout.CompletedAt)
+	return err
+}
+
+func (r *PgPayoutRepository) Update(payout *entities.Payout) error {
+	query := `UPDATE payouts SET status = $2, gateway_payout_id = NULLIF($3, ''), failure_reason = NULLIF($4, ''), attempts = $5, updated_at = $6, completed_at = $7 WHERE id = $1`
+	_, err := r.db.Exec(query, payout.ID, payout.Status, payout.GatewayPayoutID, payout.FailureReason, payout.Attempts, payout.UpdatedAt, payout.CompletedAt)
+	return err
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"database/sql"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgPromoCodeRepository struct {
+	db *sql.DB
+}
+
+func NewPgPromoCodeRepository(db *sql.DB) repositories.PromoCodeRepository {
+	return &PgPromoCodeRepository{db: db}
+}
+
+const promoCodeColumns = `id, creator_id, channel_id, subscription_id, code, discount_type, percent_off, amount_off, currency, max_redemptions, per_user_limit, starts_at, ends_at, created_date, archived_at`
+
+// promoCodeSelect loads promo codes together with the number of payments that redeemed them.
+// Only payments that went through use up a redemption, so abandoned checkouts can't exhaust a code.
+const promoCodeSelect = `SELECT pc.id, pc.creator_id, pc.channel_id, pc.subscription_id, pc.code, pc.discount_type, pc.percent_off,
+	pc.amount_off, pc.currency, pc.max_redemptions, pc.per_user_limit, pc.starts_at, pc.ends_at, pc.created_date, pc.archived_at,
+	(SELECT COUNT(*) FROM payments p WHERE p.promo_code_id = pc.id AND p.status IN ('succeeded', 'refunded'))
+	FROM promo_codes pc`
+
+func scanPromoCode(row interface{ Scan(...interface{}) error }) (*entities.PromoCode, error) {
+	p := &entities.PromoCode{}
+	var subscriptionID uuid.NullUUID
+	var startsAt, endsAt, archivedAt sql.NullTime
+	err := row.Scan(&p.ID, &p.CreatorID, &p.ChannelID, &subscriptionID, &p.Code, &p.DiscountType, &p.PercentOff,
+		&p.AmountOff.Amount, &p.AmountOff.Currency, &p.MaxRedemptions, &p.PerUserLimit, &startsAt, &endsAt, &p.CreatedDate, &archivedAt,
+		&p.Redemptions)
+	if err != nil {
+		return nil, err
+	}
+	if subscriptionID.Valid {
+		p.SubscriptionID = &subscriptionID.UUID
+	}
+	if startsAt.Valid {
+		p.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		p.EndsAt = &endsAt.Time
+	}
+	if archivedAt.Valid {
+		p.ArchivedAt = &archivedAt.Time
+	}
+	return p, nil
+}
+
+func (r *PgPromoCodeRepository) findOne(query string, args ...interface{}) (*entities.PromoCode, error) {
+	p, err := scanPromoCode(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *PgPromoCodeRepository) FindByID(id uuid.UUID) (*entities.PromoCode, error) {
+	return r.findOne(promoCodeSelect+` WHERE pc.id = $1`, id)
+}
+
+func (r *PgPromoCodeRepository) FindByCode(channelID uuid.UUID, code string) (*entities.PromoCode, error) {
+	return r.findOne(promoCodeSelect+` WHERE pc.channel_id = $1 AND pc.code = $2 AND pc.archived_at IS NULL`, channelID, code)
+}
+
+func (r *PgPromoCodeRepository) FindByChannelID(channelID uuid.UUID) ([]*entities.PromoCode, error) {
+	rows, err := r.db.Query(promoCodeSelect+` WHERE pc.channel_id = $1 AND pc.archived_at IS NULL ORDER BY pc.created_date DESC`, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promoCodes []*entities.PromoCode
+	for rows.Next() {
+		p, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		promoCodes = append(promoCodes, p)
+	}
+	return promoCodes, rows.Err()
+}
+
+func (r *PgPromoCodeRepository) Create(p *entities.PromoCode) error {
+	p.ID = uuid.New()
+	query := `INSERT INTO promo_codes (` + promoCodeColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+	_, err := r.db.Exec(query, p.ID, p.CreatorID, p.ChannelID, p.SubscriptionID, p.Code, p.DiscountType, p.PercentOff,
+		p.AmountOff.Amount, p.AmountOff.Currency, p.MaxRedemptions, p.PerUserLimit, p.StartsAt, p.EndsAt, p.CreatedDate, p.ArchivedAt)
+	return err
+}
+
+func (r *PgPromoCodeRepository) Update(p *entities.PromoCode) error {
+	query := `UPDATE promo_codes SET max_redemptions = $2, per_user_limit = $3, starts_at = $4, ends_at = $5, archived_at = $6 WHERE id = $1`
+	_, err := r.db.Exec(query, p.ID, p.MaxRedemptions, p.PerUserLimit, p.StartsAt, p.EndsAt, p.ArchivedAt)
+	return err
+}
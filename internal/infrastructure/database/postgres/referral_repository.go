@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"database/sql"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgReferralRepository struct {
+	db *sql.DB
+}
+
+func NewPgReferralRepository(db *sql.DB) repositories.ReferralRepository {
+	return &PgReferralRepository{db: db}
+}
+
+const referralColumns = `id, channel_id, subscriber_id, referrer_id, link_id, share_bps, payment_id, created_at`
+
+func scanReferral(row interface{ Scan(...interface{}) error }) (*entities.Referral, error) {
+	r := &entities.Referral{}
+	err := row.Scan(&r.ID, &r.ChannelID, &r.SubscriberID, &r.ReferrerID, &r.LinkID, &r.ShareBPS, &r.PaymentID, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *PgReferralRepository) findOne(query string, args ...interface{}) (*entities.Referral, error) {
+	referral, err := scanReferral(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return referral, nil
+}
+
+func (r *PgReferralRepository) FindByID(id uuid.UUID) (*entities.Referral, error) {
+	return r.findOne(`SELECT `+referralColumns+` FROM referrals WHERE id = $1`, id)
+}
+
+func (r *PgReferralRepository) FindBySubscriber(channelID uuid.UUID, subscriberID int64) (*entities.Referral, error) {
+	return r.findOne(`SELECT `+referralColumns+` FROM referrals WHERE channel_id = $1 AND subscriber_id = $2`, channelID, subscriberID)
+}
+
+func (r *PgReferralRepository) Create(referral *entities.Referral) error {
+	referral.ID = uuid.New()
+	query := `INSERT INTO referrals (` + referralColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := r.db.Exec(query, referral.ID, referral.ChannelID, referral.SubscriberID, referral.ReferrerID, referral.LinkID,
+		referral.ShareBPS, referral.PaymentID, referral.CreatedAt)
+	return err
+}
+
+func (r *PgReferralRepository) StatsByLinkID(linkID uuid.UUID) (*entities.ReferralStats, error) {
+	stats := &entities.ReferralStats{}
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM referrals WHERE link_id = $1`, linkID).Scan(&stats.Conversions); err != nil {
+		return nil, err
+	}
+
+	// Refunds take back the share of the refunded part, rounded half up like the ledger does
+	query := `SELECT p.currency, SUM(p.referral_amount - (p.referral_amount * p.refunded_amount * 2 + p.amount) / (p.amount * 2))
+		FROM payments p JOIN referrals rf ON rf.id = p.referral_id
+		WHERE rf.link_id = $1 AND p.status IN ('succeeded', 'refunded') AND p.referral_amount > 0
+		GROUP BY p.currency ORDER BY p.currency`
+	rows, err := r.db.Query(query, linkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var earned money.Money
+		if err := rows.Scan(&earned.Currency, &earned.Amount); err != nil {
+			return nil, err
+		}
+		stats.Earned = append(stats.Earned, earned)
+	}
+	return stats, rows.Err()
+}
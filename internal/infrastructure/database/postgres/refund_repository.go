@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"database/sql"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgRefundRepository struct {
+	db *sql.DB
+}
+
+func NewPgRefundRepository(db *sql.DB) repositories.RefundRepository {
+	return &PgRefundRepository{db: db}
+}
+
+const refundColumns = `id, payment_id, source, requested_by, idempotency_key, amount, currency, status, reason, revoke_access, provider_refund_id, failure_reason, created_at, updated_at`
+
+func scanRefund(row interface{ Scan(...interface{}) error }) (*entities.Refund, error) {
+	r := &entities.Refund{}
+	var requestedBy sql.NullInt64
+	var reason, providerRefundID, failureReason sql.NullString
+	err := row.Scan(&r.ID, &r.PaymentID, &r.Source, &requestedBy, &r.IdempotencyKey, &r.Amount.Amount, &r.Amount.Currency, &r.Status,
+		&reason, &r.RevokeAccess, &providerRefundID, &failureReason, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if requestedBy.Valid {
+		r.RequestedBy = &requestedBy.Int64
+	}
+	r.Reason = reason.String
+	r.ProviderRefundID = providerRefundID.String
+	r.FailureReason = failureReason.String
+	return r, nil
+}
+
+func (r *PgRefundRepository) FindByPaymentID(paymentID uuid.UUID) ([]*entities.Refund, error) {
+	query := `SELECT ` + refundColumns + ` FROM refunds WHERE payment_id = $1 ORDER BY created_at`
+	rows, err := r.db.Query(query, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []*entities.Refund
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, rows.Err()
+}
+
+func (r *PgRefundRepository) FindByIdempotencyKey(paymentID uuid.UUID, key string) (*entities.Refund, error) {
+	query := `SELECT ` + refundColumns + ` FROM refunds WHERE payment_id = $1 AND idempotency_key = $2`
+	refund, err := scanRefund(r.db.QueryRow(query, paymentID, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return refund, nil
+}
+
+func (r *PgRefundRepository) Create(refund *entities.Refund) error {
+	refund.ID = uuid.New()
+	query := `INSERT INTO refunds (` + refundColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, NULLIF($11, ''), NULLIF($12, ''), $13, $14)`
+	_, err := r.db.Exec(query, refund.ID, refund.PaymentID, refund.Source, refund.RequestedBy, refund.IdempotencyKey, refund.Amount.Amount, refund.Amount.Currency,
+		refund.Status, refund.Reason, refund.RevokeAccess, refund.ProviderRefundID, refund.FailureReason, refund.CreatedAt, refund.UpdatedAt)
+	return err
+}
+
+func (r *PgRefundRepository) Update(refund *entities.Refund) error {
+	query := `UPDATE refunds SET status = $2, provider_refund_id = NULLIF($3, ''), failure_reason = NULLIF($4, ''), updated_at = $5 WHERE id = $1`
+	_, err := r.db.Exec(query, refund.ID, refund.Status, refund.ProviderRefundID, refund.FailureReason, refund.UpdatedAt)
+	return err
+}
@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"database/sql"
+	"time"
 	"tribute-back/internal/domain/entities"
 	"tribute-back/internal/domain/repositories"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type PgUserRepository struct {
@@ -18,27 +20,73 @@ func NewPgUserRepository(db *sql.DB) repositories.UserRepository {
 
 func (r *PgUserRepository) FindByID(id int64) (*entities.User, error) {
 	user := &entities.User{}
-	// Note: The 'subscriptions' field is not in the 'users' table and will be populated in the service layer.
-	query := `SELECT user_id, earned, is_verified, is_sub_published, is_onboarded, card_number FROM users WHERE user_id = $1`
-	err := r.db.QueryRow(query, id).Scan(&user.ID, &user.Earned, &user.IsVerified, &user.IsSubPublished, &user.IsOnboarded, &user.CardNumber)
+	var cardToken, cardLast4, cardBrand sql.NullString
+	var cardExpMonth, cardExpYear sql.NullInt64
+	// Note: The 'subscriptions' and 'earned' fields are not in the 'users' table and will be populated in the service layer.
+	query := `SELECT user_id, is_verified, is_sub_published, is_onboarded, card_token, card_last4, card_brand, card_exp_month, card_exp_year FROM users WHERE user_id = $1`
+	err := r.db.QueryRow(query, id).Scan(&user.ID, &user.IsVerified, &user.IsSubPublished, &user.IsOnboarded, &cardToken, &cardLast4, &cardBrand, &cardExpMonth, &cardExpYear)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Or a specific "not found" error
 		}
 		return nil, err
 	}
+	if cardToken.Valid {
+		user.PayoutCard = &entities.PayoutCard{
+			Token:    cardToken.String,
+			Last4:    cardLast4.String,
+			Brand:    cardBrand.String,
+			ExpMonth: int(cardExpMonth.Int64),
+			ExpYear:  int(cardExpYear.Int64),
+		}
+	}
 	return user, nil
 }
 
+// payoutCardColumns returns the values of the card_* columns, all NULL if the user has no card.
+func payoutCardColumns(user *entities.User) []interface{} {
+	card := user.PayoutCard
+	if card == nil {
+		return []interface{}{nil, nil, nil, nil, nil}
+	}
+	return []interface{}{card.Token, card.Last4, card.Brand, sql.NullInt64{Int64: int64(card.ExpMonth), Valid: card.ExpMonth != 0}, sql.NullInt64{Int64: int64(card.ExpYear), Valid: card.ExpYear != 0}}
+}
+
 func (r *PgUserRepository) Update(user *entities.User) error {
-	query := `UPDATE users SET earned = $2, is_verified = $3, is_sub_published = $4, is_onboarded = $5, card_number = $6 WHERE user_id = $1`
-	_, err := r.db.Exec(query, user.ID, user.Earned, user.IsVerified, user.IsSubPublished, user.IsOnboarded, user.CardNumber)
+	query := `UPDATE users SET is_verified = $2, is_sub_published = $3, is_onboarded = $4, card_token = $5, card_last4 = $6, card_brand = $7, card_exp_month = $8, card_exp_year = $9 WHERE user_id = $1`
+	args := append([]interface{}{user.ID, user.IsVerified, user.IsSubPublished, user.IsOnboarded}, payoutCardColumns(user)...)
+	_, err := r.db.Exec(query, args...)
 	return err
 }
 
 func (r *PgUserRepository) Create(user *entities.User) error {
-	query := `INSERT INTO users (user_id, earned, is_verified, is_sub_published, is_onboarded, card_number) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.db.Exec(query, user.ID, user.Earned, user.IsVerified, user.IsSubPublished, user.IsOnboarded, user.CardNumber)
+	query := `INSERT INTO users (user_id, is_verified, is_sub_published, is_onboarded, card_token, card_last4, card_brand, card_exp_month, card_exp_year) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	args := append([]interface{}{user.ID, user.IsVerified, user.IsSubPublished, user.IsOnboarded}, payoutCardColumns(user)...)
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+func (r *PgUserRepository) FindLegacyCardNumbers(limit int) (map[int64]string, error) {
+	rows, err := r.db.Query(`SELECT user_id, card_number FROM users WHERE card_number IS NOT NULL AND card_number <> '' LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	numbers := make(map[int64]string)
+	for rows.Next() {
+		var userID int64
+		var number string
+		if err := rows.Scan(&userID, &number); err != nil {
+			return nil, err
+		}
+		numbers[userID] = number
+	}
+	return numbers, rows.Err()
+}
+
+func (r *PgUserRepository) ClearLegacyCardNumber(userID int64) error {
+	_, err := r.db.Exec(`UPDATE users SET card_number = NULL WHERE user_id = $1`, userID)
 	return err
 }
 
@@ -55,8 +103,57 @@ func NewPgChannelRepository(db *sql.DB) repositories.ChannelRepository {
 	return &PgChannelRepository{db: db}
 }
 
+const channelColumns = `id, user_id, channel_title, channel_username, telegram_chat_id, is_verified, referral_share_bps,
+	missing_permissions, verification_attempts, verification_checked_at, next_verification_at`
+
+func scanChannel(row interface{ Scan(...interface{}) error }) (*entities.Channel, error) {
+	channel := &entities.Channel{}
+	var username sql.NullString
+	var chatID sql.NullInt64
+	var missing []string
+	var checkedAt, nextAt sql.NullTime
+	err := row.Scan(&channel.ID, &channel.UserID, &channel.ChannelTitle, &username, &chatID, &channel.IsVerified, &channel.ReferralShareBPS,
+		pq.Array(&missing), &channel.VerificationAttempts, &checkedAt, &nextAt)
+	if err != nil {
+		return nil, err
+	}
+	channel.ChannelUsername = username.String
+	if chatID.Valid {
+		channel.TelegramChatID = &chatID.Int64
+	}
+	for _, permission := range missing {
+		channel.MissingPermissions = append(channel.MissingPermissions, entities.ChannelPermission(permission))
+	}
+	if checkedAt.Valid {
+		channel.VerificationCheckedAt = &checkedAt.Time
+	}
+	if nextAt.Valid {
+		channel.NextVerificationAt = &nextAt.Time
+	}
+	return channel, nil
+}
+
+func missingPermissions(channel *entities.Channel) interface{} {
+	missing := make([]string, len(channel.MissingPermissions))
+	for i, permission := range channel.MissingPermissions {
+		missing[i] = string(permission)
+	}
+	return pq.Array(missing)
+}
+
+func (r *PgChannelRepository) findOne(query string, args ...interface{}) (*entities.Channel, error) {
+	channel, err := scanChannel(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return channel, nil
+}
+
 func (r *PgChannelRepository) FindByUserID(userID int64) ([]*entities.Channel, error) {
-	query := "SELECT id, user_id, channel_title, channel_username, is_verified FROM channels WHERE user_id = $1"
+	query := `SELECT ` + channelColumns + ` FROM channels WHERE user_id = $1`
 	rows, err := r.db.Query(query, userID)
 	if err != nil {
 		return nil, err
@@ -65,37 +162,65 @@ func (r *PgChannelRepository) FindByUserID(userID int64) ([]*entities.Channel, e
 
 	var channels []*entities.Channel
 	for rows.Next() {
-		channel := &entities.Channel{}
-		if err := rows.Scan(&channel.ID, &channel.UserID, &channel.ChannelTitle, &channel.ChannelUsername, &channel.IsVerified); err != nil {
+		channel, err := scanChannel(rows)
+		if err != nil {
 			return nil, err
 		}
 		channels = append(channels, channel)
 	}
-	return channels, nil
+	return channels, rows.Err()
 }
 
 func (r *PgChannelRepository) FindByID(id uuid.UUID) (*entities.Channel, error) {
-	channel := &entities.Channel{}
-	query := "SELECT id, user_id, channel_title, channel_username, is_verified FROM channels WHERE id = $1"
-	err := r.db.QueryRow(query, id).Scan(&channel.ID, &channel.UserID, &channel.ChannelTitle, &channel.ChannelUsername, &channel.IsVerified)
+	return r.findOne(`SELECT `+channelColumns+` FROM channels WHERE id = $1`, id)
+}
+
+func (r *PgChannelRepository) FindByUsername(username string) (*entities.Channel, error) {
+	// Usernames are stored as entered, with or without the @, and are case-insensitive in Telegram
+	query := `SELECT ` + channelColumns + ` FROM channels
+		WHERE LOWER(LTRIM(channel_username, '@')) = LOWER(LTRIM($1, '@'))
+		ORDER BY is_verified DESC LIMIT 1`
+	return r.findOne(query, username)
+}
+
+func (r *PgChannelRepository) FindByTelegramChatID(chatID int64) (*entities.Channel, error) {
+	return r.findOne(`SELECT `+channelColumns+` FROM channels WHERE telegram_chat_id = $1`, chatID)
+}
+
+func (r *PgChannelRepository) FindDueForVerification(now time.Time) ([]*entities.Channel, error) {
+	query := `SELECT ` + channelColumns + ` FROM channels
+		WHERE NOT is_verified AND next_verification_at IS NOT NULL AND next_verification_at <= $1
+		ORDER BY next_verification_at`
+	rows, err := r.db.Query(query, now)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, err
 	}
-	return channel, nil
+	defer rows.Close()
+
+	var channels []*entities.Channel
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	return channels, rows.Err()
 }
 
 func (r *PgChannelRepository) Create(channel *entities.Channel) error {
-	query := `INSERT INTO channels (id, user_id, channel_title, channel_username, is_verified) VALUES ($1, $2, $3, $4, $5)`
-	_, err := r.db.Exec(query, uuid.New(), channel.UserID, channel.ChannelTitle, channel.ChannelUsername, channel.IsVerified)
+	channel.ID = uuid.New()
+	query := `INSERT INTO channels (` + channelColumns + `) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11)`
+	_, err := r.db.Exec(query, channel.ID, channel.UserID, channel.ChannelTitle, channel.ChannelUsername, channel.TelegramChatID, channel.IsVerified, channel.ReferralShareBPS,
+		missingPermissions(channel), channel.VerificationAttempts, channel.VerificationCheckedAt, channel.NextVerificationAt)
 	return err
 }
 
 func (r *PgChannelRepository) Update(channel *entities.Channel) error {
-	query := `UPDATE channels SET user_id = $2, channel_title = $3, channel_username = $4, is_verified = $5 WHERE id = $1`
-	_, err := r.db.Exec(query, channel.ID, channel.UserID, channel.ChannelTitle, channel.ChannelUsername, channel.IsVerified)
+	query := `UPDATE channels SET user_id = $2, channel_title = $3, channel_username = NULLIF($4, ''), telegram_chat_id = $5, is_verified = $6, referral_share_bps = $7,
+		missing_permissions = $8, verification_attempts = $9, verification_checked_at = $10, next_verification_at = $11 WHERE id = $1`
+	_, err := r.db.Exec(query, channel.ID, channel.UserID, channel.ChannelTitle, channel.ChannelUsername, channel.TelegramChatID, channel.IsVerified, channel.ReferralShareBPS,
+		missingPermissions(channel), channel.VerificationAttempts, channel.VerificationCheckedAt, channel.NextVerificationAt)
 	return err
 }
 
@@ -113,22 +238,24 @@ func NewPgSubscriptionRepository(db *sql.DB) repositories.SubscriptionRepository
 	return &PgSubscriptionRepository{db: db}
 }
 
-func (r *PgSubscriptionRepository) FindByID(id uuid.UUID) (*entities.Subscription, error) {
+const subscriptionColumns = `id, channel_id, user_id, channel_username, title, description, button_text, sort_order, created_date, archived_at`
+
+func scanSubscription(row interface{ Scan(...interface{}) error }) (*entities.Subscription, error) {
 	sub := &entities.Subscription{}
-	query := `SELECT id, channel_id, user_id, channel_username, title, description, button_text, price, created_date FROM subscriptions WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(&sub.ID, &sub.ChannelID, &sub.UserID, &sub.ChannelUsername, &sub.Title, &sub.Description, &sub.ButtonText, &sub.Price, &sub.CreatedDate)
+	var archivedAt sql.NullTime
+	err := row.Scan(&sub.ID, &sub.ChannelID, &sub.UserID, &sub.ChannelUsername, &sub.Title, &sub.Description, &sub.ButtonText,
+		&sub.SortOrder, &sub.CreatedDate, &archivedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, err
 	}
+	if archivedAt.Valid {
+		sub.ArchivedAt = &archivedAt.Time
+	}
 	return sub, nil
 }
 
-func (r *PgSubscriptionRepository) FindByUserID(userID int64) ([]*entities.Subscription, error) {
-	query := `SELECT id, channel_id, user_id, channel_username, title, description, button_text, price, created_date FROM subscriptions WHERE user_id = $1`
-	rows, err := r.db.Query(query, userID)
+func (r *PgSubscriptionRepository) querySubscriptions(query string, args ...interface{}) ([]*entities.Subscription, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -136,51 +263,132 @@ func (r *PgSubscriptionRepository) FindByUserID(userID int64) ([]*entities.Subsc
 
 	var subscriptions []*entities.Subscription
 	for rows.Next() {
-		sub := &entities.Subscription{}
-		if err := rows.Scan(&sub.ID, &sub.ChannelID, &sub.UserID, &sub.ChannelUsername, &sub.Title, &sub.Description, &sub.ButtonText, &sub.Price, &sub.CreatedDate); err != nil {
+		sub, err := scanSubscription(rows)
+		if err != nil {
 			return nil, err
 		}
 		subscriptions = append(subscriptions, sub)
 	}
-	return subscriptions, nil
+	return subscriptions, rows.Err()
+}
+
+func (r *PgSubscriptionRepository) FindByID(id uuid.UUID) (*entities.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE id = $1`
+	sub, err := scanSubscription(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (r *PgSubscriptionRepository) FindByUserID(userID int64) ([]*entities.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE user_id = $1 AND archived_at IS NULL ORDER BY channel_id, sort_order, created_date`
+	return r.querySubscriptions(query, userID)
+}
+
+func (r *PgSubscriptionRepository) FindByChannelID(channelID uuid.UUID) ([]*entities.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE channel_id = $1 AND archived_at IS NULL ORDER BY sort_order, created_date`
+	return r.querySubscriptions(query, channelID)
 }
 
 func (r *PgSubscriptionRepository) Create(subscription *entities.Subscription) error {
-	query := `INSERT INTO subscriptions (id, channel_id, user_id, channel_username, title, description, button_text, price, created_date) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-	_, err := r.db.Exec(query, uuid.New(), subscription.ChannelID, subscription.UserID, subscription.ChannelUsername, subscription.Title, subscription.Description, subscription.ButtonText, subscription.Price, subscription.CreatedDate)
+	subscription.ID = uuid.New()
+	query := `INSERT INTO subscriptions (` + subscriptionColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := r.db.Exec(query, subscription.ID, subscription.ChannelID, subscription.UserID, subscription.ChannelUsername, subscription.Title,
+		subscription.Description, subscription.ButtonText, subscription.SortOrder, subscription.CreatedDate, subscription.ArchivedAt)
 	return err
 }
 
 func (r *PgSubscriptionRepository) Update(subscription *entities.Subscription) error {
-	query := `UPDATE subscriptions SET title = $2, description = $3, button_text = $4, price = $5 WHERE id = $1`
-	_, err := r.db.Exec(query, subscription.ID, subscription.Title, subscription.Description, subscription.ButtonText, subscription.Price)
+	query := `UPDATE subscriptions SET title = $2, description = $3, button_text = $4, sort_order = $5, archived_at = $6 WHERE id = $1`
+	_, err := r.db.Exec(query, subscription.ID, subscription.Title, subscription.Description, subscription.ButtonText, subscription.SortOrder, subscription.ArchivedAt)
 	return err
 }
 
-func (r *PgSubscriptionRepository) FindByChannelID(channelID uuid.UUID) (*entities.Subscription, error) {
-	sub := &entities.Subscription{}
-	query := `SELECT id, channel_id, user_id, channel_username, title, description, button_text, price, created_date FROM subscriptions WHERE channel_id = $1`
-	err := r.db.QueryRow(query, channelID).Scan(&sub.ID, &sub.ChannelID, &sub.UserID, &sub.ChannelUsername, &sub.Title, &sub.Description, &sub.ButtonText, &sub.Price, &sub.CreatedDate)
+type PgPaymentRepository struct {
+	db *sql.DB
+}
+
+func NewPgPaymentRepository(db *sql.DB) repositories.PaymentRepository {
+	return &PgPaymentRepository{db: db}
+}
+
+const paymentColumns = `id, payer_id, creator_id, subscription_id, price_id, amount, currency, promo_code_id, discount_amount, refunded_amount, referral_id, referral_amount, status, provider, provider_charge_id, failure_reason, description, created_date, updated_at`
+
+func scanPayment(row interface{ Scan(...interface{}) error }) (*entities.Payment, error) {
+	p := &entities.Payment{}
+	var creatorID sql.NullInt64
+	var subscriptionID, priceID, promoCodeID, referralID uuid.NullUUID
+	var providerChargeID, failureReason, description sql.NullString
+	err := row.Scan(&p.ID, &p.PayerID, &creatorID, &subscriptionID, &priceID, &p.Amount.Amount, &p.Amount.Currency, &promoCodeID, &p.Discount.Amount, &p.Refunded.Amount, &referralID, &p.ReferralShare.Amount, &p.Status,
+		&p.Provider, &providerChargeID, &failureReason, &description, &p.CreatedDate, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if creatorID.Valid {
+		p.CreatorID = &creatorID.Int64
+	}
+	if subscriptionID.Valid {
+		p.SubscriptionID = &subscriptionID.UUID
+	}
+	if priceID.Valid {
+		p.PriceID = &priceID.UUID
+	}
+	if promoCodeID.Valid {
+		p.PromoCodeID = &promoCodeID.UUID
+	}
+	if referralID.Valid {
+		p.ReferralID = &referralID.UUID
+	}
+	p.Discount.Currency = p.Amount.Currency
+	p.Refunded.Currency = p.Amount.Currency
+	p.ReferralShare.Currency = p.Amount.Currency
+	p.ProviderChargeID = providerChargeID.String
+	p.FailureReason = failureReason.String
+	p.Description = description.String
+	return p, nil
+}
+
+func (r *PgPaymentRepository) FindByID(id uuid.UUID) (*entities.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE id = $1`
+	payment, err := scanPayment(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil // No subscription found for this channel, not an error
+			return nil, nil
 		}
 		return nil, err
 	}
-	return sub, nil
+	return payment, nil
 }
 
-type PgPaymentRepository struct {
-	db *sql.DB
+func (r *PgPaymentRepository) FindByPayerID(payerID int64) ([]*entities.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE payer_id = $1 ORDER BY created_date DESC`
+	return r.queryPayments(query, payerID)
 }
 
-func NewPgPaymentRepository(db *sql.DB) repositories.PaymentRepository {
-	return &PgPaymentRepository{db: db}
+func (r *PgPaymentRepository) FindByCreatorID(creatorID int64) ([]*entities.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE creator_id = $1 AND status IN ('succeeded', 'refunded') ORDER BY created_date DESC`
+	return r.queryPayments(query, creatorID)
 }
 
-func (r *PgPaymentRepository) FindByUserID(userID int64) ([]*entities.Payment, error) {
-	query := "SELECT id, user_id, description, created_date FROM payments WHERE user_id = $1"
-	rows, err := r.db.Query(query, userID)
+func (r *PgPaymentRepository) FindByPromoCode(promoCodeID uuid.UUID, payerID int64) ([]*entities.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE promo_code_id = $1 AND payer_id = $2 ORDER BY created_date DESC`
+	return r.queryPayments(query, promoCodeID, payerID)
+}
+
+func (r *PgPaymentRepository) HasPaidForChannel(payerID int64, channelID uuid.UUID, exceptID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM payments p JOIN subscriptions s ON s.id = p.subscription_id
+		WHERE p.payer_id = $1 AND s.channel_id = $2 AND p.id <> $3 AND p.status IN ('succeeded', 'refunded'))`
+	err := r.db.QueryRow(query, payerID, channelID, exceptID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PgPaymentRepository) queryPayments(query string, args ...interface{}) ([]*entities.Payment, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -188,17 +396,27 @@ func (r *PgPaymentRepository) FindByUserID(userID int64) ([]*entities.Payment, e
 
 	var payments []*entities.Payment
 	for rows.Next() {
-		payment := &entities.Payment{}
-		if err := rows.Scan(&payment.ID, &payment.UserID, &payment.Description, &payment.CreatedDate); err != nil {
+		payment, err := scanPayment(rows)
+		if err != nil {
 			return nil, err
 		}
 		payments = append(payments, payment)
 	}
-	return payments, nil
+	return payments, rows.Err()
 }
 
 func (r *PgPaymentRepository) Create(payment *entities.Payment) error {
-	query := `INSERT INTO payments (id, user_id, description, created_date) VALUES ($1, $2, $3, $4)`
-	_, err := r.db.Exec(query, uuid.New(), payment.UserID, payment.Description, payment.CreatedDate)
+	if payment.ID == uuid.Nil {
+		payment.ID = uuid.New()
+	}
+	query := `INSERT INTO payments (` + paymentColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), NULLIF($16, ''), $17, $18, $19)`
+	_, err := r.db.Exec(query, payment.ID, payment.PayerID, payment.CreatorID, payment.SubscriptionID, payment.PriceID, payment.Amount.Amount, payment.Amount.Currency,
+		payment.PromoCodeID, payment.Discount.Amount, payment.Refunded.Amount, payment.ReferralID, payment.ReferralShare.Amount, payment.Status, payment.Provider, payment.ProviderChargeID, payment.FailureReason, payment.Description, payment.CreatedDate, payment.UpdatedAt)
+	return err
+}
+
+func (r *PgPaymentRepository) Update(payment *entities.Payment) error {
+	query := `UPDATE payments SET status = $2, provider_charge_id = NULLIF($3, ''), failure_reason = NULLIF($4, ''), refunded_amount = $5, referral_id = $6, referral_amount = $7, updated_at = $8 WHERE id = $1`
+	_, err := r.db.Exec(query, payment.ID, payment.Status, payment.ProviderChargeID, payment.FailureReason, payment.Refunded.Amount, payment.ReferralID, payment.ReferralShare.Amount, payment.UpdatedAt)
 	return err
 }
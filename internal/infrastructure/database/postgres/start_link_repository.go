@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgStartLinkRepository struct {
+	db *sql.DB
+}
+
+func NewPgStartLinkRepository(db *sql.DB) repositories.StartLinkRepository {
+	return &PgStartLinkRepository{db: db}
+}
+
+const startLinkColumns = `id, code, creator_id, referrer_id, channel_id, price_id, label, created_at, archived_at`
+
+// startLinkSelect loads start links together with their click counts.
+const startLinkSelect = `SELECT sl.id, sl.code, sl.creator_id, sl.referrer_id, sl.channel_id, sl.price_id, sl.label, sl.created_at, sl.archived_at,
+	(SELECT COUNT(*) FROM start_link_clicks c WHERE c.link_id = sl.id),
+	(SELECT COUNT(DISTINCT c.user_id) FROM start_link_clicks c WHERE c.link_id = sl.id)
+	FROM start_links sl`
+
+func scanStartLink(row interface{ Scan(...interface{}) error }) (*entities.StartLink, error) {
+	l := &entities.StartLink{}
+	var referrerID sql.NullInt64
+	var priceID uuid.NullUUID
+	var archivedAt sql.NullTime
+	err := row.Scan(&l.ID, &l.Code, &l.CreatorID, &referrerID, &l.ChannelID, &priceID, &l.Label, &l.CreatedAt, &archivedAt, &l.Clicks, &l.Visitors)
+	if err != nil {
+		return nil, err
+	}
+	if referrerID.Valid {
+		l.ReferrerID = &referrerID.Int64
+	}
+	if priceID.Valid {
+		l.PriceID = &priceID.UUID
+	}
+	if archivedAt.Valid {
+		l.ArchivedAt = &archivedAt.Time
+	}
+	return l, nil
+}
+
+func (r *PgStartLinkRepository) findOne(query string, args ...interface{}) (*entities.StartLink, error) {
+	l, err := scanStartLink(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+func (r *PgStartLinkRepository) FindByID(id uuid.UUID) (*entities.StartLink, error) {
+	return r.findOne(startLinkSelect+` WHERE sl.id = $1`, id)
+}
+
+func (r *PgStartLinkRepository) FindByCode(code string) (*entities.StartLink, error) {
+	return r.findOne(startLinkSelect+` WHERE sl.code = $1`, code)
+}
+
+func (r *PgStartLinkRepository) FindByChannelID(channelID uuid.UUID) ([]*entities.StartLink, error) {
+	return r.findMany(startLinkSelect+` WHERE sl.channel_id = $1 AND sl.referrer_id IS NULL AND sl.archived_at IS NULL ORDER BY sl.created_at DESC`, channelID)
+}
+
+func (r *PgStartLinkRepository) FindByReferrerID(referrerID int64) ([]*entities.StartLink, error) {
+	return r.findMany(startLinkSelect+` WHERE sl.referrer_id = $1 AND sl.archived_at IS NULL ORDER BY sl.created_at DESC`, referrerID)
+}
+
+func (r *PgStartLinkRepository) FindReferralLink(channelID uuid.UUID, referrerID int64) (*entities.StartLink, error) {
+	return r.findOne(startLinkSelect+` WHERE sl.channel_id = $1 AND sl.referrer_id = $2 AND sl.archived_at IS NULL`, channelID, referrerID)
+}
+
+func (r *PgStartLinkRepository) FindLastReferralClick(channelID uuid.UUID, userID int64, since time.Time) (*entities.StartLink, error) {
+	query := startLinkSelect + `
+		JOIN start_link_clicks lc ON lc.link_id = sl.id
+		WHERE sl.channel_id = $1 AND sl.referrer_id IS NOT NULL AND lc.user_id = $2 AND lc.created_at >= $3
+		ORDER BY lc.created_at DESC LIMIT 1`
+	return r.findOne(query, channelID, userID, since)
+}
+
+func (r *PgStartLinkRepository) findMany(query string, args ...interface{}) ([]*entities.StartLink, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*entities.StartLink
+	for rows.Next() {
+		l, err := scanStartLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+func (r *PgStartLinkRepository) Create(link *entities.StartLink) error {
+	link.ID = uuid.New()
+	query := `INSERT INTO start_links (` + startLinkColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.db.Exec(query, link.ID, link.Code, link.CreatorID, link.ReferrerID, link.ChannelID, link.PriceID, link.Label, link.CreatedAt, link.ArchivedAt)
+	return err
+}
+
+func (r *PgStartLinkRepository) Update(link *entities.StartLink) error {
+	query := `UPDATE start_links SET label = $2, archived_at = $3 WHERE id = $1`
+	_, err := r.db.Exec(query, link.ID, link.Label, link.ArchivedAt)
+	return err
+}
+
+func (r *PgStartLinkRepository) RecordClick(click *entities.StartLinkClick) error {
+	click.ID = uuid.New()
+	query := `INSERT INTO start_link_clicks (id, link_id, user_id, chat_type, chat_instance, auth_date, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)
+		ON CONFLICT (link_id, user_id, auth_date) DO NOTHING`
+	_, err := r.db.Exec(query, click.ID, click.LinkID, click.UserID, click.ChatType, click.ChatInstance, click.AuthDate, click.CreatedAt)
+	return err
+}
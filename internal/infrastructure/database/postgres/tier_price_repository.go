@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"database/sql"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgTierPriceRepository struct {
+	db *sql.DB
+}
+
+func NewPgTierPriceRepository(db *sql.DB) repositories.TierPriceRepository {
+	return &PgTierPriceRepository{db: db}
+}
+
+const tierPriceColumns = `id, subscription_id, billing_interval, amount, currency, trial_days, sort_order, created_date, archived_at`
+
+func scanTierPrice(row interface{ Scan(...interface{}) error }) (*entities.TierPrice, error) {
+	p := &entities.TierPrice{}
+	var archivedAt sql.NullTime
+	err := row.Scan(&p.ID, &p.SubscriptionID, &p.Interval, &p.Price.Amount, &p.Price.Currency, &p.TrialDays, &p.SortOrder,
+		&p.CreatedDate, &archivedAt)
+	if err != nil {
+		return nil, err
+	}
+	if archivedAt.Valid {
+		p.ArchivedAt = &archivedAt.Time
+	}
+	return p, nil
+}
+
+func (r *PgTierPriceRepository) FindByID(id uuid.UUID) (*entities.TierPrice, error) {
+	query := `SELECT ` + tierPriceColumns + ` FROM tier_prices WHERE id = $1`
+	p, err := scanTierPrice(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *PgTierPriceRepository) FindBySubscriptionID(subscriptionID uuid.UUID) ([]*entities.TierPrice, error) {
+	query := `SELECT ` + tierPriceColumns + ` FROM tier_prices WHERE subscription_id = $1 AND archived_at IS NULL ORDER BY sort_order, created_date`
+	rows, err := r.db.Query(query, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []*entities.TierPrice
+	for rows.Next() {
+		p, err := scanTierPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+func (r *PgTierPriceRepository) Create(price *entities.TierPrice) error {
+	price.ID = uuid.New()
+	query := `INSERT INTO tier_prices (` + tierPriceColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.db.Exec(query, price.ID, price.SubscriptionID, price.Interval, price.Price.Amount, price.Price.Currency, price.TrialDays,
+		price.SortOrder, price.CreatedDate, price.ArchivedAt)
+	return err
+}
+
+func (r *PgTierPriceRepository) Update(price *entities.TierPrice) error {
+	query := `UPDATE tier_prices SET amount = $2, currency = $3, trial_days = $4, sort_order = $5, archived_at = $6 WHERE id = $1`
+	_, err := r.db.Exec(query, price.ID, price.Price.Amount, price.Price.Currency, price.TrialDays, price.SortOrder, price.ArchivedAt)
+	return err
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"database/sql"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+)
+
+type PgVaultRepository struct {
+	db *sql.DB
+}
+
+func NewPgVaultRepository(db *sql.DB) repositories.VaultRepository {
+	return &PgVaultRepository{db: db}
+}
+
+const vaultColumns = `token, key_id, wrapped_key, ciphertext, created_at, updated_at`
+
+func scanVaultRecord(row interface{ Scan(...interface{}) error }) (*entities.VaultRecord, error) {
+	record := &entities.VaultRecord{}
+	if err := row.Scan(&record.Token, &record.KeyID, &record.WrappedKey, &record.Ciphertext, &record.CreatedAt, &record.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *PgVaultRepository) FindByToken(token string) (*entities.VaultRecord, error) {
+	query := `SELECT ` + vaultColumns + ` FROM vault_records WHERE token = $1`
+	record, err := scanVaultRecord(r.db.QueryRow(query, token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *PgVaultRepository) FindNotWrappedWith(keyID string, limit int) ([]*entities.VaultRecord, error) {
+	query := `SELECT ` + vaultColumns + ` FROM vault_records WHERE key_id <> $1 ORDER BY created_at LIMIT $2`
+	rows, err := r.db.Query(query, keyID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*entities.VaultRecord
+	for rows.Next() {
+		record, err := scanVaultRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (r *PgVaultRepository) Create(record *entities.VaultRecord) error {
+	query := `INSERT INTO vault_records (` + vaultColumns + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.db.Exec(query, record.Token, record.KeyID, record.WrappedKey, record.Ciphertext, record.CreatedAt, record.UpdatedAt)
+	return err
+}
+
+func (r *PgVaultRepository) UpdateKey(record *entities.VaultRecord) error {
+	query := `UPDATE vault_records SET key_id = $2, wrapped_key = $3, updated_at = $4 WHERE token = $1`
+	_, err := r.db.Exec(query, record.Token, record.KeyID, record.WrappedKey, record.UpdatedAt)
+	return err
+}
+
+func (r *PgVaultRepository) Delete(token string) error {
+	_, err := r.db.Exec(`DELETE FROM vault_records WHERE token = $1`, token)
+	return err
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PgVerificationRepository struct {
+	db *sql.DB
+}
+
+func NewPgVerificationRepository(db *sql.DB) repositories.VerificationRepository {
+	return &PgVerificationRepository{db: db}
+}
+
+const verificationColumns = `id, user_id, status, photo_key, passport_key, reviewer, reason, submitted_at, updated_at, reviewed_at`
+
+func scanVerification(row interface{ Scan(...interface{}) error }) (*entities.VerificationRequest, error) {
+	v := &entities.VerificationRequest{}
+	var reviewer, reason sql.NullString
+	var reviewedAt sql.NullTime
+	err := row.Scan(&v.ID, &v.UserID, &v.Status, &v.PhotoKey, &v.PassportKey, &reviewer, &reason,
+		&v.SubmittedAt, &v.UpdatedAt, &reviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	v.Reviewer = reviewer.String
+	v.Reason = reason.String
+	if reviewedAt.Valid {
+		v.ReviewedAt = &reviewedAt.Time
+	}
+	return v, nil
+}
+
+func (r *PgVerificationRepository) findOne(query string, args ...interface{}) (*entities.VerificationRequest, error) {
+	request, err := scanVerification(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+func (r *PgVerificationRepository) FindByID(id uuid.UUID) (*entities.VerificationRequest, error) {
+	return r.findOne(`SELECT `+verificationColumns+` FROM verification_requests WHERE id = $1`, id)
+}
+
+func (r *PgVerificationRepository) FindLatestByUserID(userID int64) (*entities.VerificationRequest, error) {
+	return r.findOne(`SELECT `+verificationColumns+` FROM verification_requests WHERE user_id = $1
+		ORDER BY submitted_at DESC LIMIT 1`, userID)
+}
+
+func (r *PgVerificationRepository) FindDueForDelivery(staleBefore time.Time) ([]*entities.VerificationRequest, error) {
+	query := `SELECT ` + verificationColumns + ` FROM verification_requests
+		WHERE status = $1 OR (status = $2 AND updated_at < $3) ORDER BY submitted_at`
+	rows, err := r.db.Query(query, entities.VerificationSubmitted, entities.VerificationDelivering, staleBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*entities.VerificationRequest
+	for rows.Next() {
+		request, err := scanVerification(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
+
+func (r *PgVerificationRepository) Create(request *entities.VerificationRequest) error {
+	if request.ID == uuid.Nil {
+		request.ID = uuid.New()
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO verification_requests (` + verificationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10)`
+	if _, err := tx.Exec(query, request.ID, request.UserID, request.Status, request.PhotoKey, request.PassportKey,
+		request.Reviewer, request.Reason, request.SubmittedAt, request.UpdatedAt, request.ReviewedAt); err != nil {
+		return err
+	}
+	if err := insertVerificationEvent(tx, request); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PgVerificationRepository) ClaimForDelivery(request *entities.VerificationRequest, staleBefore time.Time) (bool, error) {
+	return r.update(request, `(status = $7 OR (status = $8 AND updated_at < $9))`,
+		entities.VerificationSubmitted, entities.VerificationDelivering, staleBefore)
+}
+
+func (r *PgVerificationRepository) Transition(request *entities.VerificationRequest, from entities.VerificationStatus) (bool, error) {
+	return r.update(request, `status = $7`, from)
+}
+
+// update saves the request and records an event if the request matches the condition,
+// whose arguments start at $7.
+func (r *PgVerificationRepository) update(request *entities.VerificationRequest, condition string, args ...interface{}) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE verification_requests SET status = $2, reviewer = NULLIF($3, ''), reason = NULLIF($4, ''),
+		updated_at = $5, reviewed_at = $6 WHERE id = $1 AND ` + condition
+	args = append([]interface{}{request.ID, request.Status, request.Reviewer, request.Reason, request.UpdatedAt, request.ReviewedAt}, args...)
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		return false, err
+	}
+	if err := insertVerificationEvent(tx, request); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// insertVerificationEvent records the request's current status in its history.
+func insertVerificationEvent(tx *sql.Tx, request *entities.VerificationRequest) error {
+	query := `INSERT INTO verification_events (id, request_id, status, reviewer, reason, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6)`
+	_, err := tx.Exec(query, uuid.New(), request.ID, request.Status, request.Reviewer, request.Reason, request.UpdatedAt)
+	return err
+}
+
+func (r *PgVerificationRepository) FindEvents(requestID uuid.UUID) ([]*entities.VerificationEvent, error) {
+	query := `SELECT id, request_id, status, reviewer, reason, created_at FROM verification_events
+		WHERE request_id = $1 ORDER BY created_at, id`
+	rows, err := r.db.Query(query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*entities.VerificationEvent
+	for rows.Next() {
+		event := &entities.VerificationEvent{}
+		var reviewer, reason sql.NullString
+		if err := rows.Scan(&event.ID, &event.RequestID, &event.Status, &reviewer, &reason, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.Reviewer = reviewer.String
+		event.Reason = reason.String
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,89 @@
+package payments
+
+import (
+	"errors"
+	"net/http"
+	"tribute-back/internal/domain/money"
+)
+
+// ErrNotSupported is returned by providers for operations they don't offer.
+var ErrNotSupported = errors.New("operation not supported by the payment provider")
+
+// ErrInvalidWebhook is returned when a webhook can't be authenticated or parsed.
+var ErrInvalidWebhook = errors.New("invalid payment webhook")
+
+// ChargeStatus is the provider's view of a charge.
+type ChargeStatus string
+
+const (
+	// ChargePending awaits payment or confirmation; the outcome arrives later as an Event.
+	ChargePending   ChargeStatus = "pending"
+	ChargeSucceeded ChargeStatus = "succeeded"
+	ChargeFailed    ChargeStatus = "failed"
+	ChargeRefunded  ChargeStatus = "refunded"
+)
+
+// ChargeRequest asks a provider to collect money from a payer.
+type ChargeRequest struct {
+	// Reference identifies the payment on our side and is echoed back in events
+	Reference   string
+	PayerID     int64
+	Amount      money.Money
+	Title       string
+	Description string
+	// NotifyPayer asks the provider to also send the payment request to the payer directly, if it can
+	NotifyPayer bool
+}
+
+// Charge is a provider's record of a payment attempt.
+type Charge struct {
+	// ID is assigned by the provider. It may be empty until the charge succeeds.
+	ID        string
+	Reference string
+	Status    ChargeStatus
+	Amount    money.Money
+	// CheckoutURL is where the payer completes the payment, if the provider needs them to
+	CheckoutURL   string
+	FailureReason string
+}
+
+// Refund is money returned to the payer of a charge.
+type Refund struct {
+	ID       string
+	ChargeID string
+	Amount   money.Money
+}
+
+// EventType is the kind of change a provider reports asynchronously.
+type EventType string
+
+const (
+	EventChargeSucceeded EventType = "charge.succeeded"
+	EventChargeFailed    EventType = "charge.failed"
+	EventChargeRefunded  EventType = "charge.refunded"
+)
+
+// Event is a change to a charge reported by a provider after CreateCharge returned.
+type Event struct {
+	Type      EventType
+	ChargeID  string
+	Reference string
+	// RefundID is the provider's ID of the refund a charge.refunded event reports, if it has one
+	RefundID string
+	// Amount is what was charged, or for charge.refunded what was returned; zero means all of it
+	Amount        money.Money
+	FailureReason string
+}
+
+// Provider collects money from payers.
+type Provider interface {
+	// Name is the key the provider is registered and addressed by in webhook URLs.
+	Name() string
+	CreateCharge(req ChargeRequest) (*Charge, error)
+	// Capture confirms a pending charge.
+	Capture(chargeID string) (*Charge, error)
+	// Refund returns amount of a succeeded charge to its payer.
+	Refund(chargeID string, payerID int64, amount money.Money) (*Refund, error)
+	// ParseWebhook authenticates a webhook request and extracts the event it carries.
+	ParseWebhook(header http.Header, body []byte) (*Event, error)
+}
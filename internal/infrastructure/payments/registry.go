@@ -0,0 +1,33 @@
+package payments
+
+import "fmt"
+
+// Registry holds the configured payment providers and the one new charges go through.
+type Registry struct {
+	providers map[string]Provider
+	active    string
+}
+
+// NewRegistry registers providers and selects the active one by name.
+func NewRegistry(active string, providers ...Provider) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider), active: active}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	if _, ok := r.providers[active]; !ok {
+		return nil, fmt.Errorf("unknown payment provider %q", active)
+	}
+	return r, nil
+}
+
+// Active returns the provider used for new charges.
+func (r *Registry) Active() Provider {
+	return r.providers[r.active]
+}
+
+// Get returns a provider by name. Payments keep being handled by the provider
+// that created them, even after the active provider changes.
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
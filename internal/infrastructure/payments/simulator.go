@@ -0,0 +1,203 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+	"tribute-back/internal/domain/money"
+)
+
+// SimulatorProviderName is the name of the in-process payment simulator.
+const SimulatorProviderName = "simulator"
+
+// SimulatorSignatureHeader carries the hex HMAC-SHA256 of a simulator webhook body.
+const SimulatorSignatureHeader = "X-Simulator-Signature"
+
+// Magic amounts, matched against the last two digits of the amount in minor units.
+// Every other amount succeeds immediately.
+const (
+	// SimulatorDeclineSuffix makes the charge fail immediately, e.g. 199.02 RUB.
+	SimulatorDeclineSuffix = 2
+	// SimulatorDelaySuffix leaves the charge pending until it is captured, confirmed
+	// by a webhook or the configured delay passes, e.g. 199.03 RUB.
+	SimulatorDelaySuffix = 3
+)
+
+// Simulator is a deterministic in-process payment provider for local development
+// and offline tests. The outcome of a charge is decided by its amount.
+type Simulator struct {
+	mu      sync.Mutex
+	charges map[string]*Charge
+	refunds map[string][]*Refund
+	// secret signs webhook bodies; without it all webhooks are rejected
+	secret string
+	// delay is how long delayed charges wait before confirming themselves; zero waits forever
+	delay  time.Duration
+	notify func(*Event)
+}
+
+func NewSimulator(secret string, delay time.Duration) *Simulator {
+	return &Simulator{
+		charges: make(map[string]*Charge),
+		refunds: make(map[string][]*Refund),
+		secret:  secret,
+		delay:   delay,
+	}
+}
+
+// SetNotifier sets the function that receives events for delayed charges, the same
+// way a real provider would deliver them by webhook.
+func (s *Simulator) SetNotifier(notify func(*Event)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notify = notify
+}
+
+func (s *Simulator) Name() string {
+	return SimulatorProviderName
+}
+
+// CreateCharge charges the payer. Charge IDs are derived from the reference, so
+// creating the same charge twice returns the existing one.
+func (s *Simulator) CreateCharge(req ChargeRequest) (*Charge, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("charge amount must be positive, got %s", req.Amount)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := "sim_ch_" + req.Reference
+	if charge, ok := s.charges[id]; ok {
+		snapshot := *charge
+		return &snapshot, nil
+	}
+
+	charge := &Charge{ID: id, Reference: req.Reference, Amount: req.Amount, Status: ChargeSucceeded}
+	switch req.Amount.Amount % 100 {
+	case SimulatorDeclineSuffix:
+		charge.Status = ChargeFailed
+		charge.FailureReason = "card_declined"
+	case SimulatorDelaySuffix:
+		charge.Status = ChargePending
+		if s.delay > 0 {
+			time.AfterFunc(s.delay, func() {
+				if _, err := s.Capture(id); err != nil {
+					log.Printf("Simulator failed to confirm charge %s: %v", id, err)
+				}
+			})
+		}
+	}
+	s.charges[id] = charge
+
+	snapshot := *charge
+	return &snapshot, nil
+}
+
+// Capture confirms a pending charge and reports it as succeeded.
+func (s *Simulator) Capture(chargeID string) (*Charge, error) {
+	s.mu.Lock()
+	charge, ok := s.charges[chargeID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("charge %s not found", chargeID)
+	}
+	if charge.Status != ChargePending {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("charge %s is %s", chargeID, charge.Status)
+	}
+	charge.Status = ChargeSucceeded
+	snapshot := *charge
+	notify := s.notify
+	s.mu.Unlock()
+
+	if notify != nil {
+		notify(&Event{Type: EventChargeSucceeded, ChargeID: snapshot.ID, Reference: snapshot.Reference, Amount: snapshot.Amount})
+	}
+	return &snapshot, nil
+}
+
+// Refund returns part or all of a succeeded charge.
+func (s *Simulator) Refund(chargeID string, payerID int64, amount money.Money) (*Refund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	charge, ok := s.charges[chargeID]
+	if !ok {
+		return nil, fmt.Errorf("charge %s not found", chargeID)
+	}
+	if charge.Status != ChargeSucceeded {
+		return nil, fmt.Errorf("charge %s is %s", chargeID, charge.Status)
+	}
+
+	refunded := money.Zero(charge.Amount.Currency)
+	for _, r := range s.refunds[chargeID] {
+		refunded, _ = refunded.Add(r.Amount)
+	}
+	remaining, err := charge.Amount.Sub(refunded)
+	if err != nil {
+		return nil, err
+	}
+	if !amount.SameCurrency(remaining) || !amount.IsPositive() || amount.Amount > remaining.Amount {
+		return nil, fmt.Errorf("cannot refund %s of charge %s, %s remaining", amount, chargeID, remaining)
+	}
+
+	refund := &Refund{
+		ID:       fmt.Sprintf("sim_re_%s_%d", chargeID, len(s.refunds[chargeID])+1),
+		ChargeID: chargeID,
+		Amount:   amount,
+	}
+	s.refunds[chargeID] = append(s.refunds[chargeID], refund)
+	if amount.Amount == remaining.Amount {
+		charge.Status = ChargeRefunded
+	}
+	return refund, nil
+}
+
+// simulatorEvent is the webhook body accepted by the simulator.
+type simulatorEvent struct {
+	Type          EventType `json:"type"`
+	ChargeID      string    `json:"charge_id"`
+	Reference     string    `json:"reference"`
+	RefundID      string    `json:"refund_id,omitempty"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+}
+
+// ParseWebhook accepts events signed with the simulator secret. It lets tests and
+// scripts drive delayed confirmations and failures over HTTP.
+func (s *Simulator) ParseWebhook(header http.Header, body []byte) (*Event, error) {
+	if s.secret == "" {
+		return nil, fmt.Errorf("%w: simulator webhook secret is not configured", ErrInvalidWebhook)
+	}
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write(body)
+	signature, err := hex.DecodeString(header.Get(SimulatorSignatureHeader))
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidWebhook)
+	}
+
+	var wire simulatorEvent
+	if err := json.Unmarshal(body, &wire); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	if err := money.ValidateCurrency(wire.Currency); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+
+	return &Event{
+		Type:          wire.Type,
+		ChargeID:      wire.ChargeID,
+		Reference:     wire.Reference,
+		RefundID:      wire.RefundID,
+		Amount:        money.New(wire.Amount, wire.Currency),
+		FailureReason: wire.FailureReason,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package payments
+
+import (
+	"fmt"
+	"net/http"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/telegram"
+)
+
+// TelegramProviderName is the name of the Telegram invoice provider.
+const TelegramProviderName = "telegram"
+
+// Telegram limits for invoice texts, in characters
+const (
+	invoiceTitleLimit       = 32
+	invoiceDescriptionLimit = 255
+)
+
+// TelegramProvider collects payments with Telegram invoices (Telegram Stars, or a
+// fiat provider connected in @BotFather). The outcome isn't reported through a
+// payments webhook but through bot updates: pre_checkout_query and successful_payment.
+type TelegramProvider struct {
+	bot *telegram.BotService
+}
+
+func NewTelegramProvider(bot *telegram.BotService) *TelegramProvider {
+	return &TelegramProvider{bot: bot}
+}
+
+func (p *TelegramProvider) Name() string {
+	return TelegramProviderName
+}
+
+// CreateCharge creates an invoice link. The charge stays pending until the payer pays it.
+func (p *TelegramProvider) CreateCharge(req ChargeRequest) (*Charge, error) {
+	invoice := telegram.Invoice{
+		Title:       truncate(req.Title, invoiceTitleLimit),
+		Description: truncate(req.Description, invoiceDescriptionLimit),
+		Payload:     req.Reference,
+		Currency:    req.Amount.Currency,
+		Prices:      []telegram.LabeledPrice{{Label: truncate(req.Title, invoiceTitleLimit), Amount: req.Amount.Amount}},
+	}
+
+	link, err := p.bot.CreateInvoiceLink(invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.NotifyPayer {
+		// The link alone is enough to pay, so a failed message is not fatal
+		if err := p.bot.SendInvoice(req.PayerID, invoice); err != nil {
+			fmt.Printf("Failed to send invoice %s to user %d: %v\n", req.Reference, req.PayerID, err)
+		}
+	}
+
+	return &Charge{
+		Reference:   req.Reference,
+		Status:      ChargePending,
+		Amount:      req.Amount,
+		CheckoutURL: link,
+	}, nil
+}
+
+// Capture is not supported: Telegram captures invoice payments itself.
+func (p *TelegramProvider) Capture(chargeID string) (*Charge, error) {
+	return nil, ErrNotSupported
+}
+
+// Refund returns a Telegram Stars payment in full. Fiat payments have to be refunded
+// through the connected provider's dashboard.
+func (p *TelegramProvider) Refund(chargeID string, payerID int64, amount money.Money) (*Refund, error) {
+	if amount.Currency != telegram.StarsCurrency {
+		return nil, fmt.Errorf("%w: only Telegram Stars payments can be refunded by the bot", ErrNotSupported)
+	}
+	if err := p.bot.RefundStarPayment(payerID, chargeID); err != nil {
+		return nil, err
+	}
+	return &Refund{ID: chargeID, ChargeID: chargeID, Amount: amount}, nil
+}
+
+// ParseWebhook is not supported: Telegram reports payments as bot updates.
+func (p *TelegramProvider) ParseWebhook(header http.Header, body []byte) (*Event, error) {
+	return nil, ErrNotSupported
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
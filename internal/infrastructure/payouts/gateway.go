@@ -1,10 +1,17 @@
 package payouts
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"tribute-back/internal/domain/money"
 )
 
+// ErrPayoutRejected is returned by CreatePayout when the gateway refuses a payout for good.
+// Any other error is treated as temporary and the payout is submitted again later.
+var ErrPayoutRejected = errors.New("payout rejected by gateway")
+
 // CardDetails holds the necessary (but sensitive) card information.
 // In a real application, this should never be stored.
 type CardDetails struct {
@@ -13,17 +20,76 @@ type CardDetails struct {
 	CardCVV    string
 }
 
+// PayoutStatus is the gateway's view of a payout.
+type PayoutStatus string
+
+const (
+	PayoutProcessing PayoutStatus = "processing"
+	PayoutPaid       PayoutStatus = "paid"
+	PayoutFailed     PayoutStatus = "failed"
+)
+
+// PayoutRequest asks the gateway to send money to a user's card.
+type PayoutRequest struct {
+	// Reference identifies the payout on our side; gateways use it to deduplicate requests
+	Reference  string
+	UserID     int64
+	Amount     money.Money
+	CardNumber string
+}
+
+// PayoutResult is the gateway's record of a payout.
+type PayoutResult struct {
+	ID            string
+	Status        PayoutStatus
+	FailureReason string
+}
+
 // Gateway defines the interface for a payout provider.
 type Gateway interface {
 	RegisterPayoutMethod(userID int64, details CardDetails) error
+	CreatePayout(req PayoutRequest) (*PayoutResult, error)
+	GetPayoutStatus(payoutID string) (*PayoutResult, error)
+}
+
+// MockFailureMode selects how MockGateway misbehaves.
+type MockFailureMode string
+
+const (
+	// MockNoFailure pays every payout after MockConfig.ProcessingPolls status checks.
+	MockNoFailure MockFailureMode = "none"
+	// MockReject refuses every payout when it is created.
+	MockReject MockFailureMode = "reject"
+	// MockUnavailable fails every CreatePayout call with a temporary error.
+	MockUnavailable MockFailureMode = "unavailable"
+	// MockFail accepts payouts and fails them once they finish processing.
+	MockFail MockFailureMode = "fail"
+	// MockStuck accepts payouts and never finishes processing them.
+	MockStuck MockFailureMode = "stuck"
+)
+
+// MockConfig configures MockGateway.
+type MockConfig struct {
+	FailureMode MockFailureMode
+	// ProcessingPolls is how many status checks report a payout as processing before it settles
+	ProcessingPolls int
+}
+
+type mockPayout struct {
+	result PayoutResult
+	polls  int
 }
 
 // MockGateway is a simulated implementation of a payment gateway.
-type MockGateway struct{}
+type MockGateway struct {
+	cfg     MockConfig
+	mu      sync.Mutex
+	payouts map[string]*mockPayout
+}
 
 // NewMockGateway creates a new mock gateway.
-func NewMockGateway() Gateway {
-	return &MockGateway{}
+func NewMockGateway(cfg MockConfig) Gateway {
+	return &MockGateway{cfg: cfg, payouts: make(map[string]*mockPayout)}
 }
 
 // RegisterPayoutMethod simulates registering a user's card with a third-party service.
@@ -41,3 +107,55 @@ func (g *MockGateway) RegisterPayoutMethod(userID int64, details CardDetails) er
 
 	return nil
 }
+
+// CreatePayout simulates submitting a payout. Submitting the same reference twice
+// returns the existing payout.
+func (g *MockGateway) CreatePayout(req PayoutRequest) (*PayoutResult, error) {
+	switch g.cfg.FailureMode {
+	case MockReject:
+		return nil, fmt.Errorf("%w: mock gateway rejects all payouts", ErrPayoutRejected)
+	case MockUnavailable:
+		return nil, fmt.Errorf("mock gateway error: service unavailable")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := "mock_po_" + req.Reference
+	if payout, ok := g.payouts[id]; ok {
+		result := payout.result
+		return &result, nil
+	}
+
+	log.Printf("Simulating payout of %s to user %d", req.Amount, req.UserID)
+	payout := &mockPayout{result: PayoutResult{ID: id, Status: PayoutProcessing}}
+	g.payouts[id] = payout
+	result := payout.result
+	return &result, nil
+}
+
+// GetPayoutStatus simulates checking a payout. Payouts settle after the configured number of checks.
+func (g *MockGateway) GetPayoutStatus(payoutID string) (*PayoutResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payout, ok := g.payouts[payoutID]
+	if !ok {
+		return nil, fmt.Errorf("mock gateway error: payout %s not found", payoutID)
+	}
+
+	if payout.result.Status == PayoutProcessing && g.cfg.FailureMode != MockStuck {
+		payout.polls++
+		if payout.polls > g.cfg.ProcessingPolls {
+			if g.cfg.FailureMode == MockFail {
+				payout.result.Status = PayoutFailed
+				payout.result.FailureReason = "card_declined"
+			} else {
+				payout.result.Status = PayoutPaid
+			}
+		}
+	}
+
+	result := payout.result
+	return &result, nil
+}
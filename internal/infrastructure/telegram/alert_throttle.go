@@ -0,0 +1,65 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AlertThrottle limits how often alerts about the same subject, e.g. a client IP, reach the
+// admin chat.
+type AlertThrottle interface {
+	// Allow reports whether an alert about key may be sent, and if so holds back further
+	// alerts about it for the throttle window.
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// RedisAlertThrottle keeps one expiring Redis key per throttled subject, so that the window
+// is shared by every instance of the server.
+type RedisAlertThrottle struct {
+	client *redis.Client
+	prefix string
+	window time.Duration
+}
+
+// NewRedisAlertThrottle creates an alert throttle backed by Redis.
+func NewRedisAlertThrottle(client *redis.Client, prefix string, window time.Duration) AlertThrottle {
+	return &RedisAlertThrottle{client: client, prefix: prefix, window: window}
+}
+
+func (t *RedisAlertThrottle) Allow(ctx context.Context, key string) (bool, error) {
+	return t.client.SetNX(ctx, t.prefix+key, 1, t.window).Result()
+}
+
+// MemoryAlertThrottle keeps the throttle window in process memory. It is used when Redis is
+// unavailable; every instance then throttles on its own.
+type MemoryAlertThrottle struct {
+	mu     sync.Mutex
+	window time.Duration
+	until  map[string]time.Time
+}
+
+// NewMemoryAlertThrottle creates an in-memory alert throttle.
+func NewMemoryAlertThrottle(window time.Duration) AlertThrottle {
+	return &MemoryAlertThrottle{window: window, until: make(map[string]time.Time)}
+}
+
+func (t *MemoryAlertThrottle) Allow(ctx context.Context, key string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if until, ok := t.until[key]; ok && now.Before(until) {
+		return false, nil
+	}
+	// Forget expired subjects so that the map doesn't grow with every IP ever seen
+	for subject, until := range t.until {
+		if !now.Before(until) {
+			delete(t.until, subject)
+		}
+	}
+	t.until[key] = now.Add(t.window)
+	return true, nil
+}
@@ -2,39 +2,178 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 	"tribute-back/internal/config"
+	"unicode"
 )
 
 // BotService handles interactions with the Telegram Bot API.
 type BotService struct {
 	token       string
+	apiURL      string
 	client      *http.Client
 	adminChatID string
+	// paymentProviderToken is required for invoices in anything but Telegram Stars
+	paymentProviderToken string
+	botUsername          string
+	miniAppName          string
+	// botID is the bot's own user ID, loaded on first use
+	botID   int64
+	botIDMu sync.Mutex
 }
 
 // NewBotService creates a new instance of the BotService.
+// TELEGRAM_API_URL can point the service at a local Bot API server or a fake one in tests.
 func NewBotService() (*BotService, error) {
-	token := config.GetEnv("TELEGRAM_BOT_TOKEN", "")
-	if token == "" {
+	cfg := config.GetTelegramConfig()
+	if cfg.BotToken == "" {
 		return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN environment variable not set")
 	}
-	adminChatID := config.GetEnv("TELEGRAM_ADMIN_CHAT_ID", "")
-	if adminChatID == "" {
+	if cfg.AdminChatID == "" {
 		return nil, fmt.Errorf("TELEGRAM_ADMIN_CHAT_ID environment variable not set")
 	}
 
 	return &BotService{
-		token:       token,
-		client:      &http.Client{},
-		adminChatID: adminChatID,
+		token:                cfg.BotToken,
+		apiURL:               strings.TrimRight(cfg.APIURL, "/"),
+		client:               &http.Client{},
+		adminChatID:          cfg.AdminChatID,
+		paymentProviderToken: cfg.PaymentProviderToken,
+		botUsername:          strings.TrimPrefix(cfg.BotUsername, "@"),
+		miniAppName:          cfg.MiniAppName,
 	}, nil
 }
 
+// IsCommand reports whether a message is the bot command, e.g. /refund, or the command
+// addressed to this bot the way clients send it in groups, e.g. /refund@botname.
+func (s *BotService) IsCommand(text, command string) bool {
+	name := text
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		name = text[:i]
+	}
+	name, mention, addressed := strings.Cut(name, "@")
+	if name != command {
+		return false
+	}
+	// Without a configured username there is no telling which bot a command is addressed to
+	return !addressed || s.botUsername == "" || strings.EqualFold(mention, s.botUsername)
+}
+
+// StartAppLink returns the t.me link that opens the Mini App with the given start_param.
+func (s *BotService) StartAppLink(startParam string) (string, error) {
+	if s.botUsername == "" {
+		return "", fmt.Errorf("TELEGRAM_BOT_USERNAME environment variable not set")
+	}
+	if s.miniAppName != "" {
+		return fmt.Sprintf("https://t.me/%s/%s?startapp=%s", s.botUsername, s.miniAppName, startParam), nil
+	}
+	return fmt.Sprintf("https://t.me/%s?startapp=%s", s.botUsername, startParam), nil
+}
+
+// methodURL returns the Bot API endpoint for the given method.
+func (s *BotService) methodURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", s.apiURL, s.token, method)
+}
+
+// APIError is an error response of the Bot API, as opposed to a failure to reach it.
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram api error on %s (%d): %s", e.Method, e.Code, e.Description)
+}
+
+// Temporary reports whether the request may succeed if retried later, i.e. Telegram was
+// overloaded or asked to slow down rather than refusing the request.
+func (e *APIError) Temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+}
+
+// apiResponse is the envelope every Bot API method responds with.
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+// callMethod posts a JSON payload to a Bot API method and decodes the result into result, if given.
+func (s *BotService) callMethod(method string, payload interface{}, result interface{}) error {
+	return s.callMethodContext(context.Background(), method, payload, result)
+}
+
+// callMethodContext is callMethod bound to ctx, used for long-running calls such as getUpdates.
+func (s *BotService) callMethodContext(ctx context.Context, method string, payload interface{}, result interface{}) error {
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.methodURL(method), bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram api request %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var response apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode %s response (%d): %w", method, resp.StatusCode, err)
+	}
+	if !response.OK {
+		return &APIError{Method: method, Code: response.ErrorCode, Description: response.Description}
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("failed to parse %s result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// IsAdminChat reports whether chatID is the configured admin chat.
+func (s *BotService) IsAdminChat(chatID int64) bool {
+	return strconv.FormatInt(chatID, 10) == s.adminChatID
+}
+
+// SendAdminMessage sends a text message to the configured admin chat.
+func (s *BotService) SendAdminMessage(text string) error {
+	chatID, err := strconv.ParseInt(s.adminChatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid admin chat ID %q: %w", s.adminChatID, err)
+	}
+	return s.SendMessage(chatID, text)
+}
+
+// AnswerCallbackQuery acknowledges an inline button press, optionally showing text to the user.
+func (s *BotService) AnswerCallbackQuery(callbackQueryID, text string) error {
+	body := map[string]interface{}{
+		"callback_query_id": callbackQueryID,
+	}
+	if text != "" {
+		body["text"] = text
+	}
+	return s.callMethod("answerCallbackQuery", body, nil)
+}
+
 // InlineKeyboardButton represents a single button in an inline keyboard.
 type InlineKeyboardButton struct {
 	Text         string `json:"text"`
@@ -61,7 +200,7 @@ func (s *BotService) sendPhoto(chatID string, photo io.Reader, caption string) e
 	w.WriteField("caption", caption)
 	w.Close()
 
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendPhoto", s.token)
+	url := s.methodURL("sendPhoto")
 	req, err := http.NewRequest("POST", url, &b)
 	if err != nil {
 		return err
@@ -82,7 +221,8 @@ func (s *BotService) sendPhoto(chatID string, photo io.Reader, caption string) e
 }
 
 // SendVerificationRequest sends the user's documents to the admin chat with action buttons.
-func (s *BotService) SendVerificationRequest(userID int64, userPhoto io.Reader, userPassport io.Reader) error {
+// The buttons carry the ID of the verification request they decide.
+func (s *BotService) SendVerificationRequest(requestID string, userID int64, userPhoto io.Reader, userPassport io.Reader) error {
 	if err := s.sendPhoto(s.adminChatID, userPhoto, fmt.Sprintf("User Photo for UserID: %d", userID)); err != nil {
 		return fmt.Errorf("failed to send user photo: %w", err)
 	}
@@ -91,14 +231,15 @@ func (s *BotService) SendVerificationRequest(userID int64, userPhoto io.Reader,
 	}
 
 	// Send the message with inline keyboard for actions
-	text := fmt.Sprintf("Please verify user with ID: %d", userID)
-	callbackApprove := fmt.Sprintf("verify_approve_%d", userID)
-	callbackReject := fmt.Sprintf("verify_reject_%d", userID)
+	text := fmt.Sprintf("Please verify user with ID: %d\nRequest: %s", userID, requestID)
 	keyboard := InlineKeyboardMarkup{
 		InlineKeyboard: [][]InlineKeyboardButton{
 			{
-				{Text: "Подтвердить", CallbackData: callbackApprove},
-				{Text: "Отклонить", CallbackData: callbackReject},
+				{Text: "Подтвердить", CallbackData: "verify_approve_" + requestID},
+				{Text: "Отклонить", CallbackData: "verify_reject_" + requestID},
+			},
+			{
+				{Text: "Запросить документы заново", CallbackData: "verify_resubmit_" + requestID},
 			},
 		},
 	}
@@ -107,7 +248,7 @@ func (s *BotService) SendVerificationRequest(userID int64, userPhoto io.Reader,
 		return err
 	}
 
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.token)
+	url := s.methodURL("sendMessage")
 	body := map[string]interface{}{
 		"chat_id":      s.adminChatID,
 		"text":         text,
@@ -130,7 +271,7 @@ func (s *BotService) SendVerificationRequest(userID int64, userPhoto io.Reader,
 
 // DeleteMessage deletes a message from a chat.
 func (s *BotService) DeleteMessage(chatID int64, messageID int) error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/deleteMessage", s.token)
+	url := s.methodURL("deleteMessage")
 	body := map[string]interface{}{
 		"chat_id":    chatID,
 		"message_id": messageID,
@@ -151,7 +292,7 @@ func (s *BotService) SendMessage(userID int64, text string) error {
 	fmt.Printf("Sending message to user %d: %s\n", userID, text)
 	fmt.Printf("Using bot token: %s...\n", s.token[:10]) // Show first 10 chars for debugging
 
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.token)
+	url := s.methodURL("sendMessage")
 	body := map[string]interface{}{
 		"chat_id": userID,
 		"text":    text,
@@ -180,61 +321,139 @@ func (s *BotService) SendMessage(userID int64, text string) error {
 	return nil
 }
 
-// ChatMember represents a member in a chat
+// ChatMember represents a member in a chat. The rights are only reported for administrators.
 type ChatMember struct {
 	Status string `json:"status"`
 	User   struct {
 		ID       int64  `json:"id"`
 		Username string `json:"username"`
 	} `json:"user"`
+	CanInviteUsers     bool `json:"can_invite_users"`
+	CanRestrictMembers bool `json:"can_restrict_members"`
 }
 
-// TelegramResponse represents a generic Telegram API response
-type TelegramResponse struct {
-	OK     bool        `json:"ok"`
-	Result interface{} `json:"result"`
+// GetChatMember returns a user's membership of a chat. For channels the bot must be an administrator.
+func (s *BotService) GetChatMember(chatID string, userID int64) (*ChatMember, error) {
+	var member ChatMember
+	if err := s.callMethod("getChatMember", map[string]interface{}{
+		"chat_id": chatID,
+		"user_id": userID,
+	}, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
 }
 
-// CheckChannelMembership checks if a user is a member of a channel and their role
-func (s *BotService) CheckChannelMembership(channelUsername string, userID int64) (*ChatMember, error) {
-	// Remove @ if present
-	if len(channelUsername) > 0 && channelUsername[0] == '@' {
-		channelUsername = channelUsername[1:]
+// BotUserID returns the bot's own user ID, asking Telegram the first time.
+func (s *BotService) BotUserID() (int64, error) {
+	s.botIDMu.Lock()
+	defer s.botIDMu.Unlock()
+	if s.botID != 0 {
+		return s.botID, nil
 	}
 
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/getChatMember", s.token)
-	body := map[string]interface{}{
-		"chat_id": "@" + channelUsername,
-		"user_id": userID,
+	var me struct {
+		ID int64 `json:"id"`
 	}
-	bodyBytes, _ := json.Marshal(body)
-
-	resp, err := s.client.Post(url, "application/json", bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to check channel membership: %w", err)
+	if err := s.callMethod("getMe", map[string]interface{}{}, &me); err != nil {
+		return 0, err
 	}
-	defer resp.Body.Close()
+	s.botID = me.ID
+	return s.botID, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("telegram api error on getChatMember (%d): %s", resp.StatusCode, string(respBody))
+// SetWebhook registers url as the webhook; Telegram will send secretToken in every request.
+func (s *BotService) SetWebhook(url, secretToken string) error {
+	return s.callMethod("setWebhook", map[string]interface{}{
+		"url":             url,
+		"secret_token":    secretToken,
+		"allowed_updates": AllowedUpdates,
+	}, nil)
+}
+
+// DeleteWebhook removes the webhook so that updates can be fetched with getUpdates.
+// Pending updates are kept.
+func (s *BotService) DeleteWebhook() error {
+	return s.callMethod("deleteWebhook", map[string]interface{}{
+		"drop_pending_updates": false,
+	}, nil)
+}
+
+// GetUpdates long-polls for updates starting at offset, waiting up to timeout for one to arrive.
+func (s *BotService) GetUpdates(ctx context.Context, offset int, timeout time.Duration) ([]Update, error) {
+	body := map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": AllowedUpdates,
 	}
 
-	var response TelegramResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	var updates []Update
+	if err := s.callMethodContext(ctx, "getUpdates", body, &updates); err != nil {
+		return nil, err
 	}
+	return updates, nil
+}
 
-	if !response.OK {
-		return nil, fmt.Errorf("telegram api returned error: %v", response)
+// ChatInviteLink represents an invite link created for a chat.
+type ChatInviteLink struct {
+	InviteLink  string `json:"invite_link"`
+	Name        string `json:"name,omitempty"`
+	ExpireDate  int64  `json:"expire_date,omitempty"`
+	MemberLimit int    `json:"member_limit,omitempty"`
+	IsRevoked   bool   `json:"is_revoked"`
+}
+
+// CreateChatInviteLink creates an invite link that stops working at expireDate
+// or after memberLimit users joined through it. The bot must be an admin with can_invite_users.
+func (s *BotService) CreateChatInviteLink(chatID string, name string, expireDate time.Time, memberLimit int) (*ChatInviteLink, error) {
+	body := map[string]interface{}{
+		"chat_id":      chatID,
+		"name":         name,
+		"expire_date":  expireDate.Unix(),
+		"member_limit": memberLimit,
 	}
 
-	// Parse the result as ChatMember
-	resultBytes, _ := json.Marshal(response.Result)
-	var chatMember ChatMember
-	if err := json.Unmarshal(resultBytes, &chatMember); err != nil {
-		return nil, fmt.Errorf("failed to parse chat member: %w", err)
+	var link ChatInviteLink
+	if err := s.callMethod("createChatInviteLink", body, &link); err != nil {
+		return nil, err
 	}
+	return &link, nil
+}
+
+// RevokeChatInviteLink invalidates an invite link created by the bot.
+func (s *BotService) RevokeChatInviteLink(chatID string, inviteLink string) error {
+	return s.callMethod("revokeChatInviteLink", map[string]interface{}{
+		"chat_id":     chatID,
+		"invite_link": inviteLink,
+	}, nil)
+}
 
-	return &chatMember, nil
+// BanChatMember removes a user from a chat and prevents them from rejoining until untilDate.
+// The bot must be an admin with can_restrict_members.
+func (s *BotService) BanChatMember(chatID string, userID int64, untilDate time.Time) error {
+	return s.callMethod("banChatMember", map[string]interface{}{
+		"chat_id":    chatID,
+		"user_id":    userID,
+		"until_date": untilDate.Unix(),
+	}, nil)
+}
+
+// UnbanChatMember lifts a ban so the user can join again through a new invite link.
+// Users that are not banned are left untouched.
+func (s *BotService) UnbanChatMember(chatID string, userID int64) error {
+	return s.callMethod("unbanChatMember", map[string]interface{}{
+		"chat_id":        chatID,
+		"user_id":        userID,
+		"only_if_banned": true,
+	}, nil)
+}
+
+// KickChatMember removes a user from a chat without leaving them banned.
+func (s *BotService) KickChatMember(chatID string, userID int64) error {
+	// Telegram has no kick method: a ban followed by an unban removes the user
+	// while still letting them come back later.
+	if err := s.BanChatMember(chatID, userID, time.Now().Add(time.Minute)); err != nil {
+		return err
+	}
+	return s.UnbanChatMember(chatID, userID)
 }
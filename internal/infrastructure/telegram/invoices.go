@@ -0,0 +1,84 @@
+package telegram
+
+import "fmt"
+
+// StarsCurrency is the currency code of Telegram Stars. Stars invoices don't need a payment provider.
+const StarsCurrency = "XTR"
+
+// LabeledPrice is a portion of an invoice's price, in the smallest units of the currency.
+type LabeledPrice struct {
+	Label  string `json:"label"`
+	Amount int64  `json:"amount"`
+}
+
+// Invoice describes what the user is asked to pay for. Payload is returned
+// unchanged in the pre-checkout query and the successful payment.
+type Invoice struct {
+	Title       string
+	Description string
+	Payload     string
+	Currency    string
+	Prices      []LabeledPrice
+}
+
+func (s *BotService) invoiceBody(invoice Invoice) (map[string]interface{}, error) {
+	body := map[string]interface{}{
+		"title":       invoice.Title,
+		"description": invoice.Description,
+		"payload":     invoice.Payload,
+		"currency":    invoice.Currency,
+		"prices":      invoice.Prices,
+	}
+	if invoice.Currency != StarsCurrency {
+		if s.paymentProviderToken == "" {
+			return nil, fmt.Errorf("invoices in %s need TELEGRAM_PAYMENT_PROVIDER_TOKEN", invoice.Currency)
+		}
+		body["provider_token"] = s.paymentProviderToken
+	}
+	return body, nil
+}
+
+// CreateInvoiceLink returns a link the user can open to pay the invoice.
+func (s *BotService) CreateInvoiceLink(invoice Invoice) (string, error) {
+	body, err := s.invoiceBody(invoice)
+	if err != nil {
+		return "", err
+	}
+
+	var link string
+	if err := s.callMethod("createInvoiceLink", body, &link); err != nil {
+		return "", err
+	}
+	return link, nil
+}
+
+// SendInvoice sends the invoice as a message to a chat.
+func (s *BotService) SendInvoice(chatID int64, invoice Invoice) error {
+	body, err := s.invoiceBody(invoice)
+	if err != nil {
+		return err
+	}
+	body["chat_id"] = chatID
+	return s.callMethod("sendInvoice", body, nil)
+}
+
+// AnswerPreCheckoutQuery confirms or rejects an order. errorMessage is shown to
+// the user when the order is rejected.
+func (s *BotService) AnswerPreCheckoutQuery(queryID string, ok bool, errorMessage string) error {
+	body := map[string]interface{}{
+		"pre_checkout_query_id": queryID,
+		"ok":                    ok,
+	}
+	if !ok {
+		body["error_message"] = errorMessage
+	}
+	return s.callMethod("answerPreCheckoutQuery", body, nil)
+}
+
+// RefundStarPayment returns a successful Telegram Stars payment to the user.
+func (s *BotService) RefundStarPayment(userID int64, telegramPaymentChargeID string) error {
+	return s.callMethod("refundStarPayment", map[string]interface{}{
+		"user_id":                    userID,
+		"telegram_payment_charge_id": telegramPaymentChargeID,
+	}, nil)
+}
@@ -0,0 +1,67 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// OffsetStore persists the getUpdates offset so that a restart neither replays
+// nor skips updates.
+type OffsetStore interface {
+	LoadOffset(ctx context.Context) (int, error)
+	SaveOffset(ctx context.Context, offset int) error
+}
+
+// RedisOffsetStore keeps the offset under a single Redis key.
+type RedisOffsetStore struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisOffsetStore creates an offset store backed by Redis.
+func NewRedisOffsetStore(client *redis.Client, key string) OffsetStore {
+	return &RedisOffsetStore{client: client, key: key}
+}
+
+func (s *RedisOffsetStore) LoadOffset(ctx context.Context) (int, error) {
+	value, err := s.client.Get(ctx, s.key).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+func (s *RedisOffsetStore) SaveOffset(ctx context.Context, offset int) error {
+	return s.client.Set(ctx, s.key, offset, 0).Err()
+}
+
+// MemoryOffsetStore keeps the offset in process memory. It is used when Redis is
+// unavailable; after a restart Telegram redelivers the updates it still holds.
+type MemoryOffsetStore struct {
+	mu     sync.Mutex
+	offset int
+}
+
+// NewMemoryOffsetStore creates an in-memory offset store.
+func NewMemoryOffsetStore() OffsetStore {
+	return &MemoryOffsetStore{}
+}
+
+func (s *MemoryOffsetStore) LoadOffset(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset, nil
+}
+
+func (s *MemoryOffsetStore) SaveOffset(ctx context.Context, offset int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset = offset
+	return nil
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// pollRetryDelay is how long the poller waits after a failed getUpdates call.
+const pollRetryDelay = 5 * time.Second
+
+// UpdateHandler processes a single update received from Telegram.
+type UpdateHandler func(update *Update) error
+
+// Poller pulls updates with getUpdates and feeds them to an UpdateHandler.
+// It is the alternative to the webhook for deployments that Telegram cannot reach.
+type Poller struct {
+	bot     *BotService
+	offsets OffsetStore
+	handle  UpdateHandler
+	timeout time.Duration
+}
+
+// NewPoller creates a poller that long-polls for up to timeout per request.
+func NewPoller(bot *BotService, offsets OffsetStore, handle UpdateHandler, timeout time.Duration) *Poller {
+	return &Poller{
+		bot:     bot,
+		offsets: offsets,
+		handle:  handle,
+		timeout: timeout,
+	}
+}
+
+// Run polls for updates until ctx is cancelled. A batch that is already being
+// handled is always finished and its offset saved before Run returns.
+func (p *Poller) Run(ctx context.Context) {
+	// getUpdates is refused by Telegram while a webhook is set.
+	if err := p.bot.DeleteWebhook(); err != nil {
+		log.Printf("Failed to delete Telegram webhook before polling: %v", err)
+	}
+
+	offset, err := p.offsets.LoadOffset(ctx)
+	if err != nil {
+		log.Printf("Failed to load Telegram update offset, starting from the oldest pending update: %v", err)
+	}
+
+	log.Printf("Polling Telegram for updates from offset %d", offset)
+	for ctx.Err() == nil {
+		updates, err := p.bot.GetUpdates(ctx, offset, p.timeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("Failed to get Telegram updates: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollRetryDelay):
+			}
+			continue
+		}
+
+		for i := range updates {
+			update := &updates[i]
+			if err := p.handle(update); err != nil {
+				log.Printf("Failed to handle Telegram update %d: %v", update.UpdateID, err)
+			}
+			offset = update.UpdateID + 1
+
+			// Saved with a fresh context so the final offset survives shutdown.
+			if err := p.offsets.SaveOffset(context.Background(), offset); err != nil {
+				log.Printf("Failed to save Telegram update offset %d: %v", offset, err)
+			}
+		}
+	}
+	log.Printf("Stopped polling Telegram updates at offset %d", offset)
+}
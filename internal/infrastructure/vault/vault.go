@@ -0,0 +1,184 @@
+// Package vault keeps secrets such as card numbers encrypted at rest.
+//
+// Every secret is encrypted with its own random data key using AES-256-GCM. The data key
+// is then encrypted ("wrapped") with a key encryption key from the configuration and
+// stored next to the ciphertext. Rotating the key encryption key only re-wraps data keys;
+// secrets themselves are never decrypted in the process.
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/repositories"
+)
+
+// ErrNotFound is returned when a token doesn't refer to a stored secret.
+var ErrNotFound = errors.New("vault: secret not found")
+
+const (
+	keySize    = 32
+	tokenBytes = 16
+	// tokenPrefix marks vault tokens so they are not mistaken for card numbers
+	tokenPrefix = "vt_"
+)
+
+// Vault encrypts, stores and decrypts secrets.
+type Vault struct {
+	records repositories.VaultRepository
+	keys    map[string][]byte
+	active  string
+}
+
+// New creates a vault from base64-encoded 256-bit key encryption keys. New secrets are
+// wrapped with the active key; the others are kept to read secrets until they are rotated.
+func New(records repositories.VaultRepository, keys map[string]string, active string) (*Vault, error) {
+	v := &Vault{records: records, keys: make(map[string][]byte), active: active}
+	for id, encoded := range keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("vault key %q is not valid base64: %w", id, err)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("vault key %q must be %d bytes, got %d", id, keySize, len(key))
+		}
+		v.keys[id] = key
+	}
+	if _, ok := v.keys[active]; !ok {
+		return nil, fmt.Errorf("active vault key %q is not configured", active)
+	}
+	return v, nil
+}
+
+// Store encrypts a secret and returns the token that refers to it.
+func (v *Vault) Store(secret []byte) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataKey, secret)
+	if err != nil {
+		return "", err
+	}
+	wrappedKey, err := seal(v.keys[v.active], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	record := &entities.VaultRecord{
+		Token:      tokenPrefix + hex.EncodeToString(raw),
+		KeyID:      v.active,
+		WrappedKey: wrappedKey,
+		Ciphertext: ciphertext,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := v.records.Create(record); err != nil {
+		return "", err
+	}
+	return record.Token, nil
+}
+
+// Reveal decrypts the secret a token refers to.
+func (v *Vault) Reveal(token string) ([]byte, error) {
+	record, err := v.records.FindByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrNotFound
+	}
+
+	dataKey, err := v.unwrap(record)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, record.Ciphertext)
+}
+
+// Delete destroys a secret.
+func (v *Vault) Delete(token string) error {
+	return v.records.Delete(token)
+}
+
+// Rotate re-wraps up to batch data keys that are not wrapped with the active key and
+// returns how many it re-wrapped. Once it returns 0, retired keys can be removed from
+// the configuration.
+func (v *Vault) Rotate(batch int) (int, error) {
+	records, err := v.records.FindNotWrappedWith(v.active, batch)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, record := range records {
+		dataKey, err := v.unwrap(record)
+		if err != nil {
+			return i, err
+		}
+		wrappedKey, err := seal(v.keys[v.active], dataKey)
+		if err != nil {
+			return i, err
+		}
+		record.KeyID = v.active
+		record.WrappedKey = wrappedKey
+		record.UpdatedAt = time.Now()
+		if err := v.records.UpdateKey(record); err != nil {
+			return i, err
+		}
+	}
+	return len(records), nil
+}
+
+func (v *Vault) unwrap(record *entities.VaultRecord) ([]byte, error) {
+	key, ok := v.keys[record.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("vault key %q for %s is not configured", record.KeyID, record.Token)
+	}
+	return open(key, record.WrappedKey)
+}
+
+// seal encrypts plaintext with AES-GCM and prepends the random nonce.
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open reverses seal.
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("vault: ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -1,6 +1,11 @@
 package dto
 
 import (
+	"time"
+	"tribute-back/internal/domain/card"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+
 	"github.com/google/uuid"
 )
 
@@ -33,18 +38,21 @@ type DashboardRequest struct {
 }
 
 type DashboardResponse struct {
-	Earn              float64      `json:"earn"`
-	ChannelsAndGroups []ChannelDTO `json:"channels-and-groups"`
-	IsVerified        bool         `json:"is-verified"`
-	Subscriptions     []SubDTO     `json:"subscriptions"`
-	IsSubPublished    bool         `json:"is-sub-published"`
-	PaymentsHistory   []PaymentDTO `json:"payments-history"`
-	CardNumber        string       `json:"card_number"`
+	Earn              MoneyDTO       `json:"earn"`
+	ChannelsAndGroups []ChannelDTO   `json:"channels-and-groups"`
+	IsVerified        bool           `json:"is-verified"`
+	Subscriptions     []SubDTO       `json:"subscriptions"`
+	IsSubPublished    bool           `json:"is-sub-published"`
+	PaymentsHistory   []PaymentDTO   `json:"payments-history"`
+	CardNumber        string         `json:"card_number" example:"**** **** **** 4242"` // Masked
+	PayoutCard        *PayoutCardDTO `json:"payout_card,omitempty"`
+	Referrals         []ReferralDTO  `json:"referrals"` // The user's referral links to other creators' channels
 }
 
 // AddBot
 type AddBotRequest struct {
-	UserID          int64  `json:"user_id" binding:"required"`
+	// UserID is only needed in requests signed by our services; from the Mini App the user comes from initData
+	UserID          int64  `json:"user_id,omitempty"`
 	ChannelTitle    string `json:"channel_title" binding:"required"`
 	ChannelUsername string `json:"channel_username" binding:"required"`
 }
@@ -56,7 +64,15 @@ type CheckChannelRequest struct {
 
 // CheckChannelResponse represents the response for channel ownership check
 type CheckChannelResponse struct {
-	IsOwner bool `json:"is_owner"`
+	IsOwner bool       `json:"is_owner"` // True once the channel is verified
+	Channel ChannelDTO `json:"channel"`  // The channel with its verification status and what is missing
+}
+
+// MissingPermissionDTO is something that must be fixed in Telegram before a channel can be verified.
+type MissingPermissionDTO struct {
+	Code        string `json:"code" example:"bot_can_invite_users"` // owner_is_admin, bot_is_admin, bot_can_invite_users or bot_can_restrict_members
+	Subject     string `json:"subject" example:"bot"`               // Who lacks it: owner or bot
+	Description string `json:"description" example:"Allow the bot to invite users via link"`
 }
 
 // UploadVerifiedPassport
@@ -70,6 +86,10 @@ type UploadVerifiedPassportRequest struct {
 type CheckVerifiedPassportRequest struct {
 	UserID        int64 `json:"userId" binding:"required"`
 	IsVerificated bool  `json:"isVerificated"`
+	// RequestID is the verification request the decision is about; without it the user's open request is decided
+	RequestID *uuid.UUID `json:"requestId,omitempty"`
+	// Reason is shown to the user when the request is rejected
+	Reason string `json:"reason,omitempty"`
 }
 
 type CheckVerifiedPassportResponse struct {
@@ -79,42 +99,418 @@ type CheckVerifiedPassportResponse struct {
 
 // SetUpPayouts
 type SetUpPayoutsRequest struct {
-	CardNumber string `json:"card-number" binding:"required"`
+	CardNumber string `json:"card-number" binding:"required" example:"4242 4242 4242 4242"`
+	CardExpiry string `json:"card-expiry" binding:"required" example:"12/27"` // MM/YY or MM/YYYY
 }
 
 // PublishSubscription
 type PublishSubscriptionRequest struct {
-	AccessToken string  `json:"access_token"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	ButtonText  string  `json:"button-text"`
-	Price       float64 `json:"price"`
+	AccessToken string    `json:"access_token"`
+	ChannelID   uuid.UUID `json:"channel_id" binding:"required"` // Verified channel the tier grants access to
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ButtonText  string    `json:"button-text"`
+	Price       string    `json:"price" binding:"required" example:"199.00"` // Decimal amount in major units
+	Currency    string    `json:"currency,omitempty" example:"RUB"`          // ISO 4217 code, defaults to the platform currency
 }
 
 // CreateSubscribe
 type CreateSubscribeRequest struct {
-	UserID int64   `json:"user_id"`
-	Price  float64 `json:"price"`
+	ChannelID   uuid.UUID  `json:"channel_id" binding:"required"`           // Channel whose tier to subscribe to
+	PriceID     *uuid.UUID `json:"price_id,omitempty"`                      // Tier price to pay; defaults to the channel's first tier price
+	PromoCode   string     `json:"promo_code,omitempty" example:"SPRING20"` // Case-insensitive promo code of the channel
+	SendInvoice bool       `json:"send_invoice,omitempty"`                  // Also send the invoice to the subscriber's chat with the bot
 }
 
-// --- Reusable DTOs ---
-type ChannelDTO struct {
-	ID              uuid.UUID `json:"id"`
+// StartTrialRequest starts a free trial of a channel.
+type StartTrialRequest struct {
+	ChannelID uuid.UUID  `json:"channel_id" binding:"required"` // Channel to try
+	PriceID   *uuid.UUID `json:"price_id,omitempty"`            // Tier price with trial days; defaults to the channel's first tier price
+}
+
+// MembershipDTO is a subscriber's access to a channel.
+type MembershipDTO struct {
+	ID               uuid.UUID  `json:"id"`
+	ChannelID        uuid.UUID  `json:"channel_id"`
+	ChannelTitle     string     `json:"channel_title,omitempty"`
+	ChannelUsername  string     `json:"channel_username,omitempty"`
+	TierID           uuid.UUID  `json:"tier_id"`
+	TierTitle        string     `json:"tier_title,omitempty"`
+	PriceID          *uuid.UUID `json:"price_id,omitempty"`
+	Price            *MoneyDTO  `json:"price,omitempty"`
+	BillingInterval  string     `json:"billing_interval,omitempty" example:"month"`
+	Status           string     `json:"status" example:"trialing"`
+	StartedAt        string     `json:"started_at"`
+	CurrentPeriodEnd string     `json:"current_period_end,omitempty"` // Empty for lifetime access
+	NextRenewalAt    string     `json:"next_renewal_at,omitempty"`    // Empty if the membership won't renew
+	CancelledAt      string     `json:"cancelled_at,omitempty"`
+	TrialEndsAt      string     `json:"trial_ends_at,omitempty"`
+	InviteLink       string     `json:"invite_link,omitempty"` // Only while the membership grants access
+}
+
+// MySubscriptionsResponse lists the channels a subscriber has or had access to.
+type MySubscriptionsResponse struct {
+	Subscriptions []MembershipDTO `json:"subscriptions"`
+}
+
+// ChannelOfferResponse is the public subscribe page of a channel.
+type ChannelOfferResponse struct {
+	ChannelID       uuid.UUID `json:"channel_id"` // Pass to /create-subscribe and /start-trial
 	ChannelTitle    string    `json:"channel_title"`
 	ChannelUsername string    `json:"channel_username"`
 	IsVerified      bool      `json:"is_verified"`
+	Tiers           []SubDTO  `json:"tiers"` // Tiers that can be bought, in display order; empty unless the channel is verified
+	// ReferralShareBPS is what referrers earn of the creator's earnings from subscribers they bring (1000 = 10%); 0 if the channel has no referral program
+	ReferralShareBPS int `json:"referral_share_bps" example:"1000"`
+}
+
+// StartLinkRequest creates a start link to a channel's offer.
+type StartLinkRequest struct {
+	Label   string     `json:"label" binding:"max=64" example:"Instagram bio"` // Tells the creator's links apart
+	PriceID *uuid.UUID `json:"price_id,omitempty"`                             // Price to preselect on the offer
+}
+
+// StartLinkDTO is a creator's start link with how often it was opened.
+type StartLinkDTO struct {
+	ID        uuid.UUID  `json:"id"`
+	Code      string     `json:"code" example:"q3Vx_9aB"`
+	URL       string     `json:"url" example:"https://t.me/tribute_bot?startapp=q3Vx_9aB"`
+	ChannelID uuid.UUID  `json:"channel_id"`
+	PriceID   *uuid.UUID `json:"price_id,omitempty"`
+	Label     string     `json:"label"`
+	Clicks    int        `json:"clicks"`   // Launches of the Mini App through the link
+	Visitors  int        `json:"visitors"` // Different users who opened it
+	CreatedAt string     `json:"created_at"`
+}
+
+// StartLinksResponse lists the start links of a channel, newest first.
+type StartLinksResponse struct {
+	StartLinks []StartLinkDTO `json:"start_links"`
+}
+
+// StartOfferResponse is the offer a start link opens.
+type StartOfferResponse struct {
+	Code    string               `json:"code"`
+	PriceID *uuid.UUID           `json:"price_id,omitempty"` // Price the link preselects
+	Offer   ChannelOfferResponse `json:"offer"`
+}
+
+// ReferralProgramRequest sets up a channel's referral program.
+type ReferralProgramRequest struct {
+	ShareBPS *int `json:"share_bps" binding:"required" example:"1000"` // Share of the creator's earnings (after the platform commission) paid to referrers, in basis points; 0 ends the program
+}
+
+// ReferralDTO is a referral link on the referrer's dashboard with what it brought in.
+type ReferralDTO struct {
+	ChannelID        uuid.UUID  `json:"channel_id"`
+	ChannelTitle     string     `json:"channel_title"`
+	ChannelUsername  string     `json:"channel_username"`
+	ReferralShareBPS int        `json:"referral_share_bps" example:"1000"` // The channel's current share for new referrals
+	Code             string     `json:"code" example:"q3Vx_9aB"`
+	URL              string     `json:"url,omitempty" example:"https://t.me/tribute_bot?startapp=q3Vx_9aB"`
+	Clicks           int        `json:"clicks"`      // Launches of the Mini App through the link
+	Visitors         int        `json:"visitors"`    // Different users who opened it
+	Conversions      int        `json:"conversions"` // Subscribers who paid after opening it
+	Earned           []MoneyDTO `json:"earned"`      // Credited to the referrer's balance per currency, less refunds
+}
+
+// TierRequest creates or updates a subscription tier of a channel.
+type TierRequest struct {
+	Title       string `json:"title" binding:"required" example:"VIP"`
+	Description string `json:"description"`
+	ButtonText  string `json:"button_text"`
+	SortOrder   int    `json:"sort_order"` // Tiers are listed in ascending order
+}
+
+// CreateTierPriceRequest offers a tier at a price for a billing interval.
+type CreateTierPriceRequest struct {
+	Interval  string `json:"interval" binding:"required" enums:"month,quarter,year,lifetime" example:"month"`
+	Price     string `json:"price" binding:"required" example:"199.00"` // Decimal amount in major units
+	Currency  string `json:"currency,omitempty" example:"RUB"`          // ISO 4217 code, defaults to the platform currency
+	TrialDays int    `json:"trial_days" example:"7"`                    // Free days before the first charge
+	SortOrder int    `json:"sort_order"`                                // Prices are listed in ascending order
+}
+
+// UpdateTierPriceRequest changes a tier price. The billing interval can't be changed.
+type UpdateTierPriceRequest struct {
+	Price     string `json:"price" binding:"required" example:"199.00"` // Decimal amount in major units
+	Currency  string `json:"currency,omitempty" example:"RUB"`          // ISO 4217 code, defaults to the platform currency
+	TrialDays int    `json:"trial_days" example:"7"`                    // Free days before the first charge
+	SortOrder int    `json:"sort_order"`                                // Prices are listed in ascending order
+}
+
+// CreateSubscribeResponse carries the payment the subscriber has to complete to get access.
+type CreateSubscribeResponse struct {
+	PaymentID     uuid.UUID `json:"payment_id"`
+	Status        string    `json:"status" example:"pending"`
+	InvoiceLink   string    `json:"invoice_link,omitempty" example:"https://t.me/$AbCdEf"` // Empty if the payment was settled right away
+	Amount        MoneyDTO  `json:"amount"`
+	Discount      *MoneyDTO `json:"discount,omitempty"` // Taken off by the promo code, if one was applied
+	FailureReason string    `json:"failure_reason,omitempty"`
+}
+
+// PromoCodeRequest creates a promo code for a channel.
+type PromoCodeRequest struct {
+	Code           string     `json:"code" binding:"required" example:"SPRING20"` // 3-32 letters, digits, dashes or underscores; case-insensitive
+	TierID         *uuid.UUID `json:"tier_id,omitempty"`                          // Limits the code to one tier of the channel
+	DiscountType   string     `json:"discount_type" binding:"required" enums:"percent,fixed" example:"percent"`
+	PercentOff     int        `json:"percent_off,omitempty" example:"20"`      // 1-99, for percent discounts
+	AmountOff      string     `json:"amount_off,omitempty" example:"50.00"`    // Decimal amount in major units, for fixed discounts
+	Currency       string     `json:"currency,omitempty" example:"RUB"`        // ISO 4217 code of amount_off, defaults to the platform currency
+	MaxRedemptions int        `json:"max_redemptions,omitempty" example:"100"` // 0 for unlimited
+	PerUserLimit   int        `json:"per_user_limit,omitempty" example:"1"`    // 0 for unlimited
+	StartsAt       *time.Time `json:"starts_at,omitempty" example:"2024-03-01T00:00:00Z"`
+	EndsAt         *time.Time `json:"ends_at,omitempty" example:"2024-04-01T00:00:00Z"`
+}
+
+// UpdatePromoCodeRequest changes the limits and validity window of a promo code.
+type UpdatePromoCodeRequest struct {
+	MaxRedemptions int        `json:"max_redemptions" example:"100"` // 0 for unlimited
+	PerUserLimit   int        `json:"per_user_limit" example:"1"`    // 0 for unlimited
+	StartsAt       *time.Time `json:"starts_at,omitempty" example:"2024-03-01T00:00:00Z"`
+	EndsAt         *time.Time `json:"ends_at,omitempty" example:"2024-04-01T00:00:00Z"`
+}
+
+// PromoCodeDTO is a promo code as shown to its creator.
+type PromoCodeDTO struct {
+	ID             uuid.UUID  `json:"id"`
+	ChannelID      uuid.UUID  `json:"channel_id"`
+	TierID         *uuid.UUID `json:"tier_id,omitempty"`
+	Code           string     `json:"code" example:"SPRING20"`
+	DiscountType   string     `json:"discount_type" example:"percent"`
+	PercentOff     int        `json:"percent_off,omitempty" example:"20"`
+	AmountOff      *MoneyDTO  `json:"amount_off,omitempty"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	PerUserLimit   int        `json:"per_user_limit"`
+	Redemptions    int        `json:"redemptions"` // Payments that used the code and went through
+	StartsAt       string     `json:"starts_at,omitempty"`
+	EndsAt         string     `json:"ends_at,omitempty"`
+	CreatedAt      string     `json:"created_at"`
+}
+
+// PromoCodesResponse lists the promo codes of a channel.
+type PromoCodesResponse struct {
+	PromoCodes []PromoCodeDTO `json:"promo_codes"`
+}
+
+// RequestPayoutRequest asks to withdraw part of the creator's balance.
+type RequestPayoutRequest struct {
+	Amount   string `json:"amount" binding:"required" example:"1500.00"` // Decimal amount in major units
+	Currency string `json:"currency,omitempty" example:"RUB"`            // ISO 4217 code, defaults to the platform currency
+}
+
+// PayoutDTO is a payout in the payout history.
+type PayoutDTO struct {
+	ID            uuid.UUID `json:"id"`
+	Amount        MoneyDTO  `json:"amount"`
+	Status        string    `json:"status" example:"processing"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CreatedAt     string    `json:"created_at"`
+	CompletedAt   string    `json:"completed_at,omitempty"`
+}
+
+// VerificationEventDTO is one change of status of a verification request.
+type VerificationEventDTO struct {
+	Status    string `json:"status" example:"in_review"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// VerificationRequestDTO is a user's identity verification request with its status history.
+type VerificationRequestDTO struct {
+	ID          uuid.UUID              `json:"id"`
+	Status      string                 `json:"status" example:"rejected"`
+	Reason      string                 `json:"reason,omitempty"`
+	SubmittedAt string                 `json:"submitted_at"`
+	ReviewedAt  string                 `json:"reviewed_at,omitempty"`
+	History     []VerificationEventDTO `json:"history"`
+}
+
+// VerificationResponse is the user's verification status and their latest request.
+type VerificationResponse struct {
+	IsVerified bool `json:"is_verified"`
+	// Request is omitted if the user never submitted documents
+	Request *VerificationRequestDTO `json:"request,omitempty"`
+}
+
+// PayoutsResponse is the user's payout history, newest first.
+type PayoutsResponse struct {
+	Payouts []PayoutDTO `json:"payouts"`
+}
+
+// PaymentsResponse is a list of payments, newest first.
+type PaymentsResponse struct {
+	Payments []PaymentDTO `json:"payments"`
+}
+
+// RefundPaymentRequest asks to return part or all of a payment to its payer.
+type RefundPaymentRequest struct {
+	Amount       string `json:"amount,omitempty" example:"99.50"` // Decimal amount in the payment's currency; omit to refund everything not refunded yet
+	Reason       string `json:"reason,omitempty"`                 // Shown to the subscriber
+	RevokeAccess bool   `json:"revoke_access"`                    // Also end the subscriber's access to the channel
+}
+
+// RefundDTO is a refund of a payment.
+type RefundDTO struct {
+	ID           uuid.UUID `json:"id"`
+	PaymentID    uuid.UUID `json:"payment_id"`
+	Source       string    `json:"source" example:"creator"` // creator, admin or chargeback
+	Amount       MoneyDTO  `json:"amount"`
+	Status       string    `json:"status" example:"succeeded"`
+	Reason       string    `json:"reason,omitempty"`
+	RevokeAccess bool      `json:"revoke_access"`
+	CreatedAt    string    `json:"created_at"`
+}
+
+// RefundResponse is a refund together with the updated payment.
+type RefundResponse struct {
+	Refund  RefundDTO  `json:"refund"`
+	Payment PaymentDTO `json:"payment"`
+}
+
+// --- Reusable DTOs ---
+
+// MoneyDTO is an amount of money as a decimal string in major units with its ISO 4217 currency.
+type MoneyDTO struct {
+	Amount   string `json:"amount" example:"199.00"`
+	Currency string `json:"currency" example:"RUB"`
+}
+
+// PayoutCardDTO describes a payout card without revealing its number.
+type PayoutCardDTO struct {
+	Last4    string `json:"last4" example:"4242"`
+	Brand    string `json:"brand" example:"visa"`
+	ExpMonth int    `json:"exp_month,omitempty" example:"12"`
+	ExpYear  int    `json:"exp_year,omitempty" example:"2027"`
+}
+
+// NewPayoutCardDTO converts a payout card into its API representation; nil stays nil.
+func NewPayoutCardDTO(c *entities.PayoutCard) *PayoutCardDTO {
+	if c == nil {
+		return nil
+	}
+	return &PayoutCardDTO{Last4: c.Last4, Brand: c.Brand, ExpMonth: c.ExpMonth, ExpYear: c.ExpYear}
+}
+
+// MaskedCardNumber renders a payout card for display; empty if there is none.
+func MaskedCardNumber(c *entities.PayoutCard) string {
+	if c == nil {
+		return ""
+	}
+	return card.Mask(c.Last4)
+}
+
+// NewOptionalMoneyDTO converts an amount such as a discount into its API representation; nil if it is zero.
+func NewOptionalMoneyDTO(m money.Money) *MoneyDTO {
+	if !m.IsPositive() {
+		return nil
+	}
+	amount := NewMoneyDTO(m)
+	return &amount
+}
+
+// NewMoneyDTO converts a domain amount into its API representation.
+func NewMoneyDTO(m money.Money) MoneyDTO {
+	return MoneyDTO{Amount: m.Decimal(), Currency: m.Currency}
+}
+
+type ChannelDTO struct {
+	ID               uuid.UUID `json:"id"`
+	ChannelTitle     string    `json:"channel_title"`
+	ChannelUsername  string    `json:"channel_username"`
+	IsVerified       bool      `json:"is_verified"`
+	ReferralShareBPS int       `json:"referral_share_bps" example:"1000"` // Share of the creator's earnings paid to referrers (1000 = 10%); 0 if the channel has no referral program
+	Status           string    `json:"status" example:"pending"`          // pending until the owner and the bot are confirmed to have the rights needed, then verified
+	// MissingPermissions is what kept the last check from verifying a pending channel
+	MissingPermissions []MissingPermissionDTO `json:"missing_permissions,omitempty"`
+	// NextCheckAt is when a pending channel is checked again; empty once retries are given up
+	NextCheckAt string `json:"next_check_at,omitempty"`
+}
+
+// NewChannelDTO converts a channel into its API representation.
+func NewChannelDTO(ch *entities.Channel) ChannelDTO {
+	channelDTO := ChannelDTO{
+		ID:               ch.ID,
+		ChannelTitle:     ch.ChannelTitle,
+		ChannelUsername:  ch.ChannelUsername,
+		IsVerified:       ch.IsVerified,
+		ReferralShareBPS: ch.ReferralShareBPS,
+		Status:           ch.VerificationStatus(),
+	}
+	for _, permission := range ch.MissingPermissions {
+		channelDTO.MissingPermissions = append(channelDTO.MissingPermissions, MissingPermissionDTO{
+			Code:        string(permission),
+			Subject:     permission.Subject(),
+			Description: permission.Description(),
+		})
+	}
+	if !ch.IsVerified && ch.NextVerificationAt != nil {
+		channelDTO.NextCheckAt = ch.NextVerificationAt.Format(time.RFC3339)
+	}
+	return channelDTO
 }
 
 type SubDTO struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
+	ID          uuid.UUID      `json:"id"`
+	ChannelID   uuid.UUID      `json:"channel_id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	ButtonText  string         `json:"button_text"`
+	SortOrder   int            `json:"sort_order"`
+	Price       *MoneyDTO      `json:"price,omitempty"` // First price of the tier; omitted if it has none
+	Prices      []TierPriceDTO `json:"prices"`
+}
+
+// TierPriceDTO is one way to pay for a subscription tier.
+type TierPriceDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Interval  string    `json:"interval" example:"month"`
+	Price     MoneyDTO  `json:"price"`
+	TrialDays int       `json:"trial_days"`
+	SortOrder int       `json:"sort_order"`
+}
+
+// NewTierPriceDTO converts a tier price into its API representation.
+func NewTierPriceDTO(p *entities.TierPrice) TierPriceDTO {
+	return TierPriceDTO{
+		ID:        p.ID,
+		Interval:  string(p.Interval),
+		Price:     NewMoneyDTO(p.Price),
+		TrialDays: p.TrialDays,
+		SortOrder: p.SortOrder,
+	}
+}
+
+// NewSubDTO converts a subscription tier and its loaded prices into its API representation.
+func NewSubDTO(sub *entities.Subscription) SubDTO {
+	subDTO := SubDTO{
+		ID:          sub.ID,
+		ChannelID:   sub.ChannelID,
+		Title:       sub.Title,
+		Description: sub.Description,
+		ButtonText:  sub.ButtonText,
+		SortOrder:   sub.SortOrder,
+		Prices:      make([]TierPriceDTO, len(sub.Prices)),
+	}
+	for i, price := range sub.Prices {
+		subDTO.Prices[i] = NewTierPriceDTO(price)
+	}
+	if price := sub.DefaultPrice(); price != nil {
+		defaultPrice := NewMoneyDTO(price.Price)
+		subDTO.Price = &defaultPrice
+	}
+	return subDTO
 }
 
 type PaymentDTO struct {
-	Description string `json:"description"`
-	CreatedDate string `json:"created-date"`
+	ID            uuid.UUID `json:"id"`
+	Amount        MoneyDTO  `json:"amount"`
+	Status        string    `json:"status" example:"succeeded"`
+	Discount      *MoneyDTO `json:"discount,omitempty"` // Taken off by a promo code
+	Refunded      *MoneyDTO `json:"refunded,omitempty"` // Returned to the payer so far
+	FailureReason string    `json:"failure-reason,omitempty"`
+	Description   string    `json:"description"`
+	CreatedDate   string    `json:"created-date"`
 }
 
 // --- Generic Response DTOs ---
@@ -138,12 +534,13 @@ type StatusResponse struct {
 
 // UserResponse represents a user's data in a response.
 type UserResponse struct {
-	ID             int64   `json:"id"`
-	Earned         float64 `json:"earned"`
-	IsVerified     bool    `json:"is_verified"`
-	IsSubPublished bool    `json:"is_sub_published"`
-	IsOnboarded    bool    `json:"is_onboarded"`
-	CardNumber     string  `json:"card_number"`
+	ID             int64          `json:"id"`
+	Earned         MoneyDTO       `json:"earned"`
+	IsVerified     bool           `json:"is_verified"`
+	IsSubPublished bool           `json:"is_sub_published"`
+	IsOnboarded    bool           `json:"is_onboarded"`
+	CardNumber     string         `json:"card_number" example:"**** **** **** 4242"` // Masked
+	PayoutCard     *PayoutCardDTO `json:"payout_card,omitempty"`
 }
 
 // OnboardResponse is the response for a successful onboarding.
@@ -164,6 +561,11 @@ type PublishSubscriptionResponse struct {
 	Subscription SubDTO `json:"subscription"`
 }
 
+// TiersResponse lists the subscription tiers of a channel.
+type TiersResponse struct {
+	Tiers []SubDTO `json:"tiers"`
+}
+
 // CreateUserResponse is the response for creating a user
 type CreateUserResponse struct {
 	Message string       `json:"message"`
@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/interfaces/api/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// catalogError writes the response for an error returned by a tier or price operation.
+func catalogError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrChannelNotFound), errors.Is(err, services.ErrTierNotFound), errors.Is(err, services.ErrPriceNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrNotChannelOwner), errors.Is(err, services.ErrNotTierOwner):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrIntervalTaken):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrChannelNotVerified), errors.Is(err, services.ErrInvalidPrice),
+		errors.Is(err, services.ErrInvalidInterval), errors.Is(err, services.ErrInvalidTrialDays):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
+// authenticatedUser returns the ID of the user making the request, writing an error
+// response if it is missing.
+func authenticatedUser(c *gin.Context) (int64, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "User not authenticated"})
+		return 0, false
+	}
+	id, ok := userID.(int64)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Invalid user ID format in token"})
+		return 0, false
+	}
+	return id, true
+}
+
+// pathUUID parses a UUID path parameter, writing an error response if it is malformed.
+func pathUUID(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid " + name + ": " + err.Error()})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// @Summary      List Channel Tiers
+// @Description  Returns the subscription tiers of a channel with the prices each one is sold at, both in their sort order. The channel owner always sees their tiers; other users only see the tiers of verified channels.
+// @Tags         Catalog
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Channel ID"
+// @Success      200  {object}  dto.TiersResponse  "Success - The channel's tiers."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The channel ID is malformed or the channel is not verified."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The channel does not exist."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{id}/tiers [get]
+func (h *TributeHandler) GetChannelTiers(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	channelID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	tiers, err := h.service.GetChannelTiers(userID, channelID)
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	response := dto.TiersResponse{Tiers: make([]dto.SubDTO, len(tiers))}
+	for i, tier := range tiers {
+		response.Tiers[i] = dto.NewSubDTO(tier)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Get a Channel's Offer
+// @Description  Returns what the subscribe page of a channel shows: its title, whether its ownership is verified, and the tiers that can be bought with their titles, descriptions, button texts and price options (billing interval, price and free trial days). Tiers are only listed for verified channels. No authentication is needed, so the page can be shown before the Mini App has initData; subscribing still goes through `/create-subscribe` or `/start-trial` with the returned `channel_id`.
+// @Tags         Catalog
+// @Produce      json
+// @Param        username  path      string  true  "Channel username, with or without the @"
+// @Success      200  {object}  dto.ChannelOfferResponse  "Success - The channel's offer."
+// @Failure      404  {object}  dto.ErrorResponse         "Not Found - No channel with this username has been added."
+// @Failure      500  {object}  dto.ErrorResponse         "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{username}/offer [get]
+func (h *TributeHandler) GetChannelOffer(c *gin.Context) {
+	// Registered as /channels/:id/offer: gin requires the same wildcard name as the /channels/:id routes
+	offer, err := h.service.GetChannelOffer(c.Param("id"))
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newChannelOfferResponse(offer))
+}
+
+func newChannelOfferResponse(offer *services.ChannelOffer) dto.ChannelOfferResponse {
+	response := dto.ChannelOfferResponse{
+		ChannelID:        offer.Channel.ID,
+		ChannelTitle:     offer.Channel.ChannelTitle,
+		ChannelUsername:  strings.TrimPrefix(offer.Channel.ChannelUsername, "@"),
+		IsVerified:       offer.Channel.IsVerified,
+		Tiers:            make([]dto.SubDTO, len(offer.Tiers)),
+		ReferralShareBPS: offer.Channel.ReferralShareBPS,
+	}
+	for i, tier := range offer.Tiers {
+		response.Tiers[i] = dto.NewSubDTO(tier)
+	}
+	return response
+}
+
+// @Summary      Create a Tier
+// @Description  Adds a subscription tier (e.g. "Basic" or "VIP") to one of the user's verified channels. Subscribers can buy the tier once at least one price is added to it via `POST /tiers/{id}/prices`.
+// @Tags         Catalog
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id       path  string           true  "Channel ID"
+// @Param        payload  body  dto.TierRequest  true  "The tier to create."
+// @Success      201  {object}  dto.SubDTO         "Created - The new tier."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The request is invalid or the channel is not verified."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The channel does not exist."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{id}/tiers [post]
+func (h *TributeHandler) CreateTier(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	channelID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.TierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	tier, err := h.service.CreateTier(userID, channelID, tierDetails(req))
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSubDTO(tier))
+}
+
+// @Summary      Update a Tier
+// @Description  Changes the title, description, button text and position of one of the user's tiers.
+// @Tags         Catalog
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id       path  string           true  "Tier ID"
+// @Param        payload  body  dto.TierRequest  true  "The new tier details."
+// @Success      200  {object}  dto.SubDTO         "Success - The updated tier."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The request is invalid."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the tier belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The tier does not exist."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /tiers/{id} [put]
+func (h *TributeHandler) UpdateTier(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	tierID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.TierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	tier, err := h.service.UpdateTier(userID, tierID, tierDetails(req))
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSubDTO(tier))
+}
+
+// @Summary      Delete a Tier
+// @Description  Removes a tier and its prices from the channel's catalog. Subscribers who already paid for it keep their access until the end of their period.
+// @Tags         Catalog
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Tier ID"
+// @Success      200  {object}  dto.MessageResponse  "Success - The tier was removed."
+// @Failure      400  {object}  dto.ErrorResponse    "Bad Request - The tier ID is malformed."
+// @Failure      401  {object}  dto.ErrorResponse    "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse    "Forbidden - The provided initData is invalid or expired, or the tier belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse    "Not Found - The tier does not exist."
+// @Failure      500  {object}  dto.ErrorResponse    "Internal Server Error - An unexpected error occurred."
+// @Router       /tiers/{id} [delete]
+func (h *TributeHandler) DeleteTier(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	tierID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.ArchiveTier(userID, tierID); err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Tier deleted successfully"})
+}
+
+// @Summary      Add a Tier Price
+// @Description  Offers a tier for a billing interval: `month`, `quarter`, `year` or `lifetime`. A tier has at most one price per interval. Lifetime access never expires.
+// @Tags         Catalog
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id       path  string                      true  "Tier ID"
+// @Param        payload  body  dto.CreateTierPriceRequest  true  "The price to add."
+// @Success      201  {object}  dto.TierPriceDTO   "Created - The new price."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The request is invalid, e.g. an unknown interval or a malformed price."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the tier belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The tier does not exist."
+// @Failure      409  {object}  dto.ErrorResponse  "Conflict - The tier already has a price for this interval."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /tiers/{id}/prices [post]
+func (h *TributeHandler) AddTierPrice(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	tierID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateTierPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+	amount, err := h.parsePrice(req.Price, req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	price, err := h.service.AddTierPrice(userID, tierID, services.PriceDetails{
+		Interval:  entities.BillingInterval(req.Interval),
+		Price:     amount,
+		TrialDays: req.TrialDays,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewTierPriceDTO(price))
+}
+
+// @Summary      Update a Tier Price
+// @Description  Changes the amount, trial days and position of a price. The interval can't be changed; delete the price and add a new one instead. Payments that are already pending keep their amount.
+// @Tags         Catalog
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id       path  string                      true  "Price ID"
+// @Param        payload  body  dto.UpdateTierPriceRequest  true  "The new price details."
+// @Success      200  {object}  dto.TierPriceDTO   "Success - The updated price."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The request is invalid, e.g. a malformed price."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the price belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The price does not exist."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /prices/{id} [put]
+func (h *TributeHandler) UpdateTierPrice(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	priceID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTierPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+	amount, err := h.parsePrice(req.Price, req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	price, err := h.service.UpdateTierPrice(userID, priceID, services.PriceDetails{
+		Price:     amount,
+		TrialDays: req.TrialDays,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewTierPriceDTO(price))
+}
+
+// @Summary      Delete a Tier Price
+// @Description  Stops offering a tier at a price. Subscribers who already paid it keep their access until the end of their period.
+// @Tags         Catalog
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Price ID"
+// @Success      200  {object}  dto.MessageResponse  "Success - The price was removed."
+// @Failure      400  {object}  dto.ErrorResponse    "Bad Request - The price ID is malformed."
+// @Failure      401  {object}  dto.ErrorResponse    "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse    "Forbidden - The provided initData is invalid or expired, or the price belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse    "Not Found - The price does not exist."
+// @Failure      500  {object}  dto.ErrorResponse    "Internal Server Error - An unexpected error occurred."
+// @Router       /prices/{id} [delete]
+func (h *TributeHandler) DeleteTierPrice(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	priceID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.ArchiveTierPrice(userID, priceID); err != nil {
+		catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Price deleted successfully"})
+}
+
+func tierDetails(req dto.TierRequest) services.TierDetails {
+	return services.TierDetails{
+		Title:       req.Title,
+		Description: req.Description,
+		ButtonText:  req.ButtonText,
+		SortOrder:   req.SortOrder,
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/interfaces/api/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newSubscriberMembershipDTO(sm *services.SubscriberMembership) dto.MembershipDTO {
+	membership := sm.Membership
+	membershipDTO := newMembershipDTO(membership)
+	if sm.Channel != nil {
+		membershipDTO.ChannelTitle = sm.Channel.ChannelTitle
+		membershipDTO.ChannelUsername = sm.Channel.ChannelUsername
+	}
+	if sm.Tier != nil {
+		membershipDTO.TierTitle = sm.Tier.Title
+	}
+	if sm.Price != nil {
+		price := dto.NewMoneyDTO(sm.Price.Price)
+		membershipDTO.Price = &price
+		membershipDTO.BillingInterval = string(sm.Price.Interval)
+	}
+	if membership.Status == entities.MembershipActive && membership.CurrentPeriodEnd != nil {
+		membershipDTO.NextRenewalAt = membership.CurrentPeriodEnd.Format(time.RFC3339)
+	}
+	if membership.CancelledAt != nil {
+		membershipDTO.CancelledAt = membership.CancelledAt.Format(time.RFC3339)
+	}
+	if membership.HasAccess(time.Now()) {
+		membershipDTO.InviteLink = membership.InviteLink
+	}
+	return membershipDTO
+}
+
+// @Summary      List My Subscriptions
+// @Description  Returns the channels the user has access to as a subscriber, including free trials, followed by their past subscriptions. Each entry has the channel, tier and price, when the next period is due and, while access lasts, the invite link into the channel.
+// @Tags         My Subscriptions
+// @Produce      json
+// @Security     TgAuth
+// @Success      200  {object}  dto.MySubscriptionsResponse  "Success - The user's current and past subscriptions."
+// @Failure      401  {object}  dto.ErrorResponse            "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse            "Forbidden - The provided initData is invalid or expired."
+// @Failure      500  {object}  dto.ErrorResponse            "Internal Server Error - An unexpected error occurred."
+// @Router       /my-subscriptions [get]
+func (h *TributeHandler) GetMySubscriptions(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	memberships, err := h.service.GetMySubscriptions(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := dto.MySubscriptionsResponse{Subscriptions: make([]dto.MembershipDTO, len(memberships))}
+	for i, membership := range memberships {
+		response.Subscriptions[i] = newSubscriberMembershipDTO(membership)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Cancel a Subscription
+// @Description  Stops one of the user's subscriptions from renewing. Access lasts until the end of the period already paid for (or of the free trial), after which the user is removed from the channel. Cancelling again has no effect; lifetime access can't be cancelled.
+// @Tags         My Subscriptions
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Subscription (membership) ID"
+// @Success      200  {object}  dto.MembershipDTO  "Success - The cancelled subscription."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The ID is malformed, or the subscription has expired or is lifetime access."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the subscription belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The subscription does not exist."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /my-subscriptions/{id}/cancel [post]
+func (h *TributeHandler) CancelMySubscription(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	membershipID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	membership, err := h.service.CancelMySubscription(userID, membershipID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMembershipNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrNotMembershipOwner):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrMembershipExpired), errors.Is(err, services.ErrMembershipNotRenewing):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newSubscriberMembershipDTO(membership))
+}
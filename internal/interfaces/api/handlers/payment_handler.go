@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/infrastructure/payments"
+	"tribute-back/internal/interfaces/api/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentHandler struct {
+	service   *services.TributeService
+	providers *payments.Registry
+}
+
+func NewPaymentHandler(service *services.TributeService, providers *payments.Registry) *PaymentHandler {
+	return &PaymentHandler{service: service, providers: providers}
+}
+
+// @Summary      Payment Provider Webhook
+// @Description  Receives asynchronous charge events (succeeded, failed, refunded) from a payment provider. A refund the provider reports on its own, e.g. a chargeback, is recorded against the payment, taken out of the creator's balance and ends the subscriber's access. Each provider authenticates its own requests; the simulator expects the hex HMAC-SHA256 of the body, keyed with `PAYMENT_SIMULATOR_WEBHOOK_SECRET`, in `X-Simulator-Signature`. Events that fail to apply are answered with 500 so the provider retries them.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        provider path string true "Payment provider name, e.g. simulator"
+// @Success      200  {object}  dto.StatusResponse  "Success - The event was applied."
+// @Failure      400  {object}  dto.ErrorResponse   "Bad Request - The webhook could not be authenticated or parsed."
+// @Failure      404  {object}  dto.ErrorResponse   "Not Found - The provider is unknown or doesn't use webhooks."
+// @Failure      500  {object}  dto.ErrorResponse   "Internal Server Error - The event could not be applied."
+// @Router       /payments/webhook/{provider} [post]
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	name := c.Param("provider")
+	provider, ok := h.providers.Get(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Unknown payment provider"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read request body"})
+		return
+	}
+
+	event, err := provider.ParseWebhook(c.Request.Header, body)
+	if err != nil {
+		if errors.Is(err, payments.ErrNotSupported) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Payment provider doesn't use webhooks"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.service.HandlePaymentEvent(name, event); err != nil {
+		log.Printf("Failed to handle %s event for charge %s: %v", name, event.ChargeID, err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to handle payment event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "ok"})
+}
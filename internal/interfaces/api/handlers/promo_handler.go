@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/interfaces/api/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// promoCodeError writes the response for an error returned by a promo code operation.
+func promoCodeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrChannelNotFound), errors.Is(err, services.ErrTierNotFound), errors.Is(err, services.ErrPromoCodeNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrNotChannelOwner), errors.Is(err, services.ErrNotTierOwner), errors.Is(err, services.ErrNotPromoCodeOwner):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrPromoCodeTaken):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidPromoCode), errors.Is(err, services.ErrInvalidDiscount),
+		errors.Is(err, services.ErrInvalidPromoLimits), errors.Is(err, services.ErrInvalidPromoWindow):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
+func newPromoCodeDTO(promoCode *entities.PromoCode) dto.PromoCodeDTO {
+	promoCodeDTO := dto.PromoCodeDTO{
+		ID:             promoCode.ID,
+		ChannelID:      promoCode.ChannelID,
+		TierID:         promoCode.SubscriptionID,
+		Code:           promoCode.Code,
+		DiscountType:   string(promoCode.DiscountType),
+		PercentOff:     promoCode.PercentOff,
+		AmountOff:      dto.NewOptionalMoneyDTO(promoCode.AmountOff),
+		MaxRedemptions: promoCode.MaxRedemptions,
+		PerUserLimit:   promoCode.PerUserLimit,
+		Redemptions:    promoCode.Redemptions,
+		CreatedAt:      promoCode.CreatedDate.Format(time.RFC3339),
+	}
+	if promoCode.StartsAt != nil {
+		promoCodeDTO.StartsAt = promoCode.StartsAt.Format(time.RFC3339)
+	}
+	if promoCode.EndsAt != nil {
+		promoCodeDTO.EndsAt = promoCode.EndsAt.Format(time.RFC3339)
+	}
+	return promoCodeDTO
+}
+
+// @Summary      List Promo Codes
+// @Description  Returns the live promo codes of one of the user's channels with how many times each was redeemed.
+// @Tags         Promo Codes
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Channel ID"
+// @Success      200  {object}  dto.PromoCodesResponse  "Success - The channel's promo codes."
+// @Failure      400  {object}  dto.ErrorResponse       "Bad Request - The channel ID is malformed."
+// @Failure      401  {object}  dto.ErrorResponse       "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse       "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse       "Not Found - The channel does not exist."
+// @Failure      500  {object}  dto.ErrorResponse       "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{id}/promo-codes [get]
+func (h *TributeHandler) GetPromoCodes(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	channelID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	promoCodes, err := h.service.GetPromoCodes(userID, channelID)
+	if err != nil {
+		promoCodeError(c, err)
+		return
+	}
+
+	response := dto.PromoCodesResponse{PromoCodes: make([]dto.PromoCodeDTO, len(promoCodes))}
+	for i, promoCode := range promoCodes {
+		response.PromoCodes[i] = newPromoCodeDTO(promoCode)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Create a Promo Code
+// @Description  Adds a discount code to one of the user's channels. The discount is either a percentage (1-99) or a fixed amount off prices in the same currency, and never makes a subscription free. Codes can be limited to one tier, to a number of redemptions in total and per subscriber, and to a validity window. Subscribers enter the code as `promo_code` in `/create-subscribe`; payments that fail don't use up a redemption.
+// @Tags         Promo Codes
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id       path  string                true  "Channel ID"
+// @Param        payload  body  dto.PromoCodeRequest  true  "The promo code to create."
+// @Success      201  {object}  dto.PromoCodeDTO   "Created - The new promo code."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The request is invalid, e.g. a malformed code or discount."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The channel or tier does not exist."
+// @Failure      409  {object}  dto.ErrorResponse  "Conflict - The channel already has a code with this text."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{id}/promo-codes [post]
+func (h *TributeHandler) CreatePromoCode(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	channelID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	var amountOff money.Money
+	if entities.PromoDiscountType(req.DiscountType) == entities.PromoFixed {
+		var err error
+		amountOff, err = h.parsePrice(req.AmountOff, req.Currency)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	promoCode, err := h.service.CreatePromoCode(userID, channelID, services.PromoCodeDetails{
+		Code:           req.Code,
+		TierID:         req.TierID,
+		DiscountType:   entities.PromoDiscountType(req.DiscountType),
+		PercentOff:     req.PercentOff,
+		AmountOff:      amountOff,
+		MaxRedemptions: req.MaxRedemptions,
+		PerUserLimit:   req.PerUserLimit,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+	})
+	if err != nil {
+		promoCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newPromoCodeDTO(promoCode))
+}
+
+// @Summary      Update a Promo Code
+// @Description  Changes the redemption limits and validity window of a promo code. The code and its discount can't be changed; delete it and create a new one instead.
+// @Tags         Promo Codes
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id       path  string                      true  "Promo code ID"
+// @Param        payload  body  dto.UpdatePromoCodeRequest  true  "The new limits and validity window."
+// @Success      200  {object}  dto.PromoCodeDTO   "Success - The updated promo code."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The request is invalid."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the code belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The promo code does not exist."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /promo-codes/{id} [put]
+func (h *TributeHandler) UpdatePromoCode(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	promoCodeID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	promoCode, err := h.service.UpdatePromoCode(userID, promoCodeID, services.PromoCodeDetails{
+		MaxRedemptions: req.MaxRedemptions,
+		PerUserLimit:   req.PerUserLimit,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+	})
+	if err != nil {
+		promoCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPromoCodeDTO(promoCode))
+}
+
+// @Summary      Delete a Promo Code
+// @Description  Stops a promo code from being used. Payments that already used it keep their discount.
+// @Tags         Promo Codes
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Promo code ID"
+// @Success      200  {object}  dto.MessageResponse  "Success - The promo code was removed."
+// @Failure      400  {object}  dto.ErrorResponse    "Bad Request - The promo code ID is malformed."
+// @Failure      401  {object}  dto.ErrorResponse    "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse    "Forbidden - The provided initData is invalid or expired, or the code belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse    "Not Found - The promo code does not exist."
+// @Failure      500  {object}  dto.ErrorResponse    "Internal Server Error - An unexpected error occurred."
+// @Router       /promo-codes/{id} [delete]
+func (h *TributeHandler) DeletePromoCode(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	promoCodeID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.ArchivePromoCode(userID, promoCodeID); err != nil {
+		promoCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Promo code deleted successfully"})
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/interfaces/api/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// referralError writes the response for an error returned by a referral operation.
+func referralError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrChannelNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrNotChannelOwner):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidReferralShare), errors.Is(err, services.ErrChannelNotVerified),
+		errors.Is(err, services.ErrNoReferralProgram), errors.Is(err, services.ErrSelfReferral):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
+// newReferralDTO converts a referral link and its stats for the dashboard. The URL is left
+// out if the bot's username isn't configured, so that the rest of the dashboard still loads.
+func (h *TributeHandler) newReferralDTO(r *services.ReferralLinkStats) dto.ReferralDTO {
+	url, _ := h.service.StartLinkURL(r.Link.Code)
+	referralDTO := dto.ReferralDTO{
+		ChannelID:        r.Channel.ID,
+		ChannelTitle:     r.Channel.ChannelTitle,
+		ChannelUsername:  r.Channel.ChannelUsername,
+		ReferralShareBPS: r.Channel.ReferralShareBPS,
+		Code:             r.Link.Code,
+		URL:              url,
+		Clicks:           r.Link.Clicks,
+		Visitors:         r.Link.Visitors,
+		Conversions:      r.Stats.Conversions,
+		Earned:           make([]dto.MoneyDTO, len(r.Stats.Earned)),
+	}
+	for i, earned := range r.Stats.Earned {
+		referralDTO.Earned[i] = dto.NewMoneyDTO(earned)
+	}
+	return referralDTO
+}
+
+// @Summary      Set Up a Referral Program
+// @Description  Sets the share of the creator's earnings from one of their verified channels that referrers get for the subscribers they bring, in basis points of what the creator receives after the platform commission. Referrers take their link with `/channels/{id}/referral-link`; a subscriber is credited to the referrer whose link they last opened before their first payment to the channel, and the referrer then gets the share of every payment the subscriber makes to it. A share of 0 ends the program; subscribers referred earlier keep the share they were referred at.
+// @Tags         Referrals
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id       path  string                      true  "Channel ID"
+// @Param        payload  body  dto.ReferralProgramRequest  true  "The share for referrers."
+// @Success      200  {object}  dto.ChannelDTO     "Success - The channel with its new referral share."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The share is out of range or the channel is not verified."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The channel does not exist."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{id}/referral-program [put]
+func (h *TributeHandler) SetReferralProgram(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	channelID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.ReferralProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	channel, err := h.service.SetReferralShare(userID, channelID, *req.ShareBPS)
+	if err != nil {
+		referralError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewChannelDTO(channel))
+}
+
+// @Summary      Get a Referral Link
+// @Description  Returns the user's referral link to another creator's channel, creating it the first time. The link opens the channel's offer in the Mini App; subscribers who pay after opening it are credited to the user, who earns the channel's referral share of their payments. Clicks, conversions and earnings are shown in the `referrals` section of the dashboard.
+// @Tags         Referrals
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Channel ID"
+// @Success      200  {object}  dto.StartLinkDTO   "Success - The user's referral link to the channel."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The channel is the user's own, not verified, or has no referral program."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The channel does not exist."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{id}/referral-link [post]
+func (h *TributeHandler) GetReferralLink(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	channelID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	link, err := h.service.GetReferralLink(userID, channelID)
+	if err != nil {
+		referralError(c, err)
+		return
+	}
+
+	linkDTO, err := h.newStartLinkDTO(link)
+	if err != nil {
+		referralError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, linkDTO)
+}
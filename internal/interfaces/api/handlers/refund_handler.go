@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/interfaces/api/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idempotencyKeyHeader carries the client-chosen key that makes a refund request safe to retry.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+func newPaymentDTO(payment *entities.Payment) dto.PaymentDTO {
+	return dto.PaymentDTO{
+		ID:            payment.ID,
+		Amount:        dto.NewMoneyDTO(payment.Amount),
+		Discount:      dto.NewOptionalMoneyDTO(payment.Discount),
+		Refunded:      dto.NewOptionalMoneyDTO(payment.Refunded),
+		Status:        string(payment.Status),
+		FailureReason: payment.FailureReason,
+		Description:   payment.Description,
+		CreatedDate:   payment.CreatedDate.Format(time.RFC3339),
+	}
+}
+
+func newRefundDTO(refund *entities.Refund) dto.RefundDTO {
+	return dto.RefundDTO{
+		ID:           refund.ID,
+		PaymentID:    refund.PaymentID,
+		Source:       string(refund.Source),
+		Amount:       dto.NewMoneyDTO(refund.Amount),
+		Status:       string(refund.Status),
+		Reason:       refund.Reason,
+		RevokeAccess: refund.RevokeAccess,
+		CreatedAt:    refund.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// @Summary      List Received Payments
+// @Description  Returns the succeeded and refunded payments made to the user for their channels, newest first, with how much of each was refunded.
+// @Tags         Refunds
+// @Produce      json
+// @Security     TgAuth
+// @Success      200  {object}  dto.PaymentsResponse  "Success - The payments made to the user."
+// @Failure      401  {object}  dto.ErrorResponse     "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse     "Forbidden - The provided initData is invalid or expired."
+// @Failure      500  {object}  dto.ErrorResponse     "Internal Server Error - An unexpected error occurred."
+// @Router       /payments/received [get]
+func (h *TributeHandler) GetReceivedPayments(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	payments, err := h.service.GetReceivedPayments(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := dto.PaymentsResponse{Payments: make([]dto.PaymentDTO, len(payments))}
+	for i, payment := range payments {
+		response.Payments[i] = newPaymentDTO(payment)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Refund a Payment
+// @Description  Returns part or all of a payment made to the user back to the subscriber through the payment provider, takes it back out of the user's balance (the platform commission on the refunded part is returned too) and tells the subscriber via the bot. With `revoke_access` the subscriber is also removed from the channel. A payment can be refunded in several parts up to its amount; Telegram Stars payments can only be refunded in full. The `Idempotency-Key` header makes the request safe to retry: repeating it returns the same refund, and a refund the provider rejected is tried again.
+// @Tags         Refunds
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id               path    string                    true  "Payment ID"
+// @Param        Idempotency-Key  header  string                    true  "Unique key of this refund request, e.g. a UUID"
+// @Param        payload          body    dto.RefundPaymentRequest  true  "How much to refund."
+// @Success      201  {object}  dto.RefundResponse  "Created - The refund and the updated payment."
+// @Failure      400  {object}  dto.ErrorResponse   "Bad Request - The request is invalid, the amount exceeds what is left to refund, or the payment can't be refunded."
+// @Failure      401  {object}  dto.ErrorResponse   "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse   "Forbidden - The provided initData is invalid or expired, or the payment was made to another user."
+// @Failure      404  {object}  dto.ErrorResponse   "Not Found - The payment does not exist."
+// @Failure      409  {object}  dto.ErrorResponse   "Conflict - The idempotency key was used for a different amount, or its refund is still in progress."
+// @Failure      502  {object}  dto.ErrorResponse   "Bad Gateway - The payment provider rejected the refund."
+// @Failure      500  {object}  dto.ErrorResponse   "Internal Server Error - An unexpected error occurred."
+// @Router       /payments/{id}/refunds [post]
+func (h *TributeHandler) RefundPayment(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	paymentID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key == "" || len(key) > 255 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Idempotency-Key header must be 1 to 255 characters"})
+		return
+	}
+
+	var req dto.RefundPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	refund, err := h.service.RefundPayment(userID, paymentID, services.RefundDetails{
+		Amount:         req.Amount,
+		Reason:         req.Reason,
+		RevokeAccess:   req.RevokeAccess,
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrNotPaymentRecipient):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrIdempotencyKeyReused), errors.Is(err, services.ErrRefundInProgress):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrPaymentNotRefundable), errors.Is(err, services.ErrInvalidRefundAmount),
+			errors.Is(err, services.ErrPartialRefundNotSupported):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrRefundFailed):
+			c.JSON(http.StatusBadGateway, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.RefundResponse{
+		Refund:  newRefundDTO(refund.Refund),
+		Payment: newPaymentDTO(refund.Payment),
+	})
+}
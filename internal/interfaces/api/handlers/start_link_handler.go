@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/infrastructure/auth"
+	"tribute-back/internal/interfaces/api/dto"
+	"tribute-back/internal/interfaces/api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// startLinkError writes the response for an error returned by a start link operation.
+func startLinkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrChannelNotFound), errors.Is(err, services.ErrPriceNotFound), errors.Is(err, services.ErrStartLinkNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrNotChannelOwner), errors.Is(err, services.ErrNotStartLinkOwner):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrChannelNotVerified):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
+func (h *TributeHandler) newStartLinkDTO(link *entities.StartLink) (dto.StartLinkDTO, error) {
+	url, err := h.service.StartLinkURL(link.Code)
+	if err != nil {
+		return dto.StartLinkDTO{}, err
+	}
+	return dto.StartLinkDTO{
+		ID:        link.ID,
+		Code:      link.Code,
+		URL:       url,
+		ChannelID: link.ChannelID,
+		PriceID:   link.PriceID,
+		Label:     link.Label,
+		Clicks:    link.Clicks,
+		Visitors:  link.Visitors,
+		CreatedAt: link.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// @Summary      List Start Links
+// @Description  Returns the live start links of one of the user's channels with how many times and by how many users each was opened.
+// @Tags         Start Links
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Channel ID"
+// @Success      200  {object}  dto.StartLinksResponse  "Success - The channel's start links."
+// @Failure      400  {object}  dto.ErrorResponse       "Bad Request - The channel ID is malformed."
+// @Failure      401  {object}  dto.ErrorResponse       "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse       "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse       "Not Found - The channel does not exist."
+// @Failure      500  {object}  dto.ErrorResponse       "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{id}/start-links [get]
+func (h *TributeHandler) GetStartLinks(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	channelID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	links, err := h.service.GetStartLinks(userID, channelID)
+	if err != nil {
+		startLinkError(c, err)
+		return
+	}
+
+	response := dto.StartLinksResponse{StartLinks: make([]dto.StartLinkDTO, len(links))}
+	for i, link := range links {
+		if response.StartLinks[i], err = h.newStartLinkDTO(link); err != nil {
+			startLinkError(c, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Create a Start Link
+// @Description  Creates a short link (`https://t.me/<bot>?startapp=<code>`) that opens the Mini App on the offer of one of the user's verified channels, optionally with one of its prices preselected. Share it anywhere; every launch of the Mini App through it is counted.
+// @Tags         Start Links
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        id       path  string                true  "Channel ID"
+// @Param        payload  body  dto.StartLinkRequest  true  "The link to create."
+// @Success      201  {object}  dto.StartLinkDTO   "Created - The new start link."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The request is invalid or the channel is not verified."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The channel does not exist or the price is not one of its prices."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /channels/{id}/start-links [post]
+func (h *TributeHandler) CreateStartLink(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	channelID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.StartLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	link, err := h.service.CreateStartLink(userID, channelID, req.Label, req.PriceID)
+	if err != nil {
+		startLinkError(c, err)
+		return
+	}
+
+	linkDTO, err := h.newStartLinkDTO(link)
+	if err != nil {
+		startLinkError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, linkDTO)
+}
+
+// @Summary      Delete a Start Link
+// @Description  Stops a start link from opening the offer. Its code is never given to another link. Referrers can delete their referral links the same way; subscribers a link already brought stay referred, and asking for the channel's referral link again makes a new one.
+// @Tags         Start Links
+// @Produce      json
+// @Security     TgAuth
+// @Param        id   path      string  true  "Start link ID"
+// @Success      200  {object}  dto.MessageResponse  "Success - The start link was removed."
+// @Failure      400  {object}  dto.ErrorResponse    "Bad Request - The start link ID is malformed."
+// @Failure      401  {object}  dto.ErrorResponse    "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse    "Forbidden - The provided initData is invalid or expired, or the link belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse    "Not Found - The start link does not exist."
+// @Failure      500  {object}  dto.ErrorResponse    "Internal Server Error - An unexpected error occurred."
+// @Router       /start-links/{id} [delete]
+func (h *TributeHandler) DeleteStartLink(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+	linkID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.ArchiveStartLink(userID, linkID); err != nil {
+		startLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Start link deleted successfully"})
+}
+
+// @Summary      Open the Start Link Offer
+// @Description  Resolves the `start_param` of the initData the Mini App was launched with, i.e. the code of a creator's start link, to the channel offer it points to, and records the launch as a click on the link. Repeated requests of the same launch are counted once.
+// @Tags         Start Links
+// @Produce      json
+// @Security     TgAuth
+// @Success      200  {object}  dto.StartOfferResponse  "Success - The offer the link opens."
+// @Failure      401  {object}  dto.ErrorResponse       "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse       "Forbidden - The provided initData is invalid or expired."
+// @Failure      404  {object}  dto.ErrorResponse       "Not Found - The Mini App wasn't launched through a start link, or the link was deleted."
+// @Failure      500  {object}  dto.ErrorResponse       "Internal Server Error - An unexpected error occurred."
+// @Router       /start-offer [get]
+func (h *TributeHandler) GetStartOffer(c *gin.Context) {
+	value, _ := c.Get(middleware.InitDataKey)
+	initData, ok := value.(*auth.ParsedInitData)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+	if initData.StartParam == "" {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Mini App was not opened through a start link"})
+		return
+	}
+
+	opened, err := h.service.OpenStartLink(services.StartLinkLaunch{
+		UserID:       initData.User.ID,
+		StartParam:   initData.StartParam,
+		ChatType:     initData.ChatType,
+		ChatInstance: initData.ChatInstance,
+		AuthDate:     time.Unix(initData.AuthDate, 0),
+	})
+	if err != nil {
+		startLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.StartOfferResponse{
+		Code:    opened.Link.Code,
+		PriceID: opened.Link.PriceID,
+		Offer:   newChannelOfferResponse(opened.Offer),
+	})
+}
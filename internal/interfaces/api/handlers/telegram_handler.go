@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/infrastructure/telegram"
+	"tribute-back/internal/interfaces/api/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// telegramSecretHeader is set by Telegram on every webhook request when setWebhook was called with a secret_token.
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type TelegramHandler struct {
+	dispatcher    *services.UpdateDispatcher
+	webhookSecret string
+}
+
+func NewTelegramHandler(dispatcher *services.UpdateDispatcher, webhookSecret string) *TelegramHandler {
+	return &TelegramHandler{dispatcher: dispatcher, webhookSecret: webhookSecret}
+}
+
+// @Summary      Telegram Bot Webhook
+// @Description  Receives updates from Telegram. Requests must carry the secret token configured via `setWebhook`. Callback queries from the verification buttons in the admin chat, pre-checkout queries and successful invoice payments are processed and answered here. Processing errors are logged and still acknowledged with 200 so Telegram doesn't redeliver the update.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        X-Telegram-Bot-Api-Secret-Token header string true "The secret token configured for the webhook."
+// @Param        payload body telegram.Update true "The update sent by Telegram."
+// @Success      200  {object}  dto.StatusResponse  "Success - The update was accepted."
+// @Failure      400  {object}  dto.ErrorResponse   "Bad Request - The update payload is malformed."
+// @Failure      401  {object}  dto.ErrorResponse   "Unauthorized - The secret token is missing or invalid."
+// @Router       /telegram/webhook [post]
+func (h *TelegramHandler) Webhook(c *gin.Context) {
+	// An empty configured secret rejects everything rather than accepting unauthenticated updates.
+	secret := c.GetHeader(telegramSecretHeader)
+	if h.webhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(h.webhookSecret)) != 1 {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Invalid webhook secret token"})
+		return
+	}
+
+	var update telegram.Update
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid update payload: " + err.Error()})
+		return
+	}
+
+	if err := h.dispatcher.Dispatch(&update); err != nil {
+		log.Printf("Failed to handle Telegram update %d: %v", update.UpdateID, err)
+	}
+
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "ok"})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/infrastructure/telegram"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+// fakeBotAPI is a Bot API server that answers every method with true and records the calls.
+type fakeBotAPI struct {
+	mu    sync.Mutex
+	calls map[string][]map[string]interface{}
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&params)
+
+	f.mu.Lock()
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	f.calls[method] = append(f.calls[method], params)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": true})
+}
+
+func (f *fakeBotAPI) called(method string) []map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+// newWebhookRouter serves the webhook with the given secret, sending the bot's calls to a fake Bot API.
+func newWebhookRouter(t *testing.T, secret string) (*gin.Engine, *fakeBotAPI) {
+	t.Helper()
+	fake := &fakeBotAPI{calls: make(map[string][]map[string]interface{})}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123456789:test-token")
+	t.Setenv("TELEGRAM_ADMIN_CHAT_ID", "-100")
+	t.Setenv("TELEGRAM_API_URL", server.URL)
+	bot, err := telegram.NewBotService()
+	if err != nil {
+		t.Fatalf("NewBotService: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewTelegramHandler(services.NewUpdateDispatcher(nil, bot), secret)
+	router.POST("/telegram/webhook", handler.Webhook)
+	return router, fake
+}
+
+func postUpdate(router *gin.Engine, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(telegramSecretHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+const callbackUpdate = `{"update_id": 1, "callback_query": {"id": "cb-1", "from": {"id": 42, "first_name": "Test"}, "data": "noop"}}`
+
+func TestTelegramWebhookSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		sent       string
+		body       string
+		want       int
+	}{
+		{name: "valid secret", configured: testWebhookSecret, sent: testWebhookSecret, body: callbackUpdate, want: http.StatusOK},
+		{name: "missing secret", configured: testWebhookSecret, body: callbackUpdate, want: http.StatusUnauthorized},
+		{name: "wrong secret", configured: testWebhookSecret, sent: "guess", body: callbackUpdate, want: http.StatusUnauthorized},
+		{name: "no secret configured", sent: "anything", body: callbackUpdate, want: http.StatusUnauthorized},
+		{name: "malformed update", configured: testWebhookSecret, sent: testWebhookSecret, body: `{"update_id": "one"`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, fake := newWebhookRouter(t, tt.configured)
+
+			rec := postUpdate(router, tt.sent, tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			answers := fake.called("answerCallbackQuery")
+			if dispatched := len(answers) > 0; dispatched != (tt.want == http.StatusOK) {
+				t.Errorf("update dispatched = %t with status %d", dispatched, rec.Code)
+			}
+		})
+	}
+}
+
+func TestTelegramWebhookDispatchesUpdates(t *testing.T) {
+	router, fake := newWebhookRouter(t, testWebhookSecret)
+
+	if rec := postUpdate(router, testWebhookSecret, callbackUpdate); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	answers := fake.called("answerCallbackQuery")
+	if len(answers) != 1 || answers[0]["callback_query_id"] != "cb-1" {
+		t.Fatalf("answered callback queries = %v, want cb-1", answers)
+	}
+
+	// Verification buttons pressed outside the admin chat are refused, and the update is
+	// still acknowledged so Telegram doesn't redeliver it
+	verify := `{"update_id": 2, "callback_query": {"id": "cb-2", "from": {"id": 42, "first_name": "Test"},
+		"message": {"message_id": 7, "chat": {"id": 42, "type": "private"}}, "data": "verify_approve_00000000-0000-0000-0000-000000000000"}}`
+	if rec := postUpdate(router, testWebhookSecret, verify); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	answers = fake.called("answerCallbackQuery")
+	if len(answers) != 2 || answers[1]["callback_query_id"] != "cb-2" || answers[1]["text"] != "Недостаточно прав" {
+		t.Errorf("answered callback queries = %v, want cb-2 refused", answers)
+	}
+}
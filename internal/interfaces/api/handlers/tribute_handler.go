@@ -1,12 +1,20 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 	"tribute-back/internal/application/services"
+	"tribute-back/internal/domain/card"
+	"tribute-back/internal/domain/entities"
+	"tribute-back/internal/domain/money"
+	"tribute-back/internal/infrastructure/auth"
 	"tribute-back/internal/interfaces/api/dto"
+	"tribute-back/internal/interfaces/api/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,32 +30,49 @@ func NewTributeHandler(service *services.TributeService) *TributeHandler {
 // buildDashboardResponse creates a dashboard response from dashboard data
 func (h *TributeHandler) buildDashboardResponse(data *services.DashboardData) *dto.DashboardResponse {
 	return &dto.DashboardResponse{
-		Earn:           data.User.Earned,
+		Earn:           dto.NewMoneyDTO(data.User.Earned),
 		IsVerified:     data.User.IsVerified,
 		IsSubPublished: data.User.IsSubPublished,
-		CardNumber:     data.User.CardNumber,
+		CardNumber:     dto.MaskedCardNumber(data.User.PayoutCard),
+		PayoutCard:     dto.NewPayoutCardDTO(data.User.PayoutCard),
 		ChannelsAndGroups: func() []dto.ChannelDTO {
 			dtos := make([]dto.ChannelDTO, len(data.Channels))
 			for i, ch := range data.Channels {
-				dtos[i] = dto.ChannelDTO{ID: ch.ID, ChannelTitle: ch.ChannelTitle, ChannelUsername: ch.ChannelUsername, IsVerified: ch.IsVerified}
+				dtos[i] = dto.NewChannelDTO(ch)
 			}
 			return dtos
 		}(),
 		Subscriptions: func() []dto.SubDTO {
 			dtos := make([]dto.SubDTO, len(data.Subscriptions))
 			for i, sub := range data.Subscriptions {
-				dtos[i] = dto.SubDTO{ID: sub.ID, Title: sub.Title, Description: sub.Description, Price: sub.Price}
+				dtos[i] = dto.NewSubDTO(sub)
 			}
 			return dtos
 		}(),
 		PaymentsHistory: func() []dto.PaymentDTO {
 			dtos := make([]dto.PaymentDTO, len(data.Payments))
 			for i, p := range data.Payments {
-				dtos[i] = dto.PaymentDTO{Description: p.Description, CreatedDate: p.CreatedDate.Format(time.RFC3339)}
+				dtos[i] = newPaymentDTO(p)
 			}
 			return dtos
 		}(),
+		Referrals: func() []dto.ReferralDTO {
+			dtos := make([]dto.ReferralDTO, len(data.Referrals))
+			for i, r := range data.Referrals {
+				dtos[i] = h.newReferralDTO(r)
+			}
+			return dtos
+		}(),
+	}
+}
+
+// parsePrice converts a decimal amount from a request into money, falling back
+// to the platform currency when none is given.
+func (h *TributeHandler) parsePrice(amount, currency string) (money.Money, error) {
+	if currency == "" {
+		currency = h.service.DefaultCurrency()
 	}
+	return money.Parse(amount, currency)
 }
 
 // This function is no longer needed as routes are registered directly in server.go
@@ -126,11 +151,12 @@ func (h *TributeHandler) Onboard(c *gin.Context) {
 		Message: "User is onboarded successfully",
 		User: dto.UserResponse{
 			ID:             user.ID,
-			Earned:         user.Earned,
+			Earned:         dto.NewMoneyDTO(user.Earned),
 			IsVerified:     user.IsVerified,
 			IsSubPublished: user.IsSubPublished,
 			IsOnboarded:    user.IsOnboarded,
-			CardNumber:     user.CardNumber,
+			CardNumber:     dto.MaskedCardNumber(user.PayoutCard),
+			PayoutCard:     dto.NewPayoutCardDTO(user.PayoutCard),
 		},
 	}
 
@@ -142,35 +168,58 @@ func (h *TributeHandler) Onboard(c *gin.Context) {
 }
 
 // @Summary      Add a new Channel
-// @Description  Adds a new Telegram channel for the specified user. The channel is saved with is_verified = false. User must exist in the system.
+// @Description  Adds a new Telegram channel for the user. The channel is saved with is_verified = false and checked in the background. From the Mini App the user is taken from initData and `user_id` may be omitted. Our bot process may instead sign the request and name the user in `user_id`: `X-Signature-Timestamp` carries the Unix time in seconds, `X-Signature-Nonce` a unique request ID of up to 64 letters, digits, `-` or `_`, and `X-Signature` the hex HMAC-SHA256 of `<timestamp>.<nonce>.<body>`, keyed with `SERVICE_AUTH_SECRET`; signatures older than `SERVICE_AUTH_MAX_SKEW` and nonces accepted before are rejected. User must exist in the system. Channels are also added automatically, for the user who promoted the bot, when the bot is made an administrator of a channel.
 // @Tags         Tribute
 // @Accept       json
 // @Produce      json
-// @Param        payload body dto.AddBotRequest true "The user ID, channel title and username to add."
+// @Security     TgAuth
+// @Param        X-Signature-Timestamp header string false "Unix time the request was signed at, for signed requests."
+// @Param        X-Signature-Nonce header string false "Unique request ID, for signed requests."
+// @Param        X-Signature header string false "Hex HMAC-SHA256 of the timestamp, nonce and body, for signed requests."
+// @Param        payload body dto.AddBotRequest true "The channel title and username to add, and the user for signed requests."
 // @Success      201  {object}  dto.AddBotResponse     "Created - The channel was added successfully."
 // @Failure      400  {object}  dto.ErrorResponse      "Bad Request - The request body is invalid, user not found, or channel is already added."
+// @Failure      401  {object}  dto.ErrorResponse      "Unauthorized - The request is neither authenticated with initData nor validly signed."
+// @Failure      403  {object}  dto.ErrorResponse      "Forbidden - The provided initData is invalid or expired, or user_id names another user."
 // @Failure      500  {object}  dto.ErrorResponse      "Internal Server Error - Database error."
 // @Router       /add-bot [post]
 func (h *TributeHandler) AddBot(c *gin.Context) {
 	var req dto.AddBotRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	bindErr := c.ShouldBindJSON(&req)
+
+	// Only our own services may act on behalf of the user named in the body
+	userID := req.UserID
+	if !c.GetBool(middleware.ServiceCallerKey) {
+		var ok bool
+		if userID, ok = authenticatedUser(c); !ok {
+			return
+		}
+		if req.UserID != 0 && req.UserID != userID {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "user_id does not match the authenticated user"})
+			return
+		}
+	} else if bindErr == nil && userID == 0 {
+		bindErr = errors.New("user_id is required in signed requests")
+	}
+
+	if bindErr != nil {
 		// Send error details to admin chat
 		errorMsg := fmt.Sprintf("🚨 ADD-BOT 400 ERROR\n\n❌ JSON Validation Error\n📝 Error: %s\n👤 User ID: %d\n📺 Channel Title: %s\n🔗 Channel Username: %s\n🌐 IP: %s",
-			err.Error(), req.UserID, req.ChannelTitle, req.ChannelUsername, c.ClientIP())
-		h.service.SendAdminMessage(errorMsg)
+			bindErr.Error(), userID, req.ChannelTitle, req.ChannelUsername, c.ClientIP())
+		h.alertAddBotFailure(c, userID, errorMsg)
 
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: bindErr.Error()})
 		return
 	}
 
 	// Check if user exists
-	_, err := h.service.GetDashboardData(req.UserID)
+	_, err := h.service.GetDashboardData(userID)
 	if err != nil {
 		if err.Error() == "user not found" {
 			// Send error details to admin chat
 			errorMsg := fmt.Sprintf("🚨 ADD-BOT 400 ERROR\n\n❌ User Not Found\n👤 User ID: %d\n📺 Channel Title: %s\n🔗 Channel Username: %s\n🌐 IP: %s",
-				req.UserID, req.ChannelTitle, req.ChannelUsername, c.ClientIP())
-			h.service.SendAdminMessage(errorMsg)
+				userID, req.ChannelTitle, req.ChannelUsername, c.ClientIP())
+			h.alertAddBotFailure(c, userID, errorMsg)
 
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "User not found"})
 			return
@@ -179,14 +228,14 @@ func (h *TributeHandler) AddBot(c *gin.Context) {
 		return
 	}
 
-	channel, err := h.service.AddBot(req.UserID, req.ChannelTitle, req.ChannelUsername)
+	channel, err := h.service.AddBot(userID, req.ChannelTitle, req.ChannelUsername)
 	if err != nil {
 		// Check if it's a business logic error (channel already exists)
 		if err.Error() == "this channel is already added to your account" {
 			// Send error details to admin chat
 			errorMsg := fmt.Sprintf("🚨 ADD-BOT 400 ERROR\n\n❌ Channel Already Exists\n👤 User ID: %d\n📺 Channel Title: %s\n🔗 Channel Username: %s\n🌐 IP: %s",
-				req.UserID, req.ChannelTitle, req.ChannelUsername, c.ClientIP())
-			h.service.SendAdminMessage(errorMsg)
+				userID, req.ChannelTitle, req.ChannelUsername, c.ClientIP())
+			h.alertAddBotFailure(c, userID, errorMsg)
 
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 			return
@@ -198,27 +247,33 @@ func (h *TributeHandler) AddBot(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, dto.AddBotResponse{
 		Message: "Channel added successfully",
-		Channel: dto.ChannelDTO{
-			ID:              channel.ID,
-			ChannelTitle:    channel.ChannelTitle,
-			ChannelUsername: channel.ChannelUsername,
-			IsVerified:      channel.IsVerified,
-		},
+		Channel: dto.NewChannelDTO(channel),
 	})
 }
 
+// alertAddBotFailure tells the admin chat about a rejected add-bot request, at most once per
+// throttle window for the client IP and for the user.
+func (h *TributeHandler) alertAddBotFailure(c *gin.Context, userID int64, message string) {
+	subjects := []string{"add-bot:ip:" + c.ClientIP()}
+	if userID != 0 {
+		subjects = append(subjects, fmt.Sprintf("add-bot:user:%d", userID))
+	}
+	h.service.SendThrottledAdminAlert(message, subjects...)
+}
+
 // @Summary      Upload Documents for Verification
-// @Description  Uploads a user's photo and passport scan for manual verification. Both images must be provided as base64 encoded strings. The documents are sent to a private admin chat for review.
+// @Description  Uploads a user's photo and passport scan for verification. Both images must be provided as base64 encoded strings. The documents are stored with a new verification request and sent to a private admin chat for review; if that fails they are sent again later. The request's progress is shown by `GET /verification`.
 // @Tags         Tribute
 // @Accept       json
 // @Produce      json
 // @Security     TgAuth
 // @Param        payload body dto.UploadVerifiedPassportRequest true "JSON object containing base64 encoded photo and passport."
 // @Success      200  {object}  dto.MessageResponse    "Success - The verification request was sent successfully."
-// @Failure      400  {object}  dto.ErrorResponse      "Bad Request - The request body is invalid or missing required fields."
+// @Failure      400  {object}  dto.ErrorResponse      "Bad Request - The request body is invalid, missing required fields or a document isn't valid base64."
 // @Failure      401  {object}  dto.ErrorResponse      "Unauthorized - The Authorization header is missing or invalid."
 // @Failure      403  {object}  dto.ErrorResponse      "Forbidden - The provided initData is invalid or expired."
-// @Failure      500  {object}  dto.ErrorResponse      "Internal Server Error - Failed to send documents to the verification service."
+// @Failure      409  {object}  dto.ErrorResponse      "Conflict - The user is already verified or has a request in review."
+// @Failure      500  {object}  dto.ErrorResponse      "Internal Server Error - Failed to store the documents."
 // @Router       /upload-verified-passport [post]
 func (h *TributeHandler) UploadVerifiedPassport(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -243,9 +298,8 @@ func (h *TributeHandler) UploadVerifiedPassport(c *gin.Context) {
 		return
 	}
 
-	err := h.service.RequestVerification(id, req.UserPhoto, req.UserPassport)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to send verification request: " + err.Error()})
+	if _, err := h.service.RequestVerification(id, req.UserPhoto, req.UserPassport); err != nil {
+		verificationError(c, err)
 		return
 	}
 
@@ -253,35 +307,71 @@ func (h *TributeHandler) UploadVerifiedPassport(c *gin.Context) {
 }
 
 // @Summary      Check Verified Passport
-// @Description  Updates user verification status based on provided parameters. This endpoint can be called directly to set user verification status.
-// @Tags         Tribute
+// @Description  Callback of the KYC provider that decides a user's verification request: the one named by `requestId`, or else the user's open request. Without any request only the user's status is set. Each integration partner signs its requests with its own secret from `PARTNER_SECRETS`: `X-Partner-Id` names the partner, `X-Signature-Timestamp` carries the Unix time in seconds and `X-Signature` the hex HMAC-SHA256 of `<timestamp>.<body>`. Signatures older than `PARTNER_SIGNATURE_MAX_SKEW` or accepted before are rejected. Every attempt is audited and rejected ones are reported to the admin chat.
+// @Tags         Webhooks
 // @Accept       json
 // @Produce      json
+// @Param        X-Partner-Id header string true "The integration partner that signed the request."
+// @Param        X-Signature-Timestamp header string true "Unix time the request was signed at."
+// @Param        X-Signature header string true "Hex HMAC-SHA256 of the timestamp and body."
 // @Param        payload body dto.CheckVerifiedPassportRequest true "User ID and verification status."
 // @Success      200  {object}  dto.StatusResponse     "Success - User verification status updated."
 // @Failure      400  {object}  dto.ErrorResponse      "Bad Request - Invalid request body."
-// @Failure      404  {object}  dto.ErrorResponse      "Not Found - User not found."
+// @Failure      401  {object}  dto.ErrorResponse      "Unauthorized - The request isn't validly signed by a partner, or replays an earlier one."
+// @Failure      404  {object}  dto.ErrorResponse      "Not Found - User or verification request not found."
+// @Failure      409  {object}  dto.ErrorResponse      "Conflict - The verification request has already been decided."
 // @Failure      500  {object}  dto.ErrorResponse      "Internal Server Error - Failed to update user verification status."
 // @Router       /check-verified-passport [post]
 func (h *TributeHandler) CheckVerifiedPassport(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read request body"})
+		return
+	}
+	err = h.service.AuthenticatePartnerRequest(services.PartnerRequest{
+		Partner:   c.GetHeader(auth.PartnerHeader),
+		Endpoint:  "check-verified-passport",
+		RemoteIP:  c.ClientIP(),
+		Timestamp: c.GetHeader(auth.ServiceTimestampHeader),
+		Signature: c.GetHeader(auth.ServiceSignatureHeader),
+		Body:      body,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrPartnerUnauthorized) {
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
 	var req dto.CheckVerifiedPassportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		// Send error details to admin chat
 		errorMsg := fmt.Sprintf("🚨 CHECK-VERIFIED-PASSPORT 400 ERROR\n\n❌ JSON Validation Error\n📝 Error: %s\n👤 User ID: %d\n✅ Is Verificated: %t\n🌐 IP: %s",
 			err.Error(), req.UserID, req.IsVerificated, c.ClientIP())
-		h.service.SendAdminMessage(errorMsg)
+		h.service.SendThrottledAdminAlert(errorMsg, "partner:ip:"+c.ClientIP())
 
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
 		return
 	}
 
-	err := h.service.UpdateUserVerification(req.UserID, req.IsVerificated)
+	err = h.service.ApplyPartnerVerification(c.GetHeader(auth.PartnerHeader), req.UserID, req.RequestID, req.IsVerificated, req.Reason)
 	if err != nil {
+		if errors.Is(err, services.ErrVerificationNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrVerificationDecided) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "user not found") {
 			// Send error details to admin chat
 			errorMsg := fmt.Sprintf("🚨 CHECK-VERIFIED-PASSPORT 404 ERROR\n\n❌ User Not Found\n👤 User ID: %d\n✅ Is Verificated: %t\n🌐 IP: %s",
 				req.UserID, req.IsVerificated, c.ClientIP())
-			h.service.SendAdminMessage(errorMsg)
+			h.service.SendThrottledAdminAlert(errorMsg, "partner:ip:"+c.ClientIP())
 
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
 			return
@@ -294,14 +384,14 @@ func (h *TributeHandler) CheckVerifiedPassport(c *gin.Context) {
 }
 
 // @Summary      Set Up Payout Method
-// @Description  Saves user's bank card number for payouts. The user must be verified to use this endpoint. Only the card number is stored in the database.
+// @Description  Saves the bank card the user is paid out to, replacing any previous one. The user must be verified to use this endpoint. The card number is checked with the Luhn algorithm and stored only encrypted; responses only ever show the last four digits.
 // @Tags         Tribute
 // @Accept       json
 // @Produce      json
 // @Security     TgAuth
-// @Param        payload body dto.SetUpPayoutsRequest true "The user's card number."
-// @Success      200  {object}  dto.MessageResponse    "Success - The card number was saved successfully."
-// @Failure      400  {object}  dto.ErrorResponse      "Bad Request - The request body is invalid."
+// @Param        payload body dto.SetUpPayoutsRequest true "The user's card number and expiry."
+// @Success      200  {object}  dto.MessageResponse    "Success - The card was saved successfully."
+// @Failure      400  {object}  dto.ErrorResponse      "Bad Request - The request body is invalid, the card number fails validation or the card has expired."
 // @Failure      401  {object}  dto.ErrorResponse      "Unauthorized - The Authorization header is missing or invalid."
 // @Failure      403  {object}  dto.ErrorResponse      "Forbidden - The provided initData is invalid or expired, or the user is not verified."
 // @Failure      500  {object}  dto.ErrorResponse      "Internal Server Error - Database error."
@@ -325,11 +415,15 @@ func (h *TributeHandler) SetUpPayouts(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.SetUpPayouts(id, req.CardNumber); err != nil {
-		if strings.Contains(err.Error(), "user must be verified") {
+	if err := h.service.SetUpPayouts(id, req.CardNumber, req.CardExpiry); err != nil {
+		if errors.Is(err, services.ErrPayoutNotAllowed) {
 			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
 			return
 		}
+		if errors.Is(err, card.ErrInvalidNumber) || errors.Is(err, card.ErrInvalidExpiry) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to set up payouts: " + err.Error()})
 		return
 	}
@@ -338,17 +432,18 @@ func (h *TributeHandler) SetUpPayouts(c *gin.Context) {
 }
 
 // @Summary      Publish or Update a Subscription Tier
-// @Description  Allows an author to create or update their public subscription details (title, description, price). This is an idempotent operation. The user must have at least one channel added via `/add-bot` to use this.
+// @Description  Allows an author to create or update the public subscription details (title, description, monthly price) of the first tier of one of their channels. Publishing again for the same `channel_id` updates that tier; further tiers and billing intervals are managed via `/channels/{id}/tiers`. This is an idempotent operation. The channel must have been added via `/add-bot` and verified via `/check-channel`.
 // @Tags         Tribute
 // @Accept       json
 // @Produce      json
 // @Security     TgAuth
 // @Param        payload body dto.PublishSubscriptionRequest true "The details of the subscription tier to publish."
 // @Success      200  {object}  dto.PublishSubscriptionResponse "Success - The subscription was published or updated successfully."
-// @Failure      400  {object}  dto.ErrorResponse               "Bad Request - The request body is invalid."
+// @Failure      400  {object}  dto.ErrorResponse               "Bad Request - The request body is invalid, the price or currency is malformed, or the channel is not verified."
 // @Failure      401  {object}  dto.ErrorResponse               "Unauthorized - The Authorization header is missing or invalid."
-// @Failure      403  {object}  dto.ErrorResponse               "Forbidden - The provided initData is invalid or expired."
-// @Failure      500  {object}  dto.ErrorResponse               "Internal Server Error - e.g., the user has no channels."
+// @Failure      403  {object}  dto.ErrorResponse               "Forbidden - The provided initData is invalid or expired, or the channel belongs to another user."
+// @Failure      404  {object}  dto.ErrorResponse               "Not Found - The channel does not exist."
+// @Failure      500  {object}  dto.ErrorResponse               "Internal Server Error - An unexpected error occurred."
 // @Router       /publish-subscription [put]
 func (h *TributeHandler) PublishSubscription(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -368,35 +463,48 @@ func (h *TributeHandler) PublishSubscription(c *gin.Context) {
 		return
 	}
 
-	subscription, err := h.service.PublishSubscription(id, req.Title, req.Description, req.ButtonText, req.Price)
+	price, err := h.parsePrice(req.Price, req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	subscription, err := h.service.PublishSubscription(id, req.ChannelID, req.Title, req.Description, req.ButtonText, price)
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+			return
+		case errors.Is(err, services.ErrNotChannelOwner):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+			return
+		case errors.Is(err, services.ErrChannelNotVerified), errors.Is(err, services.ErrInvalidPrice):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.PublishSubscriptionResponse{
-		Message: "Subscription published successfully",
-		Subscription: dto.SubDTO{
-			ID:          subscription.ID,
-			Title:       subscription.Title,
-			Description: subscription.Description,
-			Price:       subscription.Price,
-		},
+		Message:      "Subscription published successfully",
+		Subscription: dto.NewSubDTO(subscription),
 	})
 }
 
-// @Summary      Subscribe to an Author
-// @Description  Allows an authenticated user (the subscriber) to pay for and subscribe to another user (the creator). This action creates a payment record and updates the creator's earnings.
+// @Summary      Subscribe to a Channel
+// @Description  Charges the subscriber for one billing period of a price of the channel's subscription tiers (`price_id`, or the first price of the first tier if omitted) through the configured payment provider. The amount is always taken from the catalog, less the discount of `promo_code` if one is given. With Telegram invoices the payment stays `pending`: open `invoice_link` in the Mini App (`Telegram.WebApp.openInvoice`) to pay. Access to the creator's channel is granted, and a one-time invite link sent via the bot, only once the provider confirms the payment; the payment `status` shows whether that already happened.
 // @Tags         Tribute
 // @Accept       json
 // @Produce      json
 // @Security     TgAuth
-// @Param        payload body dto.CreateSubscribeRequest true "The ID of the user to subscribe to and the price."
-// @Success      201  {object}  dto.MessageResponse      "Created - The subscription was successful."
-// @Failure      400  {object}  dto.ErrorResponse        "Bad Request - The request body is invalid."
-// @Failure      401  {object}  dto.ErrorResponse        "Unauthorized - The Authorization header is missing or invalid."
-// @Failure      403  {object}  dto.ErrorResponse        "Forbidden - The provided initData is invalid or expired."
-// @Failure      500  {object}  dto.ErrorResponse        "Internal Server Error - e.g., the creator has no subscription tier, or the price is incorrect."
+// @Param        payload body dto.CreateSubscribeRequest true "The ID of the channel to subscribe to."
+// @Success      201  {object}  dto.CreateSubscribeResponse  "Created - The payment was created; see its status."
+// @Failure      400  {object}  dto.ErrorResponse            "Bad Request - The request body is invalid, the channel is not verified, the user tried to subscribe to their own channel, or the promo code can't be used."
+// @Failure      401  {object}  dto.ErrorResponse            "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse            "Forbidden - The provided initData is invalid or expired."
+// @Failure      404  {object}  dto.ErrorResponse            "Not Found - The channel does not exist, has no subscription tier, or the price is not one of its prices."
+// @Failure      500  {object}  dto.ErrorResponse            "Internal Server Error - An unexpected error occurred."
 // @Router       /create-subscribe [post]
 func (h *TributeHandler) CreateSubscribe(c *gin.Context) {
 	subscriberID, exists := c.Get("userID")
@@ -416,13 +524,97 @@ func (h *TributeHandler) CreateSubscribe(c *gin.Context) {
 		return
 	}
 
-	// The user making the request is the subscriber. The user_id in the body is the creator.
-	if err := h.service.CreateSubscription(id, req.UserID, req.Price); err != nil {
+	// The user making the request is the subscriber; the channel determines the creator.
+	checkout, err := h.service.StartSubscriptionCheckout(id, req.ChannelID, req.PriceID, req.PromoCode, req.SendInvoice)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound), errors.Is(err, services.ErrNoSubscriptionTier), errors.Is(err, services.ErrPriceNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+			return
+		case errors.Is(err, services.ErrSelfSubscription), errors.Is(err, services.ErrChannelNotVerified),
+			errors.Is(err, services.ErrPromoCodeNotFound), errors.Is(err, services.ErrPromoCodeInactive),
+			errors.Is(err, services.ErrPromoCodeNotApplicable), errors.Is(err, services.ErrPromoCodeExhausted),
+			errors.Is(err, services.ErrPromoCodeUsed):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.MessageResponse{Message: "Successfully subscribed"})
+	c.JSON(http.StatusCreated, dto.CreateSubscribeResponse{
+		PaymentID:     checkout.Payment.ID,
+		Status:        string(checkout.Payment.Status),
+		InvoiceLink:   checkout.CheckoutURL,
+		Amount:        dto.NewMoneyDTO(checkout.Payment.Amount),
+		Discount:      dto.NewOptionalMoneyDTO(checkout.Payment.Discount),
+		FailureReason: checkout.Payment.FailureReason,
+	})
+}
+
+func newMembershipDTO(membership *entities.Membership) dto.MembershipDTO {
+	membershipDTO := dto.MembershipDTO{
+		ID:        membership.ID,
+		ChannelID: membership.ChannelID,
+		TierID:    membership.SubscriptionID,
+		PriceID:   membership.PriceID,
+		Status:    string(membership.Status),
+		StartedAt: membership.StartedAt.Format(time.RFC3339),
+	}
+	if membership.CurrentPeriodEnd != nil {
+		membershipDTO.CurrentPeriodEnd = membership.CurrentPeriodEnd.Format(time.RFC3339)
+	}
+	if membership.TrialEndsAt != nil {
+		membershipDTO.TrialEndsAt = membership.TrialEndsAt.Format(time.RFC3339)
+	}
+	return membershipDTO
+}
+
+// @Summary      Start a Free Trial
+// @Description  Gives the subscriber free access to a channel for the trial days of one of its tier prices (`price_id`, or the first price of the first tier if omitted) and sends a one-time invite link via the bot. Each user gets one trial per channel. Before the trial ends the bot reminds the subscriber and invoices them for the price; paying converts the trial into a paid subscription that starts when the trial ends, otherwise the subscriber is removed from the channel.
+// @Tags         Tribute
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        payload body dto.StartTrialRequest true "The channel to try."
+// @Success      201  {object}  dto.MembershipDTO  "Created - The trial started."
+// @Failure      400  {object}  dto.ErrorResponse  "Bad Request - The request body is invalid, the channel is not verified, or the price has no trial."
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden - The provided initData is invalid or expired."
+// @Failure      404  {object}  dto.ErrorResponse  "Not Found - The channel does not exist, has no subscription tier, or the price is not one of its prices."
+// @Failure      409  {object}  dto.ErrorResponse  "Conflict - The user already had a trial in this channel or is already subscribed to it."
+// @Failure      500  {object}  dto.ErrorResponse  "Internal Server Error - An unexpected error occurred."
+// @Router       /start-trial [post]
+func (h *TributeHandler) StartTrial(c *gin.Context) {
+	id, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	var req dto.StartTrialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	membership, err := h.service.StartTrial(id, req.ChannelID, req.PriceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound), errors.Is(err, services.ErrNoSubscriptionTier), errors.Is(err, services.ErrPriceNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+			return
+		case errors.Is(err, services.ErrTrialUsed), errors.Is(err, services.ErrAlreadySubscribed):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+			return
+		case errors.Is(err, services.ErrSelfSubscription), errors.Is(err, services.ErrChannelNotVerified), errors.Is(err, services.ErrNoTrial):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newMembershipDTO(membership))
 }
 
 // @Summary      Create User
@@ -523,19 +715,14 @@ func (h *TributeHandler) GetChannelList(c *gin.Context) {
 	// Convert to DTO
 	channelDTOs := make([]dto.ChannelDTO, len(channels))
 	for i, ch := range channels {
-		channelDTOs[i] = dto.ChannelDTO{
-			ID:              ch.ID,
-			ChannelTitle:    ch.ChannelTitle,
-			ChannelUsername: ch.ChannelUsername,
-			IsVerified:      ch.IsVerified,
-		}
+		channelDTOs[i] = dto.NewChannelDTO(ch)
 	}
 
 	c.JSON(http.StatusOK, channelDTOs)
 }
 
 // @Summary      Check Channel Ownership
-// @Description  Checks if the user is the owner of the specified channel. If yes, sets is_verified = true. If no, deletes the channel.
+// @Description  Checks in Telegram that the user is the creator or an administrator of the channel and that the bot is an administrator allowed to invite users via link and to ban users. If everything is in place the channel becomes `verified`. Otherwise it stays `pending` with the list of `missing_permissions` and is checked again in the background until it is verified or the retries are given up; the owner is told in the bot either way. The owner can only be checked once the bot is an administrator. Calling this again starts the retries over.
 // @Tags         Tribute
 // @Accept       json
 // @Produce      json
@@ -566,10 +753,10 @@ func (h *TributeHandler) CheckChannel(c *gin.Context) {
 		return
 	}
 
-	isOwner, err := h.service.CheckChannel(id, req.ChannelID)
+	channel, err := h.service.CheckChannel(id, req.ChannelID)
 	if err != nil {
 		// Check if it's a business logic error (channel not found, not owned by user)
-		if err.Error() == "channel not found" || err.Error() == "channel does not belong to this user" {
+		if errors.Is(err, services.ErrChannelNotFound) || errors.Is(err, services.ErrNotChannelOwner) {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 			return
 		}
@@ -579,6 +766,109 @@ func (h *TributeHandler) CheckChannel(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, dto.CheckChannelResponse{
-		IsOwner: isOwner,
+		IsOwner: channel.IsVerified,
+		Channel: dto.NewChannelDTO(channel),
 	})
 }
+
+func newPayoutDTO(payout *entities.Payout) dto.PayoutDTO {
+	payoutDTO := dto.PayoutDTO{
+		ID:            payout.ID,
+		Amount:        dto.NewMoneyDTO(payout.Amount),
+		Status:        string(payout.Status),
+		FailureReason: payout.FailureReason,
+		CreatedAt:     payout.CreatedAt.Format(time.RFC3339),
+	}
+	if payout.CompletedAt != nil {
+		payoutDTO.CompletedAt = payout.CompletedAt.Format(time.RFC3339)
+	}
+	return payoutDTO
+}
+
+// @Summary      Request a Payout
+// @Description  Withdraws part of the creator's balance to the card set up via `/set-up-payouts`. The amount is reserved immediately and sent to the payout gateway in the background; follow its progress with `GET /payouts`. If the payout fails the amount is returned to the balance.
+// @Tags         Payouts
+// @Accept       json
+// @Produce      json
+// @Security     TgAuth
+// @Param        payload body dto.RequestPayoutRequest true "The amount to withdraw."
+// @Success      201  {object}  dto.PayoutDTO        "Created - The payout was requested."
+// @Failure      400  {object}  dto.ErrorResponse    "Bad Request - The amount is invalid, below the minimum or exceeds the balance, or no payout method is set up."
+// @Failure      401  {object}  dto.ErrorResponse    "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse    "Forbidden - The user is not verified, or the initData is invalid or expired."
+// @Failure      500  {object}  dto.ErrorResponse    "Internal Server Error - An unexpected error occurred."
+// @Router       /payouts [post]
+func (h *TributeHandler) RequestPayout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+	id, ok := userID.(int64)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Invalid user ID format in token"})
+		return
+	}
+
+	var req dto.RequestPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	amount, err := h.parsePrice(req.Amount, req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	payout, err := h.service.RequestPayout(id, amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPayoutNotAllowed):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrNoPayoutMethod), errors.Is(err, services.ErrPayoutBelowMinimum), errors.Is(err, services.ErrInsufficientFunds):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, newPayoutDTO(payout))
+}
+
+// @Summary      Get Payout History
+// @Description  Returns the user's payouts, newest first.
+// @Tags         Payouts
+// @Produce      json
+// @Security     TgAuth
+// @Success      200  {object}  dto.PayoutsResponse  "Success - Returns the payout history."
+// @Failure      401  {object}  dto.ErrorResponse    "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse    "Forbidden - The provided initData is invalid or expired."
+// @Failure      500  {object}  dto.ErrorResponse    "Internal Server Error - An unexpected error occurred."
+// @Router       /payouts [get]
+func (h *TributeHandler) GetPayouts(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+	id, ok := userID.(int64)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Invalid user ID format in token"})
+		return
+	}
+
+	payouts, err := h.service.GetPayouts(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := dto.PayoutsResponse{Payouts: make([]dto.PayoutDTO, len(payouts))}
+	for i, payout := range payouts {
+		response.Payouts[i] = newPayoutDTO(payout)
+	}
+	c.JSON(http.StatusOK, response)
+}
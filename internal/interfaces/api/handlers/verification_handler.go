@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"tribute-back/internal/application/services"
+	"tribute-back/internal/interfaces/api/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// verificationError writes the response for an error returned by a verification operation.
+func verificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidDocument):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrAlreadyVerified), errors.Is(err, services.ErrVerificationPending):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "User not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
+func newVerificationResponse(overview *services.VerificationOverview) dto.VerificationResponse {
+	response := dto.VerificationResponse{IsVerified: overview.IsVerified}
+	if overview.Request == nil {
+		return response
+	}
+
+	request := &dto.VerificationRequestDTO{
+		ID:          overview.Request.ID,
+		Status:      string(overview.Request.Status),
+		Reason:      overview.Request.Reason,
+		SubmittedAt: overview.Request.SubmittedAt.Format(time.RFC3339),
+		History:     make([]dto.VerificationEventDTO, len(overview.History)),
+	}
+	if overview.Request.ReviewedAt != nil {
+		request.ReviewedAt = overview.Request.ReviewedAt.Format(time.RFC3339)
+	}
+	for i, event := range overview.History {
+		request.History[i] = dto.VerificationEventDTO{
+			Status:    string(event.Status),
+			Reason:    event.Reason,
+			CreatedAt: event.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	response.Request = request
+	return response
+}
+
+// @Summary      Get Verification Status
+// @Description  Returns whether the user is verified and their latest verification request: its status (`submitted`, `delivering`, `in_review`, `approved`, `rejected` or `resubmit`), the reason given when it was rejected or new documents were asked for, and its status history. Who reviewed the request isn't shown.
+// @Tags         Tribute
+// @Produce      json
+// @Security     TgAuth
+// @Success      200  {object}  dto.VerificationResponse  "Success - The user's verification status."
+// @Failure      401  {object}  dto.ErrorResponse         "Unauthorized - The Authorization header is missing or invalid."
+// @Failure      403  {object}  dto.ErrorResponse         "Forbidden - The provided initData is invalid or expired."
+// @Failure      404  {object}  dto.ErrorResponse         "Not Found - User not found."
+// @Failure      500  {object}  dto.ErrorResponse         "Internal Server Error - An unexpected error occurred."
+// @Router       /verification [get]
+func (h *TributeHandler) GetVerification(c *gin.Context) {
+	userID, ok := authenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	overview, err := h.service.GetVerification(userID)
+	if err != nil {
+		verificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newVerificationResponse(overview))
+}
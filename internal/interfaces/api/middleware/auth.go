@@ -1,14 +1,24 @@
 package middleware
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 	"tribute-back/internal/infrastructure/auth"
 	"tribute-back/internal/interfaces/api/dto"
 
 	"github.com/gin-gonic/gin"
 )
 
+// InitDataKey is the context key of the validated *auth.ParsedInitData.
+const InitDataKey = "initData"
+
+// ServiceCallerKey is set in the context when the request was signed by one of our services.
+const ServiceCallerKey = "serviceCaller"
+
 // TelegramAuthMiddleware validates the 'Authorization: TgAuth <initData>' header.
 func TelegramAuthMiddleware(authService *auth.TelegramAuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -33,6 +43,42 @@ func TelegramAuthMiddleware(authService *auth.TelegramAuthService) gin.HandlerFu
 
 		// Set the validated user ID in the context for handlers to use.
 		c.Set("userID", parsedData.User.ID)
+		// The rest of initData, e.g. the start_param the Mini App was opened with
+		c.Set(InitDataKey, parsedData)
+		c.Next()
+	}
+}
+
+// TelegramOrServiceAuthMiddleware accepts requests signed by one of our services with the
+// shared secret, and otherwise validates initData like TelegramAuthMiddleware. Signed
+// requests act on behalf of the user they name, so only trusted services may sign them.
+func TelegramOrServiceAuthMiddleware(authService *auth.TelegramAuthService, serviceAuth *auth.ServiceAuthenticator) gin.HandlerFunc {
+	telegramAuth := TelegramAuthMiddleware(authService)
+	return func(c *gin.Context) {
+		signature := c.GetHeader(auth.ServiceSignatureHeader)
+		if signature == "" {
+			telegramAuth(c)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Failed to read request body"})
+			return
+		}
+		// The handler binds the body again
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		err = serviceAuth.VerifyRequest(c.Request.Context(), c.GetHeader(auth.ServiceTimestampHeader), c.GetHeader(auth.ServiceNonceHeader), signature, body, time.Now())
+		switch {
+		case errors.Is(err, auth.ErrInvalidSignature) || errors.Is(err, auth.ErrReplayedRequest):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to verify request signature"})
+			return
+		}
+		c.Set(ServiceCallerKey, true)
 		c.Next()
 	}
 }
@@ -1,15 +1,22 @@
 package server
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
+	"sync"
+	"time"
 	"tribute-back/internal/application/services"
 	"tribute-back/internal/config"
 	"tribute-back/internal/infrastructure/auth"
 	"tribute-back/internal/infrastructure/database/postgres"
+	"tribute-back/internal/infrastructure/documents"
+	"tribute-back/internal/infrastructure/payments"
 	"tribute-back/internal/infrastructure/payouts"
 	"tribute-back/internal/infrastructure/telegram"
+	"tribute-back/internal/infrastructure/vault"
 	"tribute-back/internal/interfaces/api/handlers"
 	"tribute-back/internal/interfaces/api/middleware"
 
@@ -20,8 +27,23 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewServer(db *sql.DB, redisClient *redis.Client) *gin.Engine {
+// shutdownTimeout bounds how long in-flight requests get to finish on shutdown.
+const shutdownTimeout = 15 * time.Second
+
+// Server bundles the HTTP router with the background workers that share its services.
+type Server struct {
+	router  *gin.Engine
+	workers []func(ctx context.Context)
+}
+
+func NewServer(db *sql.DB, redisClient *redis.Client) *Server {
 	router := gin.Default()
+	// Client IPs key admin alert throttling and partner audits, so X-Forwarded-For is only
+	// believed when it was set by one of our own proxies
+	if err := router.SetTrustedProxies(config.GetListEnv("TRUSTED_PROXIES", nil)); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES: ", err)
+	}
+	srv := &Server{router: router}
 
 	// CORS
 	router.Use(cors.New(cors.Config{
@@ -46,28 +68,194 @@ func NewServer(db *sql.DB, redisClient *redis.Client) *gin.Engine {
 	if err != nil {
 		log.Fatal("Failed to initialize Telegram Bot Service:", err)
 	}
-	payoutGateway := payouts.NewMockGateway()
+	telegramCfg := config.GetTelegramConfig()
+	var alertThrottle telegram.AlertThrottle
+	if redisClient != nil {
+		alertThrottle = telegram.NewRedisAlertThrottle(redisClient, "alerts:throttle:", telegramCfg.AlertThrottleWindow)
+	} else {
+		log.Println("Redis is unavailable, admin alerts will be throttled per instance")
+		alertThrottle = telegram.NewMemoryAlertThrottle(telegramCfg.AlertThrottleWindow)
+	}
+	serviceAuthCfg := config.GetServiceAuthConfig()
+	var nonces auth.NonceStore
+	if redisClient != nil {
+		nonces = auth.NewRedisNonceStore(redisClient, "auth:nonce:")
+	} else {
+		log.Println("Redis is unavailable, service request nonces will be checked per instance")
+		nonces = auth.NewMemoryNonceStore()
+	}
+	serviceAuth := auth.NewServiceAuthenticator(serviceAuthCfg.Secret, serviceAuthCfg.MaxSkew, nonces)
+	partnerAuthCfg := config.GetPartnerAuthConfig()
+	partnerAuth := auth.NewPartnerAuthenticator(partnerAuthCfg.Secrets, partnerAuthCfg.MaxSkew)
+	payoutCfg := config.GetPayoutConfig()
+	payoutGateway := payouts.NewMockGateway(payouts.MockConfig{
+		FailureMode:     payouts.MockFailureMode(payoutCfg.MockFailureMode),
+		ProcessingPolls: payoutCfg.MockProcessingPolls,
+	})
+	paymentsCfg := config.GetPaymentsConfig()
+	simulator := payments.NewSimulator(paymentsCfg.SimulatorWebhookSecret, paymentsCfg.SimulatorDelay)
+	paymentProviders, err := payments.NewRegistry(paymentsCfg.Provider, payments.NewTelegramProvider(botService), simulator)
+	if err != nil {
+		log.Fatal("Failed to initialize payment providers: ", err)
+	}
 
 	// Repositories
 	userRepo := postgres.NewPgUserRepository(db)
 	channelRepo := postgres.NewPgChannelRepository(db)
 	subRepo := postgres.NewPgSubscriptionRepository(db)
+	tierPriceRepo := postgres.NewPgTierPriceRepository(db)
 	paymentRepo := postgres.NewPgPaymentRepository(db)
+	refundRepo := postgres.NewPgRefundRepository(db)
+	promoCodeRepo := postgres.NewPgPromoCodeRepository(db)
+	startLinkRepo := postgres.NewPgStartLinkRepository(db)
+	referralRepo := postgres.NewPgReferralRepository(db)
+	membershipRepo := postgres.NewPgMembershipRepository(db)
+	payoutRepo := postgres.NewPgPayoutRepository(db)
+	partnerRequestRepo := postgres.NewPgPartnerRequestRepository(db)
+	verificationRepo := postgres.NewPgVerificationRepository(db)
+	vaultRepo := postgres.NewPgVaultRepository(db)
+	ledgerRepo := postgres.NewPgLedgerRepository(db)
 
 	// Application Services
-	tributeService := services.NewTributeService(userRepo, channelRepo, subRepo, paymentRepo, botService, payoutGateway)
+	billingCfg := config.GetBillingConfig()
+	channelCheckCfg := config.GetChannelCheckConfig()
+	ledgerService, err := services.NewLedgerService(ledgerRepo, billingCfg.CommissionBPS)
+	if err != nil {
+		log.Fatal("Failed to initialize Ledger Service: ", err)
+	}
+	payoutPolicy, err := services.NewPayoutPolicy(payoutCfg)
+	if err != nil {
+		log.Fatal("Invalid payout configuration: ", err)
+	}
+	verificationCfg := config.GetVerificationConfig()
+	documentStore, err := documents.NewLocalStore(verificationCfg.DocumentsDir)
+	if err != nil {
+		log.Fatal("Failed to initialize document store: ", err)
+	}
+	vaultCfg := config.GetVaultConfig()
+	cardVault, err := vault.New(vaultRepo, vaultCfg.Keys, vaultCfg.ActiveKey)
+	if err != nil {
+		log.Fatal("Failed to initialize vault (check VAULT_KEYS and VAULT_ACTIVE_KEY): ", err)
+	}
+	tributeService := services.NewTributeService(userRepo, channelRepo, subRepo, tierPriceRepo, paymentRepo, refundRepo, promoCodeRepo, startLinkRepo, referralRepo, membershipRepo, payoutRepo, partnerRequestRepo, verificationRepo, ledgerService, botService, alertThrottle, paymentProviders, payoutGateway, payoutPolicy, cardVault, documentStore, partnerAuth, billingCfg, channelCheckCfg)
+
+	// Card numbers saved before the vault existed must not stay in plaintext
+	if migrated, err := tributeService.MigrateLegacyCards(); err != nil {
+		log.Println("Failed to move legacy card numbers into the vault:", err)
+	} else if migrated > 0 {
+		log.Printf("Moved %d legacy card numbers into the vault", migrated)
+	}
+	updateDispatcher := services.NewUpdateDispatcher(tributeService, botService)
+	simulator.SetNotifier(func(event *payments.Event) {
+		if err := tributeService.HandlePaymentEvent(payments.SimulatorProviderName, event); err != nil {
+			log.Printf("Failed to handle simulator event for charge %s: %v", event.ChargeID, err)
+		}
+	})
 
 	// Handlers
 	tributeHandler := handlers.NewTributeHandler(tributeService)
+	telegramHandler := handlers.NewTelegramHandler(updateDispatcher, telegramCfg.WebhookSecret)
+	paymentHandler := handlers.NewPaymentHandler(tributeService, paymentProviders)
+
+	// Telegram updates arrive either through the webhook route below or through the poller
+	switch telegramCfg.UpdatesMode {
+	case config.TelegramUpdatesPolling:
+		var offsets telegram.OffsetStore
+		if redisClient != nil {
+			offsets = telegram.NewRedisOffsetStore(redisClient, "telegram:updates:offset")
+		} else {
+			log.Println("Redis is unavailable, Telegram update offset will not survive restarts")
+			offsets = telegram.NewMemoryOffsetStore()
+		}
+		poller := telegram.NewPoller(botService, offsets, updateDispatcher.Dispatch, telegramCfg.PollTimeout)
+		srv.workers = append(srv.workers, poller.Run)
+	case config.TelegramUpdatesWebhook:
+		if telegramCfg.WebhookURL != "" {
+			if err := botService.SetWebhook(telegramCfg.WebhookURL, telegramCfg.WebhookSecret); err != nil {
+				log.Println("Failed to register Telegram webhook:", err)
+			}
+		}
+	default:
+		log.Fatalf("Unknown TELEGRAM_UPDATES_MODE %q", telegramCfg.UpdatesMode)
+	}
+
+	// Scheduled jobs
+	srv.workers = append(srv.workers, every("expire-memberships", config.GetDurationEnv("MEMBERSHIP_EXPIRY_INTERVAL", time.Minute), func(now time.Time) error {
+		expired, err := tributeService.ExpireMemberships(now)
+		if expired > 0 {
+			log.Printf("Expired %d memberships", expired)
+		}
+		return err
+	}))
+	srv.workers = append(srv.workers, every("process-trials", config.GetDurationEnv("TRIAL_CHECK_INTERVAL", 5*time.Minute), func(now time.Time) error {
+		reminded, err := tributeService.ProcessTrials(now)
+		if reminded > 0 {
+			log.Printf("Reminded %d subscribers about ending trials", reminded)
+		}
+		return err
+	}))
+	srv.workers = append(srv.workers, every("process-renewals", config.GetDurationEnv("RENEWAL_CHECK_INTERVAL", 5*time.Minute), func(now time.Time) error {
+		invoiced, err := tributeService.ProcessRenewals(now)
+		if invoiced > 0 {
+			log.Printf("Invoiced %d subscribers for their next period", invoiced)
+		}
+		return err
+	}))
+	srv.workers = append(srv.workers, every("process-payouts", payoutCfg.Interval, func(now time.Time) error {
+		settled, err := tributeService.ProcessPayouts(now)
+		if settled > 0 {
+			log.Printf("Settled %d payouts", settled)
+		}
+		return err
+	}))
+	srv.workers = append(srv.workers, every("rotate-vault-keys", vaultCfg.RotationInterval, func(now time.Time) error {
+		rotated, err := tributeService.RotateVaultKeys()
+		if rotated > 0 {
+			log.Printf("Re-wrapped %d vault records with key %s", rotated, vaultCfg.ActiveKey)
+		}
+		return err
+	}))
+	srv.workers = append(srv.workers, every("check-channels", channelCheckCfg.Interval, func(now time.Time) error {
+		verified, err := tributeService.RetryChannelChecks(now)
+		if verified > 0 {
+			log.Printf("Verified %d pending channels", verified)
+		}
+		return err
+	}))
+	srv.workers = append(srv.workers, every("deliver-verifications", verificationCfg.DeliveryInterval, func(now time.Time) error {
+		delivered, err := tributeService.DeliverVerifications()
+		if delivered > 0 {
+			log.Printf("Sent %d verification requests to the admin chat", delivered)
+		}
+		return err
+	}))
+	srv.workers = append(srv.workers, every("verify-ledger", config.GetDurationEnv("LEDGER_CHECK_INTERVAL", time.Hour), func(now time.Time) error {
+		err := ledgerService.VerifyBalanced()
+		if err != nil {
+			if alertErr := botService.SendAdminMessage("⚠️ Ledger check failed: " + err.Error()); alertErr != nil {
+				log.Println("Failed to send ledger alert:", alertErr)
+			}
+		}
+		return err
+	}))
 
 	// Development endpoint - reset database
 	router.GET("/api/v1/reset-database", tributeHandler.ResetDatabase)
 
-	// Public webhook for Telegram
+	// KYC provider callback, signed per partner
 	router.POST("/api/v1/check-verified-passport", tributeHandler.CheckVerifiedPassport)
 
-	// Public endpoint for adding bot (no auth required)
-	router.POST("/api/v1/add-bot", tributeHandler.AddBot)
+	// Telegram Bot API webhook, authenticated by the secret token header
+	router.POST("/api/v1/telegram/webhook", telegramHandler.Webhook)
+
+	// Payment provider webhooks, authenticated by each provider
+	router.POST("/api/v1/payments/webhook/:provider", paymentHandler.Webhook)
+
+	// Adding a channel, either from the Mini App or signed by our bot process
+	router.POST("/api/v1/add-bot", middleware.TelegramOrServiceAuthMiddleware(tgAuthService, serviceAuth), tributeHandler.AddBot)
+
+	// Public subscribe page of a channel, looked up by username
+	router.GET("/api/v1/channels/:id/offer", tributeHandler.GetChannelOffer)
 
 	// Protected routes
 	api := router.Group("/api/v1")
@@ -79,9 +267,34 @@ func NewServer(db *sql.DB, redisClient *redis.Client) *gin.Engine {
 		api.GET("/channel-list", tributeHandler.GetChannelList)
 		api.POST("/check-channel", tributeHandler.CheckChannel)
 		api.POST("/upload-verified-passport", tributeHandler.UploadVerifiedPassport)
+		api.GET("/verification", tributeHandler.GetVerification)
 		api.POST("/set-up-payouts", tributeHandler.SetUpPayouts)
 		api.PUT("/publish-subscription", tributeHandler.PublishSubscription)
 		api.POST("/create-subscribe", tributeHandler.CreateSubscribe)
+		api.POST("/start-trial", tributeHandler.StartTrial)
+		api.GET("/my-subscriptions", tributeHandler.GetMySubscriptions)
+		api.POST("/my-subscriptions/:id/cancel", tributeHandler.CancelMySubscription)
+		api.GET("/channels/:id/tiers", tributeHandler.GetChannelTiers)
+		api.POST("/channels/:id/tiers", tributeHandler.CreateTier)
+		api.PUT("/tiers/:id", tributeHandler.UpdateTier)
+		api.DELETE("/tiers/:id", tributeHandler.DeleteTier)
+		api.POST("/tiers/:id/prices", tributeHandler.AddTierPrice)
+		api.PUT("/prices/:id", tributeHandler.UpdateTierPrice)
+		api.DELETE("/prices/:id", tributeHandler.DeleteTierPrice)
+		api.GET("/channels/:id/promo-codes", tributeHandler.GetPromoCodes)
+		api.POST("/channels/:id/promo-codes", tributeHandler.CreatePromoCode)
+		api.PUT("/promo-codes/:id", tributeHandler.UpdatePromoCode)
+		api.DELETE("/promo-codes/:id", tributeHandler.DeletePromoCode)
+		api.GET("/payments/received", tributeHandler.GetReceivedPayments)
+		api.POST("/payments/:id/refunds", tributeHandler.RefundPayment)
+		api.GET("/channels/:id/start-links", tributeHandler.GetStartLinks)
+		api.POST("/channels/:id/start-links", tributeHandler.CreateStartLink)
+		api.DELETE("/start-links/:id", tributeHandler.DeleteStartLink)
+		api.PUT("/channels/:id/referral-program", tributeHandler.SetReferralProgram)
+		api.POST("/channels/:id/referral-link", tributeHandler.GetReferralLink)
+		api.GET("/start-offer", tributeHandler.GetStartOffer)
+		api.POST("/payouts", tributeHandler.RequestPayout)
+		api.GET("/payouts", tributeHandler.GetPayouts)
 	}
 
 	// Swagger - no test routes needed anymore
@@ -90,5 +303,47 @@ func NewServer(db *sql.DB, redisClient *redis.Client) *gin.Engine {
 		router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	}
 
-	return router
+	return srv
+}
+
+// Run serves HTTP on addr and runs the background workers until ctx is cancelled
+// or the listener fails, then shuts both down gracefully.
+func (s *Server) Run(ctx context.Context, addr string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, worker := range s.workers {
+		wg.Add(1)
+		go func(worker func(ctx context.Context)) {
+			defer wg.Done()
+			worker(ctx)
+		}(worker)
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: s.router}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		log.Println("Shutting down server...")
+	case err = <-serveErr:
+	}
+	cancel()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	if shutdownErr := httpServer.Shutdown(shutdownCtx); err == nil {
+		err = shutdownErr
+	}
+	wg.Wait()
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// every returns a background worker that calls job once per interval until the
+// context is cancelled. Errors are logged and the job runs again on the next tick.
+func every(name string, interval time.Duration, job func(now time.Time) error) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := job(now); err != nil {
+					log.Printf("Scheduled job %s failed: %v", name, err)
+				}
+			}
+		}
+	}
+}
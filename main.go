@@ -24,7 +24,11 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"tribute-back/internal/config"
 	"tribute-back/internal/database"
@@ -62,11 +66,15 @@ func main() {
 		defer redisClient.Close()
 	}
 
+	// Stop gracefully on Ctrl+C or SIGTERM from the container runtime
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Create and run server
 	app := server.NewServer(db, redisClient)
 	addr := ":" + config.GetEnv("PORT", "8081")
 	log.Printf("Server starting on %s", addr)
-	if err := app.Run(addr); err != nil {
+	if err := app.Run(ctx, addr); err != nil {
 		log.Fatal("Error starting server:", err)
 	}
 }
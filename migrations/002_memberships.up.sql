@@ -0,0 +1,17 @@
+-- Memberships record that a subscriber has access to a creator's channel
+-- through a subscription tier until the end of the current billing period.
+
+CREATE TABLE IF NOT EXISTS memberships (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscriber_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    channel_id UUID NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    status VARCHAR(32) NOT NULL DEFAULT 'active',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    current_period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    cancelled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_memberships_subscriber_id ON memberships(subscriber_id);
+CREATE INDEX IF NOT EXISTS idx_memberships_subscription_id ON memberships(subscription_id);
+CREATE INDEX IF NOT EXISTS idx_memberships_status_period_end ON memberships(status, current_period_end);
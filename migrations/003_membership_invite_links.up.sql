@@ -0,0 +1,3 @@
+-- Keep the one-time invite link issued to the subscriber so it can be shown again
+-- and revoked when access ends.
+ALTER TABLE memberships ADD COLUMN IF NOT EXISTS invite_link TEXT;
@@ -0,0 +1,10 @@
+ALTER TABLE IF EXISTS subscriptions DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE IF EXISTS subscriptions RENAME COLUMN price_amount TO price;
+ALTER TABLE IF EXISTS subscriptions ALTER COLUMN price TYPE NUMERIC(10, 2) USING price / 100.0;
+
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS earned_currency;
+ALTER TABLE IF EXISTS users RENAME COLUMN earned_amount TO earned;
+ALTER TABLE IF EXISTS users ALTER COLUMN earned DROP NOT NULL;
+ALTER TABLE IF EXISTS users ALTER COLUMN earned DROP DEFAULT;
+ALTER TABLE IF EXISTS users ALTER COLUMN earned TYPE NUMERIC(10, 2) USING earned / 100.0;
+ALTER TABLE IF EXISTS users ALTER COLUMN earned SET DEFAULT 0.00;
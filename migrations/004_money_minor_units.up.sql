@@ -0,0 +1,13 @@
+-- Store money as integer minor units with an explicit ISO 4217 currency.
+-- Existing NUMERIC(10, 2) values are treated as RUB.
+
+ALTER TABLE users ALTER COLUMN earned DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN earned TYPE BIGINT USING ROUND(COALESCE(earned, 0) * 100)::BIGINT;
+ALTER TABLE users ALTER COLUMN earned SET DEFAULT 0;
+ALTER TABLE users ALTER COLUMN earned SET NOT NULL;
+ALTER TABLE users RENAME COLUMN earned TO earned_amount;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS earned_currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE subscriptions ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100)::BIGINT;
+ALTER TABLE subscriptions RENAME COLUMN price TO price_amount;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS price_currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
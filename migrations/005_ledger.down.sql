@@ -0,0 +1,23 @@
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS earned_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS earned_currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+
+-- Restore users.earned from creator balances (one currency per user)
+DO $$
+BEGIN
+    IF to_regclass('users') IS NOT NULL AND to_regclass('postings') IS NOT NULL THEN
+        UPDATE users u SET earned_amount = b.total, earned_currency = b.currency
+        FROM (
+            SELECT DISTINCT ON (a.owner_id) a.owner_id, a.currency, SUM(p.amount) AS total
+            FROM ledger_accounts a
+            JOIN postings p ON p.account_id = a.id
+            WHERE a.account_type = 'creator_balance'
+            GROUP BY a.owner_id, a.currency
+            ORDER BY a.owner_id, total DESC
+        ) b
+        WHERE u.user_id = b.owner_id;
+    END IF;
+END $$;
+
+DROP TABLE IF EXISTS postings CASCADE;
+DROP TABLE IF EXISTS journal_entries CASCADE;
+DROP TABLE IF EXISTS ledger_accounts CASCADE;
@@ -0,0 +1,62 @@
+-- Double-entry ledger. Every journal entry's postings sum to zero per currency;
+-- a positive posting credits an account, a negative one debits it.
+
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_type VARCHAR(32) NOT NULL,
+    owner_id BIGINT REFERENCES users(user_id) ON DELETE RESTRICT,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_type_owner_currency
+    ON ledger_accounts(account_type, (COALESCE(owner_id, 0)), currency);
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(64) NOT NULL,
+    reference VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- An event (e.g. a payment) is recorded at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_kind_reference ON journal_entries(kind, reference);
+
+CREATE TABLE IF NOT EXISTS postings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE RESTRICT,
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id) ON DELETE RESTRICT,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_postings_entry_id ON postings(entry_id);
+CREATE INDEX IF NOT EXISTS idx_postings_account_id ON postings(account_id);
+
+-- Carry over balances stored in users.earned as opening balance entries
+INSERT INTO ledger_accounts (account_type, owner_id, currency)
+SELECT 'creator_balance', user_id, earned_currency FROM users WHERE earned_amount <> 0;
+
+INSERT INTO ledger_accounts (account_type, owner_id, currency)
+SELECT DISTINCT 'opening_balance', NULL::BIGINT, earned_currency FROM users WHERE earned_amount <> 0;
+
+INSERT INTO journal_entries (kind, reference, description)
+SELECT 'opening_balance', 'user:' || user_id, 'Balance carried over from users.earned' FROM users WHERE earned_amount <> 0;
+
+INSERT INTO postings (entry_id, account_id, amount, currency)
+SELECT je.id, la.id, u.earned_amount, u.earned_currency
+FROM users u
+JOIN journal_entries je ON je.kind = 'opening_balance' AND je.reference = 'user:' || u.user_id
+JOIN ledger_accounts la ON la.account_type = 'creator_balance' AND la.owner_id = u.user_id AND la.currency = u.earned_currency
+WHERE u.earned_amount <> 0
+UNION ALL
+SELECT je.id, la.id, -u.earned_amount, u.earned_currency
+FROM users u
+JOIN journal_entries je ON je.kind = 'opening_balance' AND je.reference = 'user:' || u.user_id
+JOIN ledger_accounts la ON la.account_type = 'opening_balance' AND la.owner_id IS NULL AND la.currency = u.earned_currency
+WHERE u.earned_amount <> 0;
+
+-- Earnings are now derived from the ledger
+ALTER TABLE users DROP COLUMN IF EXISTS earned_amount;
+ALTER TABLE users DROP COLUMN IF EXISTS earned_currency;
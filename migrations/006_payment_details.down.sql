@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_payments_provider_charge_id;
+DROP INDEX IF EXISTS idx_payments_status;
+DROP INDEX IF EXISTS idx_payments_creator_id;
+
+ALTER TABLE IF EXISTS payments ALTER COLUMN created_date DROP NOT NULL;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS failure_reason;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS provider_charge_id;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS status;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS currency;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS amount;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS subscription_id;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS creator_id;
+
+ALTER INDEX IF EXISTS idx_payments_payer_id RENAME TO idx_payments_user_id;
+ALTER TABLE IF EXISTS payments RENAME COLUMN payer_id TO user_id;
@@ -0,0 +1,43 @@
+-- Payments record who paid whom, how much, for which tier and how it went.
+
+ALTER TABLE payments RENAME COLUMN user_id TO payer_id;
+ALTER INDEX IF EXISTS idx_payments_user_id RENAME TO idx_payments_payer_id;
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS creator_id BIGINT REFERENCES users(user_id) ON DELETE SET NULL;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS subscription_id UUID REFERENCES subscriptions(id) ON DELETE SET NULL;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+-- Every payment recorded so far granted access, so existing rows count as succeeded
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'succeeded'
+    CHECK (status IN ('pending', 'succeeded', 'failed', 'refunded'));
+ALTER TABLE payments ALTER COLUMN status SET DEFAULT 'pending';
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS provider_charge_id VARCHAR(255);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS failure_reason TEXT;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE payments SET created_date = CURRENT_TIMESTAMP WHERE created_date IS NULL;
+ALTER TABLE payments ALTER COLUMN created_date SET NOT NULL;
+UPDATE payments SET updated_at = created_date;
+
+-- The creator was only recorded in the description ("Subscription to user <id>")
+UPDATE payments p SET creator_id = m.creator_id
+FROM (
+    SELECT id, substring(description FROM '^Subscription to user (\d+)$')::BIGINT AS creator_id
+    FROM payments
+) m
+WHERE p.id = m.id
+  AND m.creator_id IS NOT NULL
+  AND EXISTS (SELECT 1 FROM users u WHERE u.user_id = m.creator_id);
+
+-- Amount and tier come from the creator's tier, which is what the subscriber had to pay
+UPDATE payments p SET subscription_id = s.id, amount = s.price_amount, currency = s.price_currency
+FROM (
+    SELECT DISTINCT ON (user_id) id, user_id, price_amount, price_currency
+    FROM subscriptions
+    ORDER BY user_id, created_date
+) s
+WHERE p.creator_id = s.user_id;
+
+CREATE INDEX IF NOT EXISTS idx_payments_creator_id ON payments(creator_id);
+CREATE INDEX IF NOT EXISTS idx_payments_status ON payments(status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_provider_charge_id ON payments(provider_charge_id) WHERE provider_charge_id IS NOT NULL;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_payments_provider_charge_id;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS provider;
+
+DO $$
+BEGIN
+    IF to_regclass('payments') IS NOT NULL THEN
+        CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_provider_charge_id ON payments(provider_charge_id) WHERE provider_charge_id IS NOT NULL;
+    END IF;
+END $$;
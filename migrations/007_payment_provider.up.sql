@@ -0,0 +1,8 @@
+-- Payments remember which provider handles them, and charge IDs are only unique per provider.
+-- Payments made so far went through Telegram invoices or predate providers altogether.
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS provider VARCHAR(32) NOT NULL DEFAULT 'telegram';
+ALTER TABLE payments ALTER COLUMN provider DROP DEFAULT;
+
+DROP INDEX IF EXISTS idx_payments_provider_charge_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_provider_charge_id ON payments(provider, provider_charge_id) WHERE provider_charge_id IS NOT NULL;
@@ -0,0 +1,21 @@
+-- Creator withdrawals. The amount is reserved in the ledger when the payout is requested
+-- and released again if it fails.
+
+CREATE TABLE IF NOT EXISTS payouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'requested'
+        CHECK (status IN ('requested', 'processing', 'paid', 'failed')),
+    gateway_payout_id VARCHAR(255),
+    failure_reason TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_payouts_user_id ON payouts(user_id);
+CREATE INDEX IF NOT EXISTS idx_payouts_status ON payouts(status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payouts_gateway_payout_id ON payouts(gateway_payout_id) WHERE gateway_payout_id IS NOT NULL;
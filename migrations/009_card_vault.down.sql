@@ -0,0 +1,9 @@
+-- Encrypted card numbers can't be restored into users.card_number without the keys;
+-- users will have to set up payouts again.
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS card_exp_year;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS card_exp_month;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS card_brand;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS card_last4;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS card_token;
+
+DROP TABLE IF EXISTS vault_records CASCADE;
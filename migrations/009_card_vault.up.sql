@@ -0,0 +1,20 @@
+-- Card numbers are kept encrypted in vault_records; users only keep a token and
+-- what is safe to display. Existing plaintext card_number values are moved into
+-- the vault by the application on startup (it holds the keys) and then cleared.
+
+CREATE TABLE IF NOT EXISTS vault_records (
+    token VARCHAR(64) PRIMARY KEY,
+    key_id VARCHAR(64) NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    ciphertext BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vault_records_key_id ON vault_records(key_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS card_token VARCHAR(64) REFERENCES vault_records(token) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS card_last4 VARCHAR(4);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS card_brand VARCHAR(16);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS card_exp_month SMALLINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS card_exp_year SMALLINT;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_subscriptions_channel_id_unique;
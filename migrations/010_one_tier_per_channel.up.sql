@@ -0,0 +1,29 @@
+-- Each channel has a single subscription tier. Earlier versions could create
+-- several tiers for a creator's first channel; keep the oldest one per channel
+-- and move memberships and payments that refer to the others onto it.
+
+WITH ranked AS (
+    SELECT id, FIRST_VALUE(id) OVER (PARTITION BY channel_id ORDER BY created_date, id) AS keep_id
+    FROM subscriptions
+)
+UPDATE memberships m SET subscription_id = r.keep_id
+FROM ranked r
+WHERE m.subscription_id = r.id AND r.id <> r.keep_id;
+
+WITH ranked AS (
+    SELECT id, FIRST_VALUE(id) OVER (PARTITION BY channel_id ORDER BY created_date, id) AS keep_id
+    FROM subscriptions
+)
+UPDATE payments p SET subscription_id = r.keep_id
+FROM ranked r
+WHERE p.subscription_id = r.id AND r.id <> r.keep_id;
+
+WITH ranked AS (
+    SELECT id, FIRST_VALUE(id) OVER (PARTITION BY channel_id ORDER BY created_date, id) AS keep_id
+    FROM subscriptions
+)
+DELETE FROM subscriptions s
+USING ranked r
+WHERE s.id = r.id AND r.id <> r.keep_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_channel_id_unique ON subscriptions(channel_id);
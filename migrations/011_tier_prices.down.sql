@@ -0,0 +1,32 @@
+ALTER TABLE IF EXISTS memberships DROP COLUMN IF EXISTS price_id;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS price_id;
+
+ALTER TABLE IF EXISTS subscriptions ADD COLUMN IF NOT EXISTS price_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE IF EXISTS subscriptions ADD COLUMN IF NOT EXISTS price_currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+
+DO $$
+BEGIN
+    IF to_regclass('memberships') IS NOT NULL THEN
+        -- Lifetime access is approximated with a long period
+        UPDATE memberships SET current_period_end = started_at + INTERVAL '100 years' WHERE current_period_end IS NULL;
+        ALTER TABLE memberships ALTER COLUMN current_period_end SET NOT NULL;
+    END IF;
+
+    -- Keep one price per tier, preferring the monthly one
+    IF to_regclass('tier_prices') IS NOT NULL THEN
+        UPDATE subscriptions s SET price_amount = tp.amount, price_currency = tp.currency
+        FROM (
+            SELECT DISTINCT ON (subscription_id) subscription_id, amount, currency
+            FROM tier_prices
+            WHERE archived_at IS NULL
+            ORDER BY subscription_id, (billing_interval = 'month') DESC, sort_order, created_date
+        ) tp
+        WHERE s.id = tp.subscription_id;
+    END IF;
+END $$;
+
+DROP TABLE IF EXISTS tier_prices CASCADE;
+
+ALTER TABLE IF EXISTS subscriptions DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE IF EXISTS subscriptions DROP COLUMN IF EXISTS sort_order;
+DROP INDEX IF EXISTS idx_subscriptions_channel_id;
@@ -0,0 +1,45 @@
+-- Channels can offer several subscription tiers, and each tier is sold at one or
+-- more prices with their own billing interval. The single price stored on a tier
+-- becomes its monthly price.
+
+DROP INDEX IF EXISTS idx_subscriptions_channel_id_unique;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_channel_id ON subscriptions(channel_id);
+
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS sort_order INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS tier_prices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    billing_interval VARCHAR(16) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    trial_days INTEGER NOT NULL DEFAULT 0 CHECK (trial_days >= 0),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A tier has at most one live price per interval
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tier_prices_subscription_interval
+    ON tier_prices(subscription_id, billing_interval) WHERE archived_at IS NULL;
+
+INSERT INTO tier_prices (subscription_id, billing_interval, amount, currency, created_date)
+SELECT id, 'month', price_amount, price_currency, COALESCE(created_date, CURRENT_TIMESTAMP)
+FROM subscriptions
+WHERE price_amount > 0;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS price_amount;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS price_currency;
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS price_id UUID REFERENCES tier_prices(id) ON DELETE SET NULL;
+UPDATE payments p SET price_id = tp.id
+FROM tier_prices tp
+WHERE tp.subscription_id = p.subscription_id AND tp.billing_interval = 'month';
+
+-- Lifetime memberships have no period end
+ALTER TABLE memberships ADD COLUMN IF NOT EXISTS price_id UUID REFERENCES tier_prices(id) ON DELETE SET NULL;
+ALTER TABLE memberships ALTER COLUMN current_period_end DROP NOT NULL;
+UPDATE memberships m SET price_id = tp.id
+FROM tier_prices tp
+WHERE tp.subscription_id = m.subscription_id AND tp.billing_interval = 'month';
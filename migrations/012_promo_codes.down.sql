@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payments_promo_code_id;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS promo_code_id;
+DROP TABLE IF EXISTS promo_codes CASCADE;
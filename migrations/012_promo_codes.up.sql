@@ -0,0 +1,29 @@
+-- Promo codes give subscribers a discount on a channel's tiers. Payments record
+-- the code they used and how much it took off; payments that didn't fail count
+-- as redemptions.
+
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    creator_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    channel_id UUID NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    subscription_id UUID REFERENCES subscriptions(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL,
+    discount_type VARCHAR(16) NOT NULL,
+    percent_off INTEGER NOT NULL DEFAULT 0 CHECK (percent_off BETWEEN 0 AND 99),
+    amount_off BIGINT NOT NULL DEFAULT 0 CHECK (amount_off >= 0),
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    max_redemptions INTEGER NOT NULL DEFAULT 0 CHECK (max_redemptions >= 0),
+    per_user_limit INTEGER NOT NULL DEFAULT 0 CHECK (per_user_limit >= 0),
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A channel has at most one live code with the same text
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promo_codes_channel_code
+    ON promo_codes(channel_id, code) WHERE archived_at IS NULL;
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS promo_code_id UUID REFERENCES promo_codes(id) ON DELETE SET NULL;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS discount_amount BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_payments_promo_code_id ON payments(promo_code_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_memberships_one_trial_per_channel;
+ALTER TABLE IF EXISTS memberships DROP COLUMN IF EXISTS trial_reminder_sent_at;
+ALTER TABLE IF EXISTS memberships DROP COLUMN IF EXISTS trial_ends_at;
@@ -0,0 +1,8 @@
+-- Memberships can start with a free trial. trial_ends_at is kept after the trial
+-- is paid for, so each subscriber gets at most one trial per channel.
+
+ALTER TABLE memberships ADD COLUMN IF NOT EXISTS trial_ends_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE memberships ADD COLUMN IF NOT EXISTS trial_reminder_sent_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_memberships_one_trial_per_channel
+    ON memberships(subscriber_id, channel_id) WHERE trial_ends_at IS NOT NULL;
@@ -0,0 +1,24 @@
+-- Refunds return part or all of a succeeded payment to the payer. A payment keeps
+-- the total refunded so far; it only moves to 'refunded' once nothing is left.
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refunded_amount BIGINT NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    source VARCHAR(16) NOT NULL,
+    requested_by BIGINT,
+    idempotency_key VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    reason TEXT,
+    revoke_access BOOLEAN NOT NULL DEFAULT FALSE,
+    provider_refund_id VARCHAR(255),
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Retrying a refund request with the same key must not refund twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_payment_idempotency_key ON refunds(payment_id, idempotency_key);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS start_link_clicks CASCADE;
+DROP TABLE IF EXISTS start_links CASCADE;
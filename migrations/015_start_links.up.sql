@@ -0,0 +1,30 @@
+-- Start links open the Mini App on a channel's offer through t.me/<bot>?startapp=<code>.
+-- Every launch of the Mini App through a link is recorded as a click.
+
+CREATE TABLE IF NOT EXISTS start_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(64) NOT NULL,
+    creator_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    channel_id UUID NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    price_id UUID REFERENCES tier_prices(id) ON DELETE SET NULL,
+    label VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Codes are never reused, so old links don't start pointing elsewhere
+CREATE UNIQUE INDEX IF NOT EXISTS idx_start_links_code ON start_links(code);
+CREATE INDEX IF NOT EXISTS idx_start_links_channel_id ON start_links(channel_id);
+
+CREATE TABLE IF NOT EXISTS start_link_clicks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES start_links(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    chat_type VARCHAR(32),
+    chat_instance VARCHAR(64),
+    auth_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The Mini App sends the same initData with every request of a launch
+CREATE UNIQUE INDEX IF NOT EXISTS idx_start_link_clicks_launch ON start_link_clicks(link_id, user_id, auth_date);
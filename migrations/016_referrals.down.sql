@@ -0,0 +1,6 @@
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS referral_amount;
+ALTER TABLE IF EXISTS payments DROP COLUMN IF EXISTS referral_id;
+DROP TABLE IF EXISTS referrals CASCADE;
+DROP INDEX IF EXISTS idx_start_links_referrer;
+ALTER TABLE IF EXISTS start_links DROP COLUMN IF EXISTS referrer_id;
+ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS referral_share_bps;
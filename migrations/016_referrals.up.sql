@@ -0,0 +1,33 @@
+-- Referral programs: a creator shares part of their earnings from a channel with the users
+-- who bring it subscribers. Referrers share their own start link to the channel's offer; a
+-- subscriber is attributed to the referral link they last opened when they first pay.
+
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS referral_share_bps INT NOT NULL DEFAULT 0
+    CHECK (referral_share_bps >= 0 AND referral_share_bps <= 10000);
+
+-- Referrers don't need to have onboarded, so referrer_id doesn't reference users
+ALTER TABLE start_links ADD COLUMN IF NOT EXISTS referrer_id BIGINT;
+
+-- A referrer has one live link per channel
+CREATE UNIQUE INDEX IF NOT EXISTS idx_start_links_referrer ON start_links(channel_id, referrer_id)
+    WHERE referrer_id IS NOT NULL AND archived_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS referrals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    channel_id UUID NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    subscriber_id BIGINT NOT NULL,
+    referrer_id BIGINT NOT NULL,
+    link_id UUID NOT NULL REFERENCES start_links(id) ON DELETE CASCADE,
+    share_bps INT NOT NULL CHECK (share_bps > 0 AND share_bps <= 10000),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A subscriber is referred to a channel once, by whoever brought them first
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_subscriber ON referrals(channel_id, subscriber_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_link_id ON referrals(link_id);
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS referral_id UUID REFERENCES referrals(id) ON DELETE SET NULL;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS referral_amount BIGINT NOT NULL DEFAULT 0 CHECK (referral_amount >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_payments_referral_id ON payments(referral_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_channels_next_verification_at;
+ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS next_verification_at;
+ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS verification_checked_at;
+ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS verification_attempts;
+ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS missing_permissions;
@@ -0,0 +1,13 @@
+-- Channels that fail verification are kept pending instead of being deleted, with what is
+-- missing in Telegram, and checked again on a schedule.
+
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS missing_permissions TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS verification_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS verification_checked_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS next_verification_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_channels_next_verification_at ON channels(next_verification_at)
+    WHERE NOT is_verified AND next_verification_at IS NOT NULL;
+
+-- Channels added before were never checked again; give them a round of retries
+UPDATE channels SET next_verification_at = CURRENT_TIMESTAMP WHERE NOT is_verified AND next_verification_at IS NULL;
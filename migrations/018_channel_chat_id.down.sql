@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_channels_telegram_chat_id;
+UPDATE channels SET channel_username = id::text WHERE channel_username IS NULL;
+ALTER TABLE IF EXISTS channels ALTER COLUMN channel_username SET NOT NULL;
+ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS telegram_chat_id;
@@ -0,0 +1,7 @@
+-- Channels are registered automatically when the bot is made an administrator, and are
+-- addressed by their Telegram chat ID once it is known. Private channels have no username.
+
+ALTER TABLE channels ADD COLUMN IF NOT EXISTS telegram_chat_id BIGINT;
+ALTER TABLE channels ALTER COLUMN channel_username DROP NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_channels_telegram_chat_id ON channels(telegram_chat_id) WHERE telegram_chat_id IS NOT NULL;
@@ -0,0 +1,16 @@
+-- Callbacks from integration partners, e.g. the KYC provider, are signed per partner. Every
+-- attempt is kept for audit; an accepted signature can't be used again.
+
+CREATE TABLE IF NOT EXISTS partner_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner TEXT NOT NULL,
+    endpoint TEXT NOT NULL,
+    remote_ip TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    accepted BOOLEAN NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_partner_requests_signature ON partner_requests(signature) WHERE accepted;
+CREATE INDEX IF NOT EXISTS idx_partner_requests_created_at ON partner_requests(created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS verification_events CASCADE;
+DROP TABLE IF EXISTS verification_requests CASCADE;
@@ -0,0 +1,29 @@
+-- Identity verification requests are kept with their documents and every change of status,
+-- instead of only living as messages in the admin chat.
+
+CREATE TABLE IF NOT EXISTS verification_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL,
+    photo_key TEXT NOT NULL,
+    passport_key TEXT NOT NULL,
+    reviewer TEXT,
+    reason TEXT,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_requests_user_id ON verification_requests(user_id, submitted_at);
+CREATE INDEX IF NOT EXISTS idx_verification_requests_status ON verification_requests(status);
+
+CREATE TABLE IF NOT EXISTS verification_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    request_id UUID NOT NULL REFERENCES verification_requests(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL,
+    reviewer TEXT,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_events_request_id ON verification_events(request_id, created_at);
@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS memberships DROP COLUMN IF EXISTS payment_id;
@@ -0,0 +1,4 @@
+-- Memberships record the payment that funded their current period, so a payment that is
+-- reported again after a partial failure doesn't extend the membership a second time.
+
+ALTER TABLE memberships ADD COLUMN IF NOT EXISTS payment_id UUID REFERENCES payments(id);
@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS memberships DROP COLUMN IF EXISTS renewal_invoiced_at;
@@ -0,0 +1,3 @@
+-- Active memberships are invoiced for their next period before the current one ends.
+
+ALTER TABLE memberships ADD COLUMN IF NOT EXISTS renewal_invoiced_at TIMESTAMP WITH TIME ZONE;
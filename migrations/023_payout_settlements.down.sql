@@ -0,0 +1,2 @@
+DELETE FROM postings WHERE entry_id IN (SELECT id FROM journal_entries WHERE kind = 'payout_settlement');
+DELETE FROM journal_entries WHERE kind = 'payout_settlement';
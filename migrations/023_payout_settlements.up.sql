@@ -0,0 +1,25 @@
+-- Paid payouts are settled in the ledger: the amount leaves payouts_clearing and the funds
+-- received from subscribers. Settle the payouts that were paid before that was recorded.
+
+INSERT INTO ledger_accounts (account_type, owner_id, currency)
+SELECT DISTINCT 'payments_clearing', NULL::BIGINT, currency FROM payouts WHERE status = 'paid'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO journal_entries (kind, reference, description)
+SELECT 'payout_settlement', p.id::TEXT, 'Settlement of payout to user ' || p.user_id
+FROM payouts p
+WHERE p.status = 'paid'
+ON CONFLICT (kind, reference) DO NOTHING;
+
+INSERT INTO postings (entry_id, account_id, amount, currency)
+SELECT je.id, la.id, -p.amount, p.currency
+FROM payouts p
+JOIN journal_entries je ON je.kind = 'payout_settlement' AND je.reference = p.id::TEXT
+JOIN ledger_accounts la ON la.account_type = 'payouts_clearing' AND la.owner_id IS NULL AND la.currency = p.currency
+WHERE p.status = 'paid' AND NOT EXISTS (SELECT 1 FROM postings WHERE entry_id = je.id)
+UNION ALL
+SELECT je.id, la.id, p.amount, p.currency
+FROM payouts p
+JOIN journal_entries je ON je.kind = 'payout_settlement' AND je.reference = p.id::TEXT
+JOIN ledger_accounts la ON la.account_type = 'payments_clearing' AND la.owner_id IS NULL AND la.currency = p.currency
+WHERE p.status = 'paid' AND NOT EXISTS (SELECT 1 FROM postings WHERE entry_id = je.id);
@@ -0,0 +1,63 @@
+// Package migrations embeds the SQL migrations so the application can apply them itself.
+// The same files are used by golang-migrate (see Makefile).
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Up applies every up migration in version order.
+func Up(db *sql.DB) error {
+	names, err := list(".up.sql")
+	if err != nil {
+		return err
+	}
+	return run(db, names)
+}
+
+// Down reverts every migration, newest first.
+func Down(db *sql.DB) error {
+	names, err := list(".down.sql")
+	if err != nil {
+		return err
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return run(db, names)
+}
+
+func list(suffix string) ([]string, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), suffix) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func run(db *sql.DB, names []string) error {
+	for _, name := range names {
+		query, err := files.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(string(query)); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", name, err)
+		}
+	}
+	return nil
+}